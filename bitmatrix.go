@@ -0,0 +1,221 @@
+package ar8t
+
+import (
+	"errors"
+	"strings"
+)
+
+var errInvalidBitMatrix = errors.New("ar8t: rows of a bit matrix must have the same length")
+
+// BitMatrix is a grid of modules, packed into 64 bit words with every row starting on a new word.
+// A set bit is a dark module. Coordinates outside the matrix read as light modules.
+type BitMatrix struct {
+	width, height int
+	rowWords      int
+	words         []uint64
+}
+
+func NewBitMatrix(width, height int) BitMatrix {
+	rowWords := (width + 63) / 64
+	return BitMatrix{
+		width:    width,
+		height:   height,
+		rowWords: rowWords,
+		words:    make([]uint64, rowWords*height),
+	}
+}
+
+// ParseBitMatrix reads a matrix as rendered by String, '#', 'X' and '1' being dark modules.
+// Empty lines are ignored.
+func ParseBitMatrix(s string) (BitMatrix, error) {
+	rows := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			rows = append(rows, line)
+		}
+	}
+
+	if len(rows) == 0 {
+		return BitMatrix{}, nil
+	}
+
+	m := NewBitMatrix(len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != m.width {
+			return BitMatrix{}, errInvalidBitMatrix
+		}
+
+		for x := 0; x < len(row); x++ {
+			switch row[x] {
+			case '#', 'X', '1':
+				m.Set(x, y, true)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m BitMatrix) Width() int {
+	return m.width
+}
+
+func (m BitMatrix) Height() int {
+	return m.height
+}
+
+func (m BitMatrix) in(x, y int) bool {
+	return x >= 0 && y >= 0 && x < m.width && y < m.height
+}
+
+// Get reports whether the module at x, y is dark
+func (m BitMatrix) Get(x, y int) bool {
+	if !m.in(x, y) {
+		return false
+	}
+	return m.words[y*m.rowWords+x/64]&(1<<(x%64)) != 0
+}
+
+// bit returns the module at x, y as 1 (dark) or 0
+func (m BitMatrix) bit(x, y int) byte {
+	if m.Get(x, y) {
+		return 1
+	}
+	return 0
+}
+
+// Set makes the module at x, y dark or light, coordinates outside the matrix are ignored
+func (m *BitMatrix) Set(x, y int, dark bool) {
+	if !m.in(x, y) {
+		return
+	}
+
+	if dark {
+		m.words[y*m.rowWords+x/64] |= 1 << (x % 64)
+		return
+	}
+	m.words[y*m.rowWords+x/64] &^= 1 << (x % 64)
+}
+
+// Flip inverts the module at x, y
+func (m *BitMatrix) Flip(x, y int) {
+	if !m.in(x, y) {
+		return
+	}
+	m.words[y*m.rowWords+x/64] ^= 1 << (x % 64)
+}
+
+func (m BitMatrix) Clone() BitMatrix {
+	clone := m
+	clone.words = append([]uint64(nil), m.words...)
+	return clone
+}
+
+// Xor returns a new matrix of m XOR other, both must have the same size
+func (m BitMatrix) Xor(other BitMatrix) BitMatrix {
+	res := m.Clone()
+	for i := range res.words {
+		res.words[i] ^= other.words[i]
+	}
+
+	return res
+}
+
+func (m BitMatrix) Equal(other BitMatrix) bool {
+	if m.width != other.width || m.height != other.height {
+		return false
+	}
+
+	for i := range m.words {
+		if m.words[i] != other.words[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Transpose mirrors the matrix along its main diagonal
+func (m BitMatrix) Transpose() BitMatrix {
+	res := NewBitMatrix(m.height, m.width)
+	m.each(func(x, y int) {
+		res.Set(y, x, true)
+	})
+
+	return res
+}
+
+// Rotate turns the matrix clockwise by the given number of quarter turns
+func (m BitMatrix) Rotate(quarterTurns int) BitMatrix {
+	switch (quarterTurns%4 + 4) % 4 {
+	case 1:
+		res := NewBitMatrix(m.height, m.width)
+		m.each(func(x, y int) {
+			res.Set(m.height-1-y, x, true)
+		})
+		return res
+	case 2:
+		res := NewBitMatrix(m.width, m.height)
+		m.each(func(x, y int) {
+			res.Set(m.width-1-x, m.height-1-y, true)
+		})
+		return res
+	case 3:
+		res := NewBitMatrix(m.height, m.width)
+		m.each(func(x, y int) {
+			res.Set(y, m.width-1-x, true)
+		})
+		return res
+	default:
+		return m.Clone()
+	}
+}
+
+// each calls fn for every dark module
+func (m BitMatrix) each(fn func(x, y int)) {
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			if m.Get(x, y) {
+				fn(x, y)
+			}
+		}
+	}
+}
+
+// Row returns the modules of row y, true being dark
+func (m BitMatrix) Row(y int) []bool {
+	row := make([]bool, m.width)
+	for x := range row {
+		row[x] = m.Get(x, y)
+	}
+	return row
+}
+
+// Column returns the modules of column x, true being dark
+func (m BitMatrix) Column(x int) []bool {
+	column := make([]bool, m.height)
+	for y := range column {
+		column[y] = m.Get(x, y)
+	}
+	return column
+}
+
+// String renders the matrix one row per line, '#' for dark and '.' for light modules
+func (m BitMatrix) String() string {
+	sb := strings.Builder{}
+	sb.Grow((m.width + 1) * m.height)
+
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			if m.Get(x, y) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
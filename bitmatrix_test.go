@@ -0,0 +1,54 @@
+package ar8t
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BitMatrix(t *testing.T) {
+	m, err := ParseBitMatrix(`
+		##.
+		.#.
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name string
+		got  BitMatrix
+		want string
+	}{
+		{"identity", m, "##.\n.#.\n"},
+		{"transpose", m.Transpose(), "#.\n##\n..\n"},
+		{"rotate once", m.Rotate(1), ".#\n##\n..\n"},
+		{"rotate twice", m.Rotate(2), ".#.\n.##\n"},
+		{"rotate thrice", m.Rotate(3), "..\n##\n#.\n"},
+		{"rotate back", m.Rotate(-1), m.Rotate(3).String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got.String())
+		})
+	}
+}
+
+func Test_BitMatrix_setFlip(t *testing.T) {
+	m := NewBitMatrix(70, 2)
+
+	m.Set(65, 1, true)
+	m.Flip(0, 0)
+	m.Flip(65, 1)
+	m.Set(100, 100, true)
+
+	assert.True(t, m.Get(0, 0))
+	assert.False(t, m.Get(65, 1))
+	assert.False(t, m.Get(100, 100))
+	assert.Equal(t, []bool{true, false}, m.Column(0))
+	assert.Len(t, m.Row(1), 70)
+
+	_, err := ParseBitMatrix("##\n#")
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+// Command ar8t-server decodes the symbols in images over HTTP
+//
+// Usage:
+//
+//	ar8t-server [flags]
+//
+// POST /decode decodes the images in a multipart/form-data body, every file part being an image,
+// or the whole body as an image otherwise. PNG, JPEG and GIF images are supported.
+// The response is a JSON object such as
+//
+//	{"images": [{"name": "label.png", "status": "ok", "symbols": [{"symbology": "QR Code",
+//	"decoded": true, "data_base64": "aGVsbG8=", "data_utf8": "hello", "corners": [[12, 10], ...],
+//	"version": 1, "ec_level": "M", "mask": 3, "corrected_errors": [0]}]}]}
+//
+// name being the file name of the part, omitted for raw bodies. Every image has a status of ok,
+// no_symbol, undecodable or bad_image, images of more than -max-pixels being bad ones, and the fields
+// of the symbols are those of the json format of the ar8t command. Responses are 200 OK when every image was decoded whatever their status,
+// 400 for a body without images, 413 for a body over -max-bytes, 503 when no image could start
+// decoding before -timeout and 504 when decoding took longer than that, with an error field.
+//
+// GET /healthz answers ok, and GET /metrics the request and image counts by status
+// and the decode latency in the Prometheus text format.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/mrg0lden/ar8t"
+)
+
+func main() {
+	var (
+		addr        = flag.String("addr", ":8080", "the address to listen on")
+		maxBytes    = flag.Int64("max-bytes", 10<<20, "the largest request body accepted, in bytes")
+		maxPixels   = flag.Int64("max-pixels", 50_000_000, "the largest image decoded, in pixels")
+		concurrency = flag.Int("concurrency", runtime.NumCPU(), "the number of images decoded at once")
+		timeout     = flag.Duration("timeout", 10*time.Second, "the longest a request can take")
+		soft        = flag.Bool("soft", false, "sample modules from the grayscale image, slower but better for blurry images")
+	)
+	flag.Parse()
+
+	s := newServer(ar8t.DefaultDecoder{SoftSampling: *soft}, *maxBytes, *maxPixels, *timeout, *concurrency)
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       *timeout,
+	}
+
+	log.Printf("ar8t-server listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
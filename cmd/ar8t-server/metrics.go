@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds of the decode latency histogram, in seconds
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics are counted for the Prometheus text format, see writeTo
+type metrics struct {
+	mu sync.Mutex
+
+	// requests counts the decode requests by status, images the images decoded by status
+	requests map[string]int
+	images   map[string]int
+
+	// buckets count the decodes at most as long as every latency bucket, the last one counting them all
+	buckets  []int
+	sum      float64
+	inFlight int
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests: map[string]int{},
+		images:   map[string]int{},
+		buckets:  make([]int, len(latencyBuckets)+1),
+	}
+}
+
+func (m *metrics) request(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[status]++
+}
+
+func (m *metrics) image(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.images[status]++
+}
+
+// start counts a decode in flight, until the function it returns is called with how long it took
+func (m *metrics) start() func(time.Duration) {
+	m.mu.Lock()
+	m.inFlight++
+	m.mu.Unlock()
+
+	return func(d time.Duration) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.inFlight--
+		seconds := d.Seconds()
+		m.sum += seconds
+		for i, bound := range latencyBuckets {
+			if seconds <= bound {
+				m.buckets[i]++
+			}
+		}
+		m.buckets[len(latencyBuckets)]++
+	}
+}
+
+func (m *metrics) writeTo(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP ar8t_requests_total Decode requests by status.")
+	fmt.Fprintln(w, "# TYPE ar8t_requests_total counter")
+	writeCounts(w, "ar8t_requests_total", m.requests)
+
+	fmt.Fprintln(w, "# HELP ar8t_images_total Images decoded by status, ok being a successful decode.")
+	fmt.Fprintln(w, "# TYPE ar8t_images_total counter")
+	writeCounts(w, "ar8t_images_total", m.images)
+
+	fmt.Fprintln(w, "# HELP ar8t_decode_duration_seconds Time taken to decode an image.")
+	fmt.Fprintln(w, "# TYPE ar8t_decode_duration_seconds histogram")
+	for i, bound := range latencyBuckets {
+		fmt.Fprintf(w, "ar8t_decode_duration_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(bound, 'g', -1, 64), m.buckets[i])
+	}
+	count := m.buckets[len(latencyBuckets)]
+	fmt.Fprintf(w, "ar8t_decode_duration_seconds_bucket{le=\"+Inf\"} %d\n", count)
+	fmt.Fprintf(w, "ar8t_decode_duration_seconds_sum %g\n", m.sum)
+	fmt.Fprintf(w, "ar8t_decode_duration_seconds_count %d\n", count)
+
+	fmt.Fprintln(w, "# HELP ar8t_decodes_in_flight Images being decoded.")
+	fmt.Fprintln(w, "# TYPE ar8t_decodes_in_flight gauge")
+	fmt.Fprintf(w, "ar8t_decodes_in_flight %d\n", m.inFlight)
+}
+
+// writeCounts writes a counter by status, in the order of the statuses
+func writeCounts(w io.Writer, name string, counts map[string]int) {
+	statuses := []string{}
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	for _, status := range statuses {
+		fmt.Fprintf(w, "%s{status=%q} %d\n", name, status, counts[status])
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/mrg0lden/ar8t"
+	"github.com/mrg0lden/ar8t/internal/report"
+)
+
+// the statuses of requests and images in responses and metrics
+const (
+	statusOK          = "ok"
+	statusNoSymbol    = "no_symbol"
+	statusUndecodable = "undecodable"
+	statusBadImage    = "bad_image"
+	statusBadRequest  = "bad_request"
+	statusTooLarge    = "too_large"
+	statusBusy        = "busy"
+	statusTimeout     = "timeout"
+)
+
+// server decodes the images posted to /decode
+type server struct {
+	decoder ar8t.DefaultDecoder
+
+	// maxBytes limits the size of request bodies, maxPixels that of every image
+	// and timeout how long a request takes
+	maxBytes  int64
+	maxPixels int64
+	timeout   time.Duration
+
+	// slots holds a value for every image being decoded, as many as can be at once
+	slots chan struct{}
+
+	metrics *metrics
+}
+
+func newServer(decoder ar8t.DefaultDecoder, maxBytes, maxPixels int64, timeout time.Duration, concurrency int) *server {
+	return &server{
+		decoder:   decoder,
+		maxBytes:  maxBytes,
+		maxPixels: maxPixels,
+		timeout:   timeout,
+		slots:     make(chan struct{}, concurrency),
+		metrics:   newMetrics(),
+	}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/decode", s.handleDecode)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+// response is the body of every response of /decode
+type response struct {
+	Images []imageResponse `json:"images"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// imageResponse is what decoding an image gave, as the ar8t command writes in its json format
+type imageResponse struct {
+	Name    string          `json:"name,omitempty"`
+	Status  string          `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Symbols []report.Symbol `json:"symbols"`
+}
+
+// upload is an image posted, name being the file name of a multipart part
+type upload struct {
+	name string
+	data []byte
+}
+
+var (
+	errBusy     = errors.New("too many images being decoded")
+	errNoFiles  = errors.New("no files in the multipart body")
+	errTooLarge = errors.New("request body too large")
+)
+
+func (s *server) handleDecode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, response{Images: []imageResponse{}, Error: "only POST is allowed"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+
+	body := &limitReader{r: r.Body, n: s.maxBytes}
+	uploads, err := readUploads(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		status, code := statusBadRequest, http.StatusBadRequest
+		if body.exceeded {
+			status, code, err = statusTooLarge, http.StatusRequestEntityTooLarge, errTooLarge
+		}
+
+		s.metrics.request(status)
+		writeJSON(w, code, response{Images: []imageResponse{}, Error: err.Error()})
+		return
+	}
+
+	res := response{Images: []imageResponse{}}
+	for _, u := range uploads {
+		image, err := s.decode(ctx, u)
+		switch {
+		case errors.Is(err, errBusy):
+			s.metrics.request(statusBusy)
+			writeJSON(w, http.StatusServiceUnavailable, response{Images: res.Images, Error: err.Error()})
+			return
+		case errors.Is(err, context.DeadlineExceeded):
+			s.metrics.request(statusTimeout)
+			writeJSON(w, http.StatusGatewayTimeout, response{Images: res.Images, Error: "decoding took too long"})
+			return
+		case err != nil:
+			// the client went away
+			s.metrics.request(statusBadRequest)
+			return
+		}
+
+		s.metrics.image(image.Status)
+		res.Images = append(res.Images, image)
+	}
+
+	s.metrics.request(statusOK)
+	writeJSON(w, http.StatusOK, res)
+}
+
+// limitReader reads at most n bytes, failing when there are more
+type limitReader struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, errTooLarge
+	}
+
+	// a byte more than the limit tells whether there are more
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		l.exceeded = true
+		return n - int(-l.n), errTooLarge
+	}
+	return n, err
+}
+
+// readUploads reads the images of a request body, every file of a multipart/form-data body
+// or the whole body otherwise
+func readUploads(contentType string, body io.Reader) ([]upload, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		return []upload{{data: data}}, nil
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	uploads := []upload{}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// form fields that aren't files are ignored
+		if part.FileName() == "" {
+			continue
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, upload{name: part.FileName(), data: data})
+	}
+
+	if len(uploads) == 0 {
+		return nil, errNoFiles
+	}
+	return uploads, nil
+}
+
+// decode decodes an upload once a slot is free, failing when none is before the request times out.
+// Decoding can't be stopped, so it keeps its slot until it is done even when the request times out.
+func (s *server) decode(ctx context.Context, u upload) (imageResponse, error) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return imageResponse{}, errBusy
+		}
+		return imageResponse{}, ctx.Err()
+	}
+
+	done := make(chan imageResponse, 1)
+	go func() {
+		defer func() { <-s.slots }()
+
+		finish := s.metrics.start()
+		start := time.Now()
+		res := s.decodeImage(u)
+		finish(time.Since(start))
+
+		done <- res
+	}()
+
+	select {
+	case res := <-done:
+		return res, nil
+	case <-ctx.Done():
+		return imageResponse{}, ctx.Err()
+	}
+}
+
+func (s *server) decodeImage(u upload) imageResponse {
+	res := imageResponse{Name: u.name, Symbols: []report.Symbol{}}
+
+	// the size is read from the header first, a small file can hold a huge image
+	config, _, err := image.DecodeConfig(bytes.NewReader(u.data))
+	if err != nil {
+		res.Status, res.Error = statusBadImage, fmt.Sprintf("decoding image: %v", err)
+		return res
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > s.maxPixels {
+		res.Status, res.Error = statusBadImage, fmt.Sprintf("image of %dx%d pixels is over the limit of %d", config.Width, config.Height, s.maxPixels)
+		return res
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(u.data))
+	if err != nil {
+		res.Status, res.Error = statusBadImage, fmt.Sprintf("decoding image: %v", err)
+		return res
+	}
+
+	results, err := s.decoder.DecodeAll(img)
+	if err != nil {
+		res.Status, res.Error = statusNoSymbol, err.Error()
+		return res
+	}
+
+	res.Status = statusUndecodable
+	for _, result := range results {
+		res.Symbols = append(res.Symbols, report.NewSymbol(result))
+		if result.Err == nil {
+			res.Status = statusOK
+		}
+	}
+
+	return res
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.writeTo(w)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
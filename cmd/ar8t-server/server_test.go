@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrg0lden/ar8t"
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/mrg0lden/ar8t/internal/report"
+	"github.com/stretchr/testify/assert"
+)
+
+// encodePNG encodes img as a PNG file
+func encodePNG(t *testing.T, img image.Image) []byte {
+	buf := bytes.Buffer{}
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func multipartBody(t *testing.T, files map[string][]byte) (string, []byte) {
+	buf := bytes.Buffer{}
+	w := multipart.NewWriter(&buf)
+	assert.NoError(t, w.WriteField("comment", "ignored"))
+	for name, data := range files {
+		part, err := w.CreateFormFile("image", name)
+		assert.NoError(t, err)
+		part.Write(data)
+	}
+	assert.NoError(t, w.Close())
+
+	return w.FormDataContentType(), buf.Bytes()
+}
+
+func Test_handleDecode(t *testing.T) {
+	multipartType, multipartData := multipartBody(t, map[string][]byte{"label.png": encodePNG(t, corpus.EAN13())})
+	emptyType, emptyData := multipartBody(t, nil)
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        []byte
+		code        int
+		want        []imageResponse
+	}{
+		{
+			name:        "raw",
+			method:      http.MethodPost,
+			contentType: "image/png",
+			body:        encodePNG(t, corpus.EAN13()),
+			code:        http.StatusOK,
+			want: []imageResponse{{Status: statusOK, Symbols: []report.Symbol{{
+				Symbology: "EAN-13", Decoded: true, DataBase64: ptr("NDAwNjM4MTMzMzkzMQ=="), DataUTF8: ptr("4006381333931"),
+				Corners: [][2]float64{{36, 40}, {320, 40}},
+			}}}},
+		},
+		{
+			name:        "multipart",
+			method:      http.MethodPost,
+			contentType: multipartType,
+			body:        multipartData,
+			code:        http.StatusOK,
+			want: []imageResponse{{Name: "label.png", Status: statusOK, Symbols: []report.Symbol{{
+				Symbology: "EAN-13", Decoded: true, DataBase64: ptr("NDAwNjM4MTMzMzkzMQ=="), DataUTF8: ptr("4006381333931"),
+				Corners: [][2]float64{{36, 40}, {320, 40}},
+			}}}},
+		},
+		{
+			name:   "not an image",
+			method: http.MethodPost,
+			body:   []byte("not an image"),
+			code:   http.StatusOK,
+			want:   []imageResponse{{Status: statusBadImage, Error: "decoding image: image: unknown format", Symbols: []report.Symbol{}}},
+		},
+		{
+			name:   "too many pixels",
+			method: http.MethodPost,
+			body:   encodePNG(t, image.NewGray(image.Rect(0, 0, 400, 400))),
+			code:   http.StatusOK,
+			want: []imageResponse{{
+				Status: statusBadImage, Error: "image of 400x400 pixels is over the limit of 100000", Symbols: []report.Symbol{},
+			}},
+		},
+		{
+			name:        "no files",
+			method:      http.MethodPost,
+			contentType: emptyType,
+			body:        emptyData,
+			code:        http.StatusBadRequest,
+			want:        []imageResponse{},
+		},
+		{
+			name:   "too large",
+			method: http.MethodPost,
+			body:   make([]byte, 64<<10+1),
+			code:   http.StatusRequestEntityTooLarge,
+			want:   []imageResponse{},
+		},
+		{
+			name:   "get",
+			method: http.MethodGet,
+			code:   http.StatusMethodNotAllowed,
+			want:   []imageResponse{},
+		},
+	}
+
+	s := newServer(ar8t.DefaultDecoder{}, 64<<10, 100_000, time.Minute, 2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/decode", bytes.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			s.routes().ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+
+			var res response
+			if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res)) {
+				assert.Equal(t, tt.want, res.Images)
+				assert.Equal(t, tt.code != http.StatusOK, res.Error != "")
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	s.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	metrics := w.Body.String()
+	for _, line := range []string{
+		`ar8t_requests_total{status="ok"} 4`,
+		`ar8t_requests_total{status="too_large"} 1`,
+		`ar8t_images_total{status="ok"} 2`,
+		`ar8t_images_total{status="bad_image"} 2`,
+		`ar8t_decode_duration_seconds_count 4`,
+		`ar8t_decodes_in_flight 0`,
+	} {
+		assert.Contains(t, metrics, line+"\n")
+	}
+
+	w = httptest.NewRecorder()
+	s.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, "ok", strings.TrimSpace(w.Body.String()))
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// blockingObserver holds up decoding until release is closed
+type blockingObserver struct {
+	ar8t.NopObserver
+	release chan struct{}
+}
+
+func (o blockingObserver) Prepared(*image.Gray) { <-o.release }
+
+func Test_handleDecode_overloaded(t *testing.T) {
+	body := encodePNG(t, corpus.EAN13())
+
+	tests := []struct {
+		name string
+		code int
+		// busy takes the only slot before the request, so that decoding can't start
+		busy   bool
+		status string
+	}{
+		{
+			name:   "decoding too long",
+			code:   http.StatusGatewayTimeout,
+			status: statusTimeout,
+		},
+		{
+			name:   "no free slot",
+			code:   http.StatusServiceUnavailable,
+			busy:   true,
+			status: statusBusy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+
+			s := newServer(ar8t.DefaultDecoder{Observer: blockingObserver{release: release}}, 64<<10, 100_000, 50*time.Millisecond, 1)
+			if tt.busy {
+				s.slots <- struct{}{}
+			}
+
+			w := httptest.NewRecorder()
+			s.routes().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/decode", bytes.NewReader(body)))
+			assert.Equal(t, tt.code, w.Code)
+
+			var res response
+			if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res)) {
+				assert.Empty(t, res.Images)
+				assert.NotEmpty(t, res.Error)
+			}
+
+			w = httptest.NewRecorder()
+			s.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			assert.Contains(t, w.Body.String(), `ar8t_requests_total{status="`+tt.status+`"} 1`+"\n")
+		})
+	}
+}
@@ -0,0 +1,22 @@
+#!/bin/sh
+# Builds ar8t.wasm and copies the wasm_exec.js it needs next to index.html, in the directory given or dist.
+# Symbols and DWARF are stripped, and a gzip copy is made for servers that serve precompressed files.
+set -e
+
+cd "$(dirname "$0")"
+out="${1:-dist}"
+mkdir -p "$out"
+
+GOOS=js GOARCH=wasm go build -trimpath -ldflags="-s -w" -o "$out/ar8t.wasm" .
+
+# wasm_exec.js moved from misc/wasm to lib/wasm in Go 1.24
+root="$(go env GOROOT)"
+if [ -f "$root/lib/wasm/wasm_exec.js" ]; then
+	cp "$root/lib/wasm/wasm_exec.js" "$out/"
+else
+	cp "$root/misc/wasm/wasm_exec.js" "$out/"
+fi
+
+cp index.html "$out/"
+gzip -9 -k -f "$out/ar8t.wasm"
+ls -l "$out/ar8t.wasm" "$out/ar8t.wasm.gz"
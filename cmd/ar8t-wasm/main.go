@@ -0,0 +1,104 @@
+//go:build js && wasm
+
+// Command ar8t-wasm exposes the decoder to JavaScript as a WebAssembly module
+//
+// Once the module runs, it sets the global ar8t object with a single function
+//
+//	ar8t.decode(rgba, width, height)
+//
+// rgba being the pixels of an image as a Uint8ClampedArray, 4 bytes a pixel in row major order,
+// as the data of the ImageData a canvas gives. It returns an object such as
+//
+//	{results: [{symbology: "QR Code", decoded: true, text: "hello", bytes: Uint8Array,
+//	corners: [{x: 12, y: 10}, ...], error: null}], error: null}
+//
+// results being every symbol found, decoded or not, and error set when no symbol is found
+// or the arguments are invalid. text is the payload decoded as UTF-8 and bytes the payload as is,
+// both null for a symbol that couldn't be decoded, its error telling why.
+//
+// Build it with build.sh, which strips the module and copies the wasm_exec.js it needs.
+// See index.html for an example page.
+package main
+
+import (
+	"errors"
+	"image"
+	"syscall/js"
+
+	"github.com/mrg0lden/ar8t"
+)
+
+var errInvalidImage = errors.New("rgba must be a Uint8ClampedArray of width*height*4 bytes")
+
+func main() {
+	js.Global().Set("ar8t", js.ValueOf(map[string]any{
+		"decode": js.FuncOf(decode),
+	}))
+
+	// the functions are called from JavaScript for as long as the page lives
+	select {}
+}
+
+func decode(this js.Value, args []js.Value) any {
+	if len(args) != 3 || args[1].Type() != js.TypeNumber || args[2].Type() != js.TypeNumber {
+		return response(nil, errInvalidImage)
+	}
+
+	img, err := rgbaImage(args[0], args[1].Int(), args[2].Int())
+	if err != nil {
+		return response(nil, err)
+	}
+
+	return response(ar8t.DefaultDecoder{}.DecodeAll(img))
+}
+
+// rgbaImage copies the pixels of a Uint8ClampedArray into an image of width x height pixels
+func rgbaImage(rgba js.Value, width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 || rgba.Type() != js.TypeObject || rgba.Get("length").Int() != width*height*4 {
+		return nil, errInvalidImage
+	}
+
+	// a Uint8Array over the same buffer, as CopyBytesToGo takes
+	bytes := js.Global().Get("Uint8Array").New(rgba.Get("buffer"), rgba.Get("byteOffset"), rgba.Get("byteLength"))
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	js.CopyBytesToGo(img.Pix, bytes)
+
+	return img, nil
+}
+
+func response(results []ar8t.Result, err error) any {
+	res := []any{}
+	for _, result := range results {
+		corners := []any{}
+		for _, p := range result.Corners {
+			corners = append(corners, map[string]any{"x": p.X, "y": p.Y})
+		}
+
+		symbol := map[string]any{
+			"symbology": result.Symbology.String(),
+			"decoded":   result.Err == nil,
+			"text":      nil,
+			"bytes":     nil,
+			"corners":   corners,
+			"error":     nil,
+		}
+
+		if result.Err != nil {
+			symbol["error"] = result.Err.Error()
+		} else {
+			data := js.Global().Get("Uint8Array").New(len(result.Data))
+			js.CopyBytesToJS(data, result.Data)
+			symbol["text"], symbol["bytes"] = string(result.Data), data
+		}
+
+		res = append(res, symbol)
+	}
+
+	var message any
+	if err != nil {
+		message = err.Error()
+	}
+
+	return js.ValueOf(map[string]any{"results": res, "error": message})
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"path/filepath"
+
+	"github.com/mrg0lden/ar8t"
+)
+
+// errUndecodable is also returned for finder candidates that aren't symbols at all,
+// as the results of DecodeAll don't tell those apart from damaged symbols
+var errUndecodable = errors.New("symbols found but none could be decoded")
+
+// ioError is an error reading a file or decoding its image
+type ioError struct {
+	err error
+}
+
+func (e ioError) Error() string { return e.err.Error() }
+func (e ioError) Unwrap() error { return e.err }
+
+// decoder decodes the symbols in files as the flags ask
+type decoder struct {
+	soft  bool
+	first bool
+
+	// debugDir is where the images of the stages of decoding are written, none are when empty
+	debugDir string
+}
+
+// decodeFile decodes the symbols in a file, or in stdin when the file is -.
+// The debug images are named after debugName.
+func (d decoder) decodeFile(file string, stdin io.Reader, debugName string) ([]ar8t.Result, error) {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, ioError{err}
+		}
+		defer f.Close()
+		r = f
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, ioError{fmt.Errorf("decoding image: %w", err)}
+	}
+
+	return d.decode(img, debugName)
+}
+
+// decode decodes the symbols in an image, along with those found that can't be decoded
+func (d decoder) decode(img image.Image, debugName string) ([]ar8t.Result, error) {
+	dec := ar8t.DefaultDecoder{SoftSampling: d.soft}
+
+	var debugErr error
+	if d.debugDir != "" {
+		dec.Debug = func(stage ar8t.DebugStage, img image.Image) {
+			if err := writePNG(filepath.Join(d.debugDir, debugName+"."+string(stage)+".png"), img); err != nil && debugErr == nil {
+				debugErr = ioError{fmt.Errorf("writing debug image: %w", err)}
+			}
+		}
+	}
+
+	results, err := dec.DecodeAll(img)
+	if debugErr != nil {
+		return results, debugErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := 0
+	for _, result := range results {
+		if result.Err == nil {
+			if d.first {
+				return []ar8t.Result{result}, nil
+			}
+			decoded++
+		}
+	}
+
+	if decoded == 0 {
+		return results, errUndecodable
+	}
+
+	return results, nil
+}
+
+// writePNG writes an image to a PNG file
+func writePNG(file string, img image.Image) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// statusOf is the exit status for the error decoding a file
+func statusOf(err error) int {
+	var ioErr ioError
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &ioErr):
+		return exitIO
+	case errors.Is(err, ar8t.ErrNoSymbolsFound):
+		return exitNoSymbol
+	case errors.Is(err, errUndecodable):
+		return exitUndecodable
+	}
+
+	return exitIO
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// imageExtensions are the extensions of the files decoded in directories
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+}
+
+// input is a file to decode, or the error finding the files an argument names
+type input struct {
+	file string
+	err  error
+}
+
+// inputs are the files the arguments name. Directories are walked for images, and patterns
+// that aren't files themselves are expanded, for shells that don't or when they are quoted.
+func inputs(args []string) []input {
+	res := []input{}
+	for _, arg := range args {
+		if arg == "-" {
+			res = append(res, input{file: arg})
+			continue
+		}
+
+		files := []string{arg}
+		if _, err := os.Stat(arg); err != nil && strings.ContainsAny(arg, "*?[") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				res = append(res, input{file: arg, err: ioError{err}})
+				continue
+			}
+			if len(matches) == 0 {
+				res = append(res, input{file: arg, err: ioError{fmt.Errorf("no files match")}})
+				continue
+			}
+			files = matches
+		}
+
+		for _, file := range files {
+			res = append(res, walk(file)...)
+		}
+	}
+
+	return res
+}
+
+// walk is the file itself, or the images in a directory and those below it in lexical order
+func walk(file string) []input {
+	info, err := os.Stat(file)
+	if err != nil || !info.IsDir() {
+		// the error is reported when the file is opened
+		return []input{{file: file}}
+	}
+
+	res := []input{}
+	err = filepath.WalkDir(file, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			res = append(res, input{file: path, err: ioError{err}})
+			return nil
+		}
+
+		if !entry.IsDir() && imageExtensions[strings.ToLower(filepath.Ext(path))] {
+			res = append(res, input{file: path})
+		}
+		return nil
+	})
+	if err != nil {
+		res = append(res, input{file: file, err: ioError{err}})
+	}
+
+	return res
+}
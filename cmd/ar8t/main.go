@@ -0,0 +1,190 @@
+// Command ar8t decodes the symbols in image files
+//
+// Usage:
+//
+//	ar8t [flags] [file ...]
+//
+// Every file is decoded in turn, standard input being read when there are no files or the file is -.
+// Directories are walked for .png, .jpg, .jpeg and .gif files, and patterns such as 'labels/*.png'
+// are expanded when the shell leaves them as they are. PNG, JPEG and GIF images are supported.
+//
+// The text, hex and base64 formats write the payload of every decoded symbol on its own line,
+// after the name of its file when there are several of them.
+//
+// The json format writes an array with an object for every file, ndjson writes those objects
+// one per line as every file is decoded. Their fields are
+//
+//	file     the name of the file, <stdin> for standard input
+//	status   ok, no_symbol, undecodable or io_error, as the exit statuses below
+//	error    why no symbol was found or the file couldn't be read, omitted otherwise
+//	symbols  every symbol found in the file, decoded or not
+//
+// and those of every symbol are
+//
+//	symbology         such as "QR Code" or "EAN-13"
+//	decoded           whether the symbol was decoded
+//	data_base64       the payload in base64, omitted when not decoded
+//	data_utf8         the payload as text, omitted when not decoded or not valid UTF-8
+//	corners           the [x, y] pixel coordinates of the corners of the symbol clockwise from
+//	                  its top left, the ends of the line read across linear barcodes
+//	version           QR Codes only
+//	ec_level          L, M, Q or H, QR Codes only and omitted when the format can't be read
+//	mask              0 to 7, as ec_level
+//	corrected_errors  the number of bits corrected in every block, QR Codes only
+//	error             why the symbol couldn't be decoded, omitted when it was
+//
+// The csv format writes a header, then a row for every symbol with the fields of the symbol as columns
+// after file and status. A file without any symbol has a single row with only file, status and error.
+// Corners are written as "x y" pairs separated by semicolons, and so are the corrected errors.
+//
+// With -debug-dir, images of the stages of decoding QR Codes are written for every file, as the
+// DebugStage documentation of the ar8t package describes. They are named after the file and the stage,
+// such as label.threshold.png, numbered as 2-label.threshold.png when there are several files.
+//
+// The exit status is 0 when a symbol is decoded in every file, 1 when a file can't be read
+// or isn't an image, 2 for invalid flags, 3 when no symbol is found in a file and 4 when
+// symbols are found in a file but none of them can be decoded. The most serious one is returned
+// when the files differ, I/O errors being the most serious and missing symbols the least.
+// Exit status 4 can be a false positive: a symbol counts as found as soon as its finder patterns are,
+// before its format information confirms it, and Micro QR and rMQR Codes are looked for around
+// every QR Code finder candidate, so a file without any symbol can exit with 4 instead of 3.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// the exit statuses, see the command documentation
+const (
+	exitOK = iota
+	exitIO
+	exitUsage
+	exitNoSymbol
+	exitUndecodable
+)
+
+// severity orders the exit statuses from the least to the most serious
+var severity = [...]int{
+	exitOK:          0,
+	exitNoSymbol:    1,
+	exitUndecodable: 2,
+	exitUsage:       3,
+	exitIO:          4,
+}
+
+// worse is the more serious of two exit statuses
+func worse(a, b int) int {
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with its arguments and returns its exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ar8t", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: ar8t [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+
+	var (
+		format = flags.String("format", "text", "the output format: text, hex, base64, json, ndjson or csv")
+		first  = flags.Bool("first", false, "write only the first symbol decoded in every file")
+		all    = flags.Bool("all", true, "write every symbol decoded in every file")
+		soft   = flags.Bool("soft", false, "sample modules from the grayscale image, slower but better for blurry images")
+		debug  = flags.String("debug-dir", "", "write images of the stages of decoding QR Codes in every file to this directory")
+	)
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	newOutput, ok := outputs[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "ar8t: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	d := decoder{
+		soft:     *soft,
+		first:    *first || !*all,
+		debugDir: *debug,
+	}
+
+	if d.debugDir != "" {
+		if err := os.MkdirAll(d.debugDir, 0o755); err != nil {
+			fmt.Fprintf(stderr, "ar8t: %v\n", err)
+			return exitIO
+		}
+	}
+
+	files := inputs(args)
+	out := newOutput(stdout, len(files) > 1)
+
+	status := exitOK
+	for i, in := range files {
+		res := fileResult{file: in.file, err: in.err}
+		if res.err == nil {
+			res.results, res.err = d.decodeFile(in.file, stdin, debugName(in.file, i, len(files) > 1))
+		}
+
+		if res.err != nil {
+			fmt.Fprintf(stderr, "ar8t: %s: %v\n", displayName(in.file), res.err)
+		}
+		status = worse(status, statusOf(res.err))
+
+		if err := out.write(res); err != nil {
+			fmt.Fprintf(stderr, "ar8t: %v\n", err)
+			return exitIO
+		}
+	}
+
+	if err := out.close(); err != nil {
+		fmt.Fprintf(stderr, "ar8t: %v\n", err)
+		return exitIO
+	}
+
+	return status
+}
+
+// debugName is the name the debug images of a file start with, the name of the file without
+// its extension. It is numbered when there are several files, as their names can be the same.
+func debugName(file string, i int, several bool) string {
+	name := "stdin"
+	if file != "-" {
+		name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	if several {
+		name = fmt.Sprintf("%d-%s", i+1, name)
+	}
+	return name
+}
+
+// displayName is the name of a file in messages
+func displayName(file string) string {
+	if file == "-" {
+		return "<stdin>"
+	}
+	return file
+}
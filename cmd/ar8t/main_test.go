@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+// encodePNG encodes img as a PNG file
+func encodePNG(t *testing.T, img image.Image) []byte {
+	buf := bytes.Buffer{}
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// qrCodeImage is the version 1 QR Code of the corpus holding "ar8t", 6 pixels a module,
+// with the modules in flipped flipped
+func qrCodeImage(t *testing.T, flipped ...[2]int) *image.Gray {
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	symbol := symbols[0]
+	for _, m := range flipped {
+		symbol.Modules[m[1]][m[0]] = !symbol.Modules[m[1]][m[0]]
+	}
+
+	return symbol.Render(6)
+}
+
+func blank(t *testing.T) []byte {
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	return encodePNG(t, img)
+}
+
+func Test_run(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, data, 0o644))
+		return path
+	}
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "labels"), 0o755))
+	labelled := write(filepath.Join("labels", "label.png"), encodePNG(t, corpus.EAN13()))
+	barcode := write("barcode.png", encodePNG(t, corpus.EAN13()))
+	empty := write("blank.png", blank(t))
+	text := write("text.png", []byte("not an image"))
+
+	// the bottom right of the QR Code is all data, flipping it leaves too many errors to correct
+	undecodableFlipped := [][2]int{}
+	for y := 13; y < 21; y++ {
+		for x := 13; x < 21; x++ {
+			undecodableFlipped = append(undecodableFlipped, [2]int{x, y})
+		}
+	}
+	undecodable := write("undecodable.png", encodePNG(t, qrCodeImage(t, undecodableFlipped...)))
+
+	// a QR Code above an EAN-13 barcode
+	page := image.NewGray(image.Rect(0, 0, 400, 350))
+	draw.Draw(page, page.Rect, image.White, image.Point{}, draw.Src)
+	draw.Draw(page, page.Rect, qrCodeImage(t), image.Point{}, draw.Src)
+	draw.Draw(page, page.Rect.Add(image.Pt(0, 200)), corpus.EAN13(), image.Point{}, draw.Src)
+	both := write("page.png", encodePNG(t, page))
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  []byte
+		want   string
+		status int
+	}{
+		{
+			name:   "file",
+			args:   []string{barcode},
+			want:   "4006381333931\n",
+			status: exitOK,
+		},
+		{
+			name:   "stdin hex",
+			args:   []string{"--format", "hex"},
+			stdin:  encodePNG(t, corpus.EAN13()),
+			want:   "34303036333831333333393331\n",
+			status: exitOK,
+		},
+		{
+			name:   "several files base64",
+			args:   []string{"-format=base64", barcode, empty},
+			want:   barcode + ": NDAwNjM4MTMzMzkzMQ==\n",
+			status: exitNoSymbol,
+		},
+		{
+			name:   "no symbol",
+			args:   []string{empty},
+			status: exitNoSymbol,
+		},
+		{
+			name:   "not an image",
+			args:   []string{empty, text},
+			status: exitIO,
+		},
+		{
+			name:   "missing file",
+			args:   []string{filepath.Join(dir, "missing.png")},
+			status: exitIO,
+		},
+		{
+			name: "directory ndjson",
+			args: []string{"--format", "ndjson", filepath.Join(dir, "labels")},
+			want: `{"file":"` + labelled + `","status":"ok","symbols":[{"symbology":"EAN-13","decoded":true,` +
+				`"data_base64":"NDAwNjM4MTMzMzkzMQ==","data_utf8":"4006381333931","corners":[[36,40],[320,40]]}]}` + "\n",
+			status: exitOK,
+		},
+		{
+			name: "glob csv",
+			args: []string{"--format", "csv", filepath.Join(dir, "b*.png")},
+			want: "file,status,symbology,decoded,data_base64,data_utf8,corners,version,ec_level,mask,corrected_errors,error\n" +
+				barcode + ",ok,EAN-13,true,NDAwNjM4MTMzMzkzMQ==,4006381333931,36 40;320 40,,,,,\n" +
+				empty + ",no_symbol,,,,,,,,,,no symbols found\n",
+			status: exitNoSymbol,
+		},
+		{
+			name:   "undecodable",
+			args:   []string{undecodable},
+			status: exitUndecodable,
+		},
+		{
+			name:   "undecodable and no symbol",
+			args:   []string{empty, undecodable},
+			status: exitUndecodable,
+		},
+		{
+			name:   "every symbol",
+			args:   []string{both},
+			want:   "ar8t\n4006381333931\n",
+			status: exitOK,
+		},
+		{
+			name:   "first symbol",
+			args:   []string{"--first", both},
+			want:   "ar8t\n",
+			status: exitOK,
+		},
+		{
+			name:   "first symbol with all unset",
+			args:   []string{"--all=false", both},
+			want:   "ar8t\n",
+			status: exitOK,
+		},
+		{
+			name:   "unknown format",
+			args:   []string{"--format", "xml", barcode},
+			status: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+			status := run(tt.args, bytes.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.status, status, stderr.String())
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func Test_runDebugDir(t *testing.T) {
+	dir := t.TempDir()
+	debugDir := filepath.Join(dir, "debug")
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	status := run([]string{"--debug-dir", debugDir}, bytes.NewReader(blank(t)), &stdout, &stderr)
+	assert.Equal(t, exitNoSymbol, status, stderr.String())
+
+	for _, stage := range []string{"threshold", "finders", "locations", "alignment", "grid"} {
+		assert.FileExists(t, filepath.Join(debugDir, "stdin."+stage+".png"))
+	}
+}
+
+// Test_run_qrCode checks what the json and csv formats tell about a damaged QR Code
+func Test_run_qrCode(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "damaged.png")
+	assert.NoError(t, os.WriteFile(file, encodePNG(t, qrCodeImage(t, [2]int{20, 20}, [2]int{19, 20}, [2]int{20, 18})), 0o644))
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	if !assert.Equal(t, exitOK, run([]string{"--format", "json", file}, nil, &stdout, &stderr), stderr.String()) {
+		return
+	}
+
+	var files []jsonFile
+	if !assert.NoError(t, json.Unmarshal(stdout.Bytes(), &files)) || !assert.Len(t, files, 1) || !assert.Len(t, files[0].Symbols, 1) {
+		return
+	}
+
+	symbol := files[0].Symbols[0]
+	assert.Equal(t, "QR Code", symbol.Symbology)
+	assert.Equal(t, "ar8t", *symbol.DataUTF8)
+	assert.Equal(t, uint32(1), *symbol.Version)
+	assert.Equal(t, "L", symbol.ECLevel)
+	assert.Equal(t, 0, *symbol.Mask)
+	assert.Equal(t, []int{3}, symbol.CorrectedErrors)
+	assert.Len(t, symbol.Corners, 4)
+
+	stdout.Reset()
+	if !assert.Equal(t, exitOK, run([]string{"--format", "csv", file}, nil, &stdout, &stderr), stderr.String()) {
+		return
+	}
+
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if !assert.NoError(t, err) || !assert.Len(t, records, 2) {
+		return
+	}
+
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	assert.Equal(t, "QR Code", row["symbology"])
+	assert.Equal(t, "1", row["version"])
+	assert.Equal(t, "L", row["ec_level"])
+	assert.Equal(t, "0", row["mask"])
+	assert.Equal(t, "3", row["corrected_errors"])
+	assert.Empty(t, row["error"])
+}
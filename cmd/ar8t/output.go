@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mrg0lden/ar8t"
+	"github.com/mrg0lden/ar8t/internal/report"
+)
+
+// fileResult is what decoding a file gave
+type fileResult struct {
+	file    string
+	results []ar8t.Result
+	err     error
+}
+
+// output writes the results of every file in a format
+type output interface {
+	write(res fileResult) error
+
+	// close writes whatever the format needs after the last file
+	close() error
+}
+
+// outputs make the output of every format, several being whether there are several files
+var outputs = map[string]func(w io.Writer, several bool) output{
+	"text": func(w io.Writer, several bool) output {
+		return &payloadOutput{w, several, func(data []byte) string { return string(data) }}
+	},
+	"hex": func(w io.Writer, several bool) output {
+		return &payloadOutput{w, several, hex.EncodeToString}
+	},
+	"base64": func(w io.Writer, several bool) output {
+		return &payloadOutput{w, several, base64.StdEncoding.EncodeToString}
+	},
+	"json": func(w io.Writer, several bool) output {
+		return &jsonOutput{w: w, files: []jsonFile{}}
+	},
+	"ndjson": func(w io.Writer, several bool) output {
+		return &ndjsonOutput{json.NewEncoder(w)}
+	},
+	"csv": func(w io.Writer, several bool) output {
+		return &csvOutput{w: csv.NewWriter(w)}
+	},
+}
+
+// payloadOutput writes the payload of every decoded symbol on its own line,
+// after the name of its file when there are several
+type payloadOutput struct {
+	w       io.Writer
+	several bool
+	encode  func([]byte) string
+}
+
+func (o *payloadOutput) write(res fileResult) error {
+	for _, result := range res.results {
+		if result.Err != nil {
+			continue
+		}
+
+		line := o.encode(result.Data) + "\n"
+		if o.several {
+			line = displayName(res.file) + ": " + line
+		}
+		if _, err := io.WriteString(o.w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *payloadOutput) close() error { return nil }
+
+// statusNames name the exit status of every file in the structured formats
+var statusNames = [...]string{
+	exitOK:          "ok",
+	exitIO:          "io_error",
+	exitNoSymbol:    "no_symbol",
+	exitUndecodable: "undecodable",
+}
+
+// jsonFile is the result of a file in the JSON formats, see the command documentation
+type jsonFile struct {
+	File    string          `json:"file"`
+	Status  string          `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Symbols []report.Symbol `json:"symbols"`
+}
+
+func newJSONFile(res fileResult) jsonFile {
+	f := jsonFile{
+		File:    displayName(res.file),
+		Status:  statusNames[statusOf(res.err)],
+		Symbols: []report.Symbol{},
+	}
+
+	// files where symbols were found but not decoded list the reasons for each of them
+	if res.err != nil && statusOf(res.err) != exitUndecodable {
+		f.Error = res.err.Error()
+	}
+
+	for _, result := range res.results {
+		f.Symbols = append(f.Symbols, report.NewSymbol(result))
+	}
+
+	return f
+}
+
+// jsonOutput writes an array of the results of every file
+type jsonOutput struct {
+	w     io.Writer
+	files []jsonFile
+}
+
+func (o *jsonOutput) write(res fileResult) error {
+	o.files = append(o.files, newJSONFile(res))
+	return nil
+}
+
+func (o *jsonOutput) close() error {
+	e := json.NewEncoder(o.w)
+	e.SetIndent("", "  ")
+	return e.Encode(o.files)
+}
+
+// ndjsonOutput writes the results of every file on its own line as soon as they are known
+type ndjsonOutput struct {
+	e *json.Encoder
+}
+
+func (o *ndjsonOutput) write(res fileResult) error {
+	return o.e.Encode(newJSONFile(res))
+}
+
+func (o *ndjsonOutput) close() error { return nil }
+
+// csvHeader are the columns of the CSV format, a row for every symbol
+// and one for every file without any
+var csvHeader = []string{
+	"file", "status", "symbology", "decoded", "data_base64", "data_utf8",
+	"corners", "version", "ec_level", "mask", "corrected_errors", "error",
+}
+
+type csvOutput struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (o *csvOutput) write(res fileResult) error {
+	if !o.header {
+		o.header = true
+		if err := o.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	f := newJSONFile(res)
+	if len(f.Symbols) == 0 {
+		row := make([]string, len(csvHeader))
+		row[0], row[1], row[11] = f.File, f.Status, f.Error
+		if err := o.w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range f.Symbols {
+		corners := []string{}
+		for _, p := range s.Corners {
+			corners = append(corners, strconv.FormatFloat(p[0], 'f', -1, 64)+" "+strconv.FormatFloat(p[1], 'f', -1, 64))
+		}
+
+		errorCounts := []string{}
+		for _, n := range s.CorrectedErrors {
+			errorCounts = append(errorCounts, strconv.Itoa(n))
+		}
+
+		row := []string{
+			f.File, f.Status, s.Symbology, strconv.FormatBool(s.Decoded), optional(s.DataBase64), optional(s.DataUTF8),
+			strings.Join(corners, ";"), optional(s.Version), s.ECLevel, optional(s.Mask), strings.Join(errorCounts, ";"), s.Error,
+		}
+		if err := o.w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	o.w.Flush()
+	return o.w.Error()
+}
+
+func (o *csvOutput) close() error {
+	o.w.Flush()
+	return o.w.Error()
+}
+
+// optional is the value a pointer points to, or an empty string for nil
+func optional[T any](v *T) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
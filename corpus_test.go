@@ -0,0 +1,130 @@
+package ar8t
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Corpus decodes the symbols of the corpus distorted in a number of ways and to a number of levels,
+// with a few seeds each, and checks the rate of them decoded. The minimum rates are those decoded
+// when they were set, less an image's worth. A rate falling below its minimum is a regression,
+// one well above it can be raised.
+func Test_Corpus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("decoding the corpus takes a while")
+	}
+
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name        string
+		distortions []corpus.Distortion
+		minRate     float64
+	}{
+		{"none", nil, 1},
+
+		{"rotate 10", []corpus.Distortion{corpus.Rotate(10)}, 0.9},
+		{"rotate 30", []corpus.Distortion{corpus.Rotate(30)}, 0.9},
+		{"rotate 45", []corpus.Distortion{corpus.Rotate(45)}, 0.9},
+		{"rotate 160", []corpus.Distortion{corpus.Rotate(160)}, 0.9},
+
+		{"perspective 0.05", []corpus.Distortion{corpus.Perspective(0.05)}, 0.65},
+		{"perspective 0.1", []corpus.Distortion{corpus.Perspective(0.1)}, 0.4},
+
+		{"blur 0.75", []corpus.Distortion{corpus.Blur(0.75)}, 0.9},
+		{"blur 1", []corpus.Distortion{corpus.Blur(1)}, 0.7},
+		{"blur 1.25", []corpus.Distortion{corpus.Blur(1.25)}, 0.1},
+
+		{"blur 1, noise 20", []corpus.Distortion{corpus.Blur(1), corpus.Noise(20)}, 0.65},
+		{"blur 1, noise 30", []corpus.Distortion{corpus.Blur(1), corpus.Noise(30)}, 0.5},
+
+		{"noise 20", []corpus.Distortion{corpus.Noise(20)}, 0.9},
+		{"noise 40", []corpus.Distortion{corpus.Noise(40)}, 0.85},
+		{"noise 50", []corpus.Distortion{corpus.Noise(50)}, 0.65},
+
+		{"jpeg 20", []corpus.Distortion{corpus.JPEG(20)}, 0.9},
+		{"jpeg 5", []corpus.Distortion{corpus.JPEG(5)}, 0.9},
+		{"blur 0.75, jpeg 5", []corpus.Distortion{corpus.Blur(0.75), corpus.JPEG(5)}, 0.9},
+		{"blur 0.75, noise 20, jpeg 5", []corpus.Distortion{corpus.Blur(0.75), corpus.Noise(20), corpus.JPEG(5)}, 0.65},
+
+		{"illumination 0.6", []corpus.Distortion{corpus.Illumination(0.6)}, 0.9},
+		{"illumination 0.9", []corpus.Distortion{corpus.Illumination(0.9)}, 0.9},
+		{"illumination 0.9, noise 10", []corpus.Distortion{corpus.Illumination(0.9), corpus.Noise(10)}, 0.85},
+		{"illumination 0.9, noise 20", []corpus.Distortion{corpus.Illumination(0.9), corpus.Noise(20)}, 0.25},
+
+		{"occlude 0.01", []corpus.Distortion{corpus.Occlude(0.01)}, 0.85},
+		{"occlude 0.03", []corpus.Distortion{corpus.Occlude(0.03)}, 0.4},
+		{"occlude 0.06", []corpus.Distortion{corpus.Occlude(0.06)}, 0.3},
+
+		{"rotate 30, perspective 0.05, blur 0.75, noise 10, jpeg 20", []corpus.Distortion{
+			corpus.Rotate(30), corpus.Perspective(0.05), corpus.Blur(0.75), corpus.Noise(10), corpus.JPEG(20),
+		}, 0.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decodeCorpus(t, DefaultDecoder{}, symbols, tt.distortions, tt.minRate)
+		})
+	}
+}
+
+// Test_Corpus_softSampling is Test_Corpus with DefaultDecoder.SoftSampling, on blurred symbols.
+// Blurred and noisy symbols are where it reads more than Test_Corpus does.
+func Test_Corpus_softSampling(t *testing.T) {
+	if testing.Short() {
+		t.Skip("decoding the corpus takes a while")
+	}
+
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name        string
+		distortions []corpus.Distortion
+		minRate     float64
+	}{
+		{"none", nil, 1},
+		{"blur 0.75", []corpus.Distortion{corpus.Blur(0.75)}, 0.9},
+		{"blur 1", []corpus.Distortion{corpus.Blur(1)}, 0.7},
+		{"blur 1.25", []corpus.Distortion{corpus.Blur(1.25)}, 0.1},
+		{"blur 1, noise 20", []corpus.Distortion{corpus.Blur(1), corpus.Noise(20)}, 0.8},
+		{"blur 1, noise 30", []corpus.Distortion{corpus.Blur(1), corpus.Noise(30)}, 0.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decodeCorpus(t, DefaultDecoder{SoftSampling: true}, symbols, tt.distortions, tt.minRate)
+		})
+	}
+}
+
+// decodeCorpus decodes every symbol distorted with a few seeds and checks the rate of them decoded
+func decodeCorpus(t *testing.T, decoder DefaultDecoder, symbols []corpus.Symbol, distortions []corpus.Distortion, minRate float64) {
+	const moduleSize, seeds = 4, 3
+
+	decoded, missed := 0, []string{}
+	for _, symbol := range symbols {
+		for seed := int64(1); seed <= seeds; seed++ {
+			img := corpus.Apply(symbol.Render(moduleSize), seed, distortions...)
+			results, _ := decoder.DecodeResults(img)
+
+			if len(results) == 1 && string(results[0].Data) == symbol.Text {
+				decoded++
+			} else {
+				missed = append(missed, fmt.Sprint(symbol.Name, " seed ", seed))
+			}
+		}
+	}
+
+	total := len(symbols) * seeds
+	t.Logf("%d of %d decoded, not %v", decoded, total, missed)
+	assert.GreaterOrEqual(t, float64(decoded)/float64(total), minRate)
+}
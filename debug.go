@@ -0,0 +1,135 @@
+package ar8t
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// DebugStage names the image of a stage of decoding QR Codes, see DefaultDecoder.Debug
+type DebugStage string
+
+const (
+	// DebugThreshold is the image prepared by BlockedMean, every pixel black or white
+	DebugThreshold DebugStage = "threshold"
+
+	// DebugFinders circles every QRFinderPosition candidate on the source image,
+	// as large as its finder pattern would be
+	DebugFinders DebugStage = "finders"
+
+	// DebugLocations draws the triangle between the finder patterns of every QRLocation,
+	// the top left one marked with a cross
+	DebugLocations DebugStage = "locations"
+
+	// DebugAlignment marks the alignment pattern found for every QRLocation.
+	// Version 1 symbols have none, and nothing is marked when it isn't found.
+	DebugAlignment DebugStage = "alignment"
+
+	// DebugGrid marks the centre of every module sampled by QRExtract, red for dark modules and blue for light ones
+	DebugGrid DebugStage = "grid"
+)
+
+var (
+	debugRed   = color.NRGBA{255, 0, 0, 255}
+	debugGreen = color.NRGBA{0, 200, 0, 255}
+	debugBlue  = color.NRGBA{0, 80, 255, 255}
+)
+
+// qrDebug is what decoding a QR Code location gives to draw
+type qrDebug struct {
+	location QRLocation
+	sampling qrSampling
+
+	// modules are the modules sampled, nil when extraction failed
+	modules *BitMatrix
+}
+
+// debugQR passes the images of the stages of decoding QR Codes to debug
+func debugQR(debug func(DebugStage, image.Image), src image.Image, prepared *image.Gray, finders []QRFinderPosition, decoded []qrDebug) {
+	debug(DebugThreshold, prepared)
+
+	canvas := newDebugCanvas(src)
+	for _, finder := range finders {
+		canvas.circle(finder.Location, 3.5*finder.ModuleSize, debugRed)
+	}
+	debug(DebugFinders, canvas)
+
+	canvas = newDebugCanvas(src)
+	for _, d := range decoded {
+		loc := d.location
+		canvas.line(loc.TopLeft, loc.TopRight, debugGreen)
+		canvas.line(loc.TopRight, loc.BottomLeft, debugGreen)
+		canvas.line(loc.BottomLeft, loc.TopLeft, debugGreen)
+		canvas.cross(loc.TopLeft, 2*loc.ModuleSize, debugGreen)
+	}
+	debug(DebugLocations, canvas)
+
+	canvas = newDebugCanvas(src)
+	for _, d := range decoded {
+		if d.sampling.alignment != nil {
+			canvas.circle(*d.sampling.alignment, 2.5*d.location.ModuleSize, debugBlue)
+			canvas.cross(*d.sampling.alignment, d.location.ModuleSize, debugBlue)
+		}
+	}
+	debug(DebugAlignment, canvas)
+
+	canvas = newDebugCanvas(src)
+	for _, d := range decoded {
+		if d.modules == nil {
+			continue
+		}
+
+		width := d.modules.Width()
+		for i, p := range d.sampling.grid {
+			c := debugBlue
+			if d.modules.Get(i%width, i/width) {
+				c = debugRed
+			}
+			canvas.dot(p, c)
+		}
+	}
+	debug(DebugGrid, canvas)
+}
+
+// debugCanvas is a copy of an image to draw on, its top left corner moved to 0, 0 as prepared images are
+type debugCanvas struct {
+	*image.NRGBA
+}
+
+func newDebugCanvas(src image.Image) debugCanvas {
+	return debugCanvas{imaging.Clone(src)}
+}
+
+func (c debugCanvas) dot(p Point, col color.NRGBA) {
+	x, y := int(math.Round(p.X)), int(math.Round(p.Y))
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 || dy == 0 {
+				c.SetNRGBA(x+dx, y+dy, col)
+			}
+		}
+	}
+}
+
+func (c debugCanvas) line(a, b Point, col color.NRGBA) {
+	steps := int(math.Ceil(2 * distance(a, b)))
+	for i := 0; i <= steps; i++ {
+		p := a.Add(b.Sub(a).Mul(float64(i) / float64(max(steps, 1))))
+		c.SetNRGBA(int(math.Round(p.X)), int(math.Round(p.Y)), col)
+	}
+}
+
+func (c debugCanvas) cross(p Point, r float64, col color.NRGBA) {
+	c.line(p.Sub(Point{r, r}), p.Add(Point{r, r}), col)
+	c.line(p.Sub(Point{r, -r}), p.Add(Point{r, -r}), col)
+}
+
+func (c debugCanvas) circle(p Point, r float64, col color.NRGBA) {
+	steps := int(math.Ceil(4 * math.Pi * r))
+	for i := 0; i < steps; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(steps)
+		c.SetNRGBA(int(math.Round(p.X+r*math.Cos(angle))), int(math.Round(p.Y+r*math.Sin(angle))), col)
+	}
+}
@@ -0,0 +1,76 @@
+package ar8t
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+// drawnNear tells whether img has a pixel of col within a pixel of p
+func drawnNear(img image.Image, p image.Point, col color.NRGBA) bool {
+	for y := p.Y - 1; y <= p.Y+1; y++ {
+		for x := p.X - 1; x <= p.X+1; x++ {
+			if color.NRGBAModel.Convert(img.At(x, y)) == col {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func Test_DefaultDecoder_Debug(t *testing.T) {
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var symbol corpus.Symbol
+	for _, s := range symbols {
+		if s.Name == "version3-M" {
+			symbol = s
+		}
+	}
+
+	const moduleSize = 6
+	img := symbol.Render(moduleSize)
+
+	// centre is the centre of a module in img
+	centre := func(x, y float64) image.Point {
+		return image.Pt(int((x+corpus.QuietZone+0.5)*moduleSize), int((y+corpus.QuietZone+0.5)*moduleSize))
+	}
+
+	stages := map[DebugStage]image.Image{}
+	results, err := DefaultDecoder{
+		Symbologies: []Symbology{SymbologyQR},
+		Debug: func(stage DebugStage, img image.Image) {
+			stages[stage] = img
+		},
+	}.DecodeResults(img)
+	if !assert.NoError(t, err) || !assert.Len(t, results, 1) {
+		return
+	}
+	assert.Equal(t, symbol.Text, string(results[0].Data))
+
+	if threshold, ok := stages[DebugThreshold].(*image.Gray); assert.True(t, ok) {
+		assert.Equal(t, img.Bounds(), threshold.Bounds())
+	}
+
+	// the finders are circled 3.5 modules from their centres, those of the top left and top right ones
+	// are joined by the triangle of the location, the top left one marked with a cross
+	for _, finder := range []image.Point{centre(3, 3), centre(25, 3), centre(3, 25)} {
+		assert.True(t, drawnNear(stages[DebugFinders], finder.Add(image.Pt(21, 0)), debugRed), "finder %v", finder)
+	}
+	assert.True(t, drawnNear(stages[DebugLocations], centre(3, 3), debugGreen))
+	assert.True(t, drawnNear(stages[DebugLocations], centre(14, 3), debugGreen))
+
+	// the alignment pattern of version 3 is centred on module 22, 22
+	assert.True(t, drawnNear(stages[DebugAlignment], centre(22, 22), debugBlue))
+
+	// the top left module is dark, the one diagonally inside it light
+	assert.True(t, drawnNear(stages[DebugGrid], centre(0, 0), debugRed))
+	assert.True(t, drawnNear(stages[DebugGrid], centre(1, 1), debugBlue))
+	assert.False(t, drawnNear(stages[DebugGrid], centre(1, 1), debugRed))
+}
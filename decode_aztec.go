@@ -0,0 +1,517 @@
+package ar8t
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/mrg0lden/ar8t/reedsolomon"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	errAztecSize = errors.New("not the size of an Aztec Code")
+	errAztecData = errors.New("invalid Aztec Code data")
+)
+
+// AztecDecoder decodes compact Aztec Codes, with 1 to 4 layers, and full-range ones with 1 to 32.
+// QRData.Modules is the whole symbol, upright as its orientation marks put it. The mode message
+// around the bullseye gives the number of layers, QRData.Version is not used.
+type AztecDecoder struct{}
+
+// AztecModeMessage is the mode message of an Aztec Code
+type AztecModeMessage struct {
+	Compact bool
+
+	Layers    int //1 .. 4 for compact symbols, 1 .. 32 for full-range ones
+	DataWords int //the number of data codewords, the rest of the layers being error correction
+}
+
+// aztecPatterns caches the data module order of compact symbols, then full-range ones
+var aztecPatterns [36]struct {
+	once      sync.Once
+	dataOrder []uint32
+}
+
+func (AztecDecoder) Decode(qrData QRData) ([]byte, error) {
+	mode, err := DecodeAztecModeMessage(qrData)
+	if err != nil {
+		return nil, err
+	}
+
+	order := getAztecPatterns(mode.Compact, mode.Layers)
+
+	field, wordSize := aztecField(mode.Layers)
+	count := len(order) / wordSize
+	if mode.DataWords > count {
+		return nil, errAztecData
+	}
+
+	// the layers hold a whole number of codewords, after the first few bits
+	skip := len(order) % wordSize
+
+	words := make([]int, count)
+	confidence := make([]byte, count)
+	for i := range words {
+		confidence[i] = 255
+		for _, index := range order[skip+i*wordSize : skip+(i+1)*wordSize] {
+			x, y := int(index)%qrData.Modules.Width(), int(index)/qrData.Modules.Width()
+			words[i] = words[i]<<1 | int(qrData.Modules.bit(x, y))
+			confidence[i] = min(confidence[i], qrData.confidence(index))
+		}
+	}
+
+	corrected, err := correctAztec(field, words, count-mode.DataWords, confidence, qrData.Confidence != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// a codeword of all zeros or all ones but its last bit has that bit stuffed,
+	// so that no codeword is all zeros or all ones
+	mask := 1<<wordSize - 1
+	bits := make([]bool, 0, mode.DataWords*wordSize)
+	for _, word := range corrected[:mode.DataWords] {
+		switch word {
+		case 0, mask:
+			return nil, errAztecData
+		case 1, mask - 1:
+			for i := 0; i < wordSize-1; i++ {
+				bits = append(bits, word > 1)
+			}
+		default:
+			for i := wordSize - 1; i >= 0; i-- {
+				bits = append(bits, word>>i&1 == 1)
+			}
+		}
+	}
+
+	return AztecData(bits)
+}
+
+// correctAztec corrects codewords in the field of their size, falling back to treating
+// the least confident codewords as erasures the way correctSoft does
+func correctAztec(field *reedsolomon.Field, words []int, ecLen int, confidence []byte, soft bool) ([]int, error) {
+	corrected := slices.Clone(words)
+
+	_, err := field.Decode(corrected, ecLen, nil)
+	if err == nil || !soft {
+		return corrected, err
+	}
+
+	candidates := erasureCandidates(confidence, ecLen)
+
+	for n := 1; n <= len(candidates); n++ {
+		corrected = slices.Clone(words)
+		if _, erasureErr := field.Decode(corrected, ecLen, candidates[:n]); erasureErr == nil {
+			return corrected, nil
+		}
+	}
+
+	return nil, err
+}
+
+// aztecField is the field of the data codewords, and their size in bits, for a number of layers
+func aztecField(layers int) (*reedsolomon.Field, int) {
+	switch {
+	case layers <= 2:
+		return reedsolomon.AztecData6, 6
+	case layers <= 8:
+		return reedsolomon.AztecData8, 8
+	case layers <= 22:
+		return reedsolomon.AztecData10, 10
+	default:
+		return reedsolomon.AztecData12, 12
+	}
+}
+
+// DecodeAztecModeMessage reads the mode message around the bullseye of an Aztec Code.
+// Full-range symbols are told apart from compact ones of the same size by the extra ring
+// of their bullseye, the number of layers is then checked against the size of the modules.
+func DecodeAztecModeMessage(data QRData) (AztecModeMessage, error) {
+	size := data.Modules.Width()
+	if size != data.Modules.Height() || size < 15 {
+		return AztecModeMessage{}, errAztecSize
+	}
+
+	compact := !aztecFullRange(data.Modules)
+	center := size / 2
+
+	bits := []bool{}
+	for _, module := range aztecModeMessageModules(compact) {
+		bits = append(bits, data.Modules.Get(center+module[0], center+module[1]))
+	}
+
+	mode, err := aztecModeMessage(bits, compact)
+	if err != nil {
+		return AztecModeMessage{}, err
+	}
+
+	if s, err := AztecSize(mode.Compact, mode.Layers); err != nil || s != size {
+		return AztecModeMessage{}, errVersionMismatch
+	}
+
+	return mode, nil
+}
+
+// aztecModeMessage corrects the 28 bits of a compact mode message, or the 40 bits
+// of a full-range one, 4 bit codewords in GF(16) that are mostly error correction
+func aztecModeMessage(bits []bool, compact bool) (AztecModeMessage, error) {
+	words := make([]int, len(bits)/4)
+	for i, bit := range bits {
+		if bit {
+			words[i/4] |= 1 << (3 - i%4)
+		}
+	}
+
+	dataWords := 4
+	if compact {
+		dataWords = 2
+	}
+
+	if _, err := reedsolomon.AztecParam.Decode(words, len(words)-dataWords, nil); err != nil {
+		return AztecModeMessage{}, err
+	}
+
+	data := 0
+	for _, word := range words[:dataWords] {
+		data = data<<4 | word
+	}
+
+	if compact {
+		return AztecModeMessage{Compact: true, Layers: data>>6 + 1, DataWords: data&0x3F + 1}, nil
+	}
+
+	return AztecModeMessage{Layers: data>>11 + 1, DataWords: data&0x7FF + 1}, nil
+}
+
+// aztecFullRange reports whether the bullseye of a symbol has the ring of full-range symbols,
+// 6 modules from its centre, which is part of the data in compact ones
+func aztecFullRange(modules BitMatrix) bool {
+	size := modules.Width()
+	if size == 15 {
+		return false
+	}
+
+	center, dark := size/2, 0
+	for _, m := range ringModules(6) {
+		if modules.Get(center+m[0], center+m[1]) {
+			dark++
+		}
+	}
+
+	// a few errors are allowed, a ring of data is about half dark
+	return dark >= 44
+}
+
+// ringModules are the 8*r modules r modules away from the centre of a bullseye
+func ringModules(r int) [][2]int {
+	modules := make([][2]int, 0, 8*r)
+	for i := -r; i < r; i++ {
+		modules = append(modules, [2]int{i, -r}, [2]int{r, i}, [2]int{-i, r}, [2]int{-r, -i})
+	}
+	return modules
+}
+
+// aztecModeMessageModules are the modules of the mode message from the centre of the bullseye,
+// clockwise from the top left, most significant bit first. The mode message of full-range symbols
+// skips the reference grid line through the centre.
+func aztecModeMessageModules(compact bool) [][2]int {
+	side, radius := 10, 7
+	offset := func(i int) int {
+		return i - 5 + i/5
+	}
+
+	if compact {
+		side, radius = 7, 5
+		offset = func(i int) int {
+			return i - 3
+		}
+	}
+
+	modules := make([][2]int, 0, 4*side)
+	for i := 0; i < side; i++ {
+		modules = append(modules, [2]int{offset(i), -radius})
+	}
+	for i := 0; i < side; i++ {
+		modules = append(modules, [2]int{radius, offset(i)})
+	}
+	for i := side - 1; i >= 0; i-- {
+		modules = append(modules, [2]int{offset(i), radius})
+	}
+	for i := side - 1; i >= 0; i-- {
+		modules = append(modules, [2]int{-radius, offset(i)})
+	}
+
+	return modules
+}
+
+// aztecOrientation are the modules in the corners of the ring of the mode message, from the centre
+// of the bullseye, and whether they are dark. The top left corner has three dark modules,
+// the top right two, the bottom right one and the bottom left none.
+func aztecOrientation(compact bool) [12]struct {
+	x, y int
+	dark bool
+} {
+	r := 7
+	if compact {
+		r = 5
+	}
+
+	return [12]struct {
+		x, y int
+		dark bool
+	}{
+		{-r, -r, true}, {-r + 1, -r, true}, {-r, -r + 1, true},
+		{r, -r, true}, {r - 1, -r, false}, {r, -r + 1, true},
+		{r, r, false}, {r, r - 1, true}, {r - 1, r, false},
+		{-r, r, false}, {-r, r - 1, false}, {-r + 1, r, false},
+	}
+}
+
+// AztecSize is the side in modules of an Aztec Code, reference grid included
+func AztecSize(compact bool, layers int) (int, error) {
+	if layers < 1 || (compact && layers > 4) || layers > 32 {
+		return 0, errUnsupportedVersion
+	}
+
+	if compact {
+		return 11 + 4*layers, nil
+	}
+
+	// a reference grid line every 16 modules from the centre
+	base := 14 + 4*layers
+	return base + 1 + 2*((base/2-1)/15), nil
+}
+
+// getAztecPatterns computes the data module order of an Aztec Code once
+func getAztecPatterns(compact bool, layers int) []uint32 {
+	i := layers + 3
+	if compact {
+		i = layers - 1
+	}
+
+	p := &aztecPatterns[i]
+	p.once.Do(func() {
+		p.dataOrder = aztecDataOrder(compact, layers)
+	})
+
+	return p.dataOrder
+}
+
+// aztecDataOrder is the order of the data modules of an Aztec Code, from the outermost layer
+// inwards. Every layer is two modules thick and is read in four sides, clockwise from its top
+// left corner: the left side down, the bottom side right, the right side up then the top side left,
+// two modules across the side at a time, the outermost first.
+// Each module is returned as an index into the symbol.
+func aztecDataOrder(compact bool, layers int) []uint32 {
+	base := 4 * layers
+	if compact {
+		base += 11
+	} else {
+		base += 14
+	}
+
+	size, _ := AztecSize(compact, layers)
+
+	// positions without the reference grid, into the symbol
+	position := make([]int, base)
+	if compact {
+		for i := range position {
+			position[i] = i
+		}
+	} else {
+		half, center := base/2, size/2
+		for i := 0; i < half; i++ {
+			position[half-i-1] = center - i - i/15 - 1
+			position[half+i] = center + i + i/15 + 1
+		}
+	}
+
+	index := func(x, y int) uint32 {
+		return uint32(position[y]*size + position[x])
+	}
+
+	order := make([]uint32, 0, 8*layers*(base/2))
+
+	for layer := 0; layer < layers; layer++ {
+		length := 4*(layers-layer) + 12
+		if compact {
+			length -= 3
+		}
+
+		low, high := 2*layer, base-1-2*layer
+
+		sides := [4][]uint32{}
+		for j := 0; j < length; j++ {
+			for k := 0; k < 2; k++ {
+				sides[0] = append(sides[0], index(low+k, low+j))
+				sides[1] = append(sides[1], index(low+j, high-k))
+				sides[2] = append(sides[2], index(high-k, high-j))
+				sides[3] = append(sides[3], index(high-j, low+k))
+			}
+		}
+
+		for _, side := range sides {
+			order = append(order, side...)
+		}
+	}
+
+	return order
+}
+
+// aztecMode is a character mode of Aztec Codes, or the byte mode
+type aztecMode int
+
+const (
+	aztecUpper aztecMode = iota
+	aztecLower
+	aztecMixed
+	aztecPunct
+	aztecDigit
+	aztecBinary
+)
+
+// aztecCharacters are the characters of every character mode by code, the shifts and latches
+// to other modes being empty
+var aztecCharacters = [...][]string{
+	aztecUpper: {
+		"", " ", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N",
+		"O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "", "", "", "",
+	},
+	aztecLower: {
+		"", " ", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n",
+		"o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z", "", "", "", "",
+	},
+	aztecMixed: {
+		"", " ", "\x01", "\x02", "\x03", "\x04", "\x05", "\x06", "\x07", "\b", "\t", "\n", "\v", "\f", "\r", "\x1B",
+		"\x1C", "\x1D", "\x1E", "\x1F", "@", "\\", "^", "_", "`", "|", "~", "\x7F", "", "", "", "",
+	},
+	aztecPunct: {
+		"", "\r", "\r\n", ". ", ", ", ": ", "!", "\"", "#", "$", "%", "&", "'", "(", ")", "*",
+		"+", ",", "-", ".", "/", ":", ";", "<", "=", ">", "?", "[", "]", "{", "}", "",
+	},
+	aztecDigit: {
+		"", " ", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9", ",", ".", "", "",
+	},
+}
+
+// aztecSwitch is a shift, for the next character only, or a latch to another mode
+type aztecSwitch struct {
+	mode  aztecMode
+	shift bool
+}
+
+// aztecSwitches are the shifts and latches of every character mode by code
+var aztecSwitches = [...]map[int]aztecSwitch{
+	aztecUpper: {0: {aztecPunct, true}, 28: {aztecLower, false}, 29: {aztecMixed, false}, 30: {aztecDigit, false}, 31: {aztecBinary, true}},
+	aztecLower: {0: {aztecPunct, true}, 28: {aztecUpper, true}, 29: {aztecMixed, false}, 30: {aztecDigit, false}, 31: {aztecBinary, true}},
+	aztecMixed: {0: {aztecPunct, true}, 28: {aztecLower, false}, 29: {aztecUpper, false}, 30: {aztecPunct, false}, 31: {aztecBinary, true}},
+	aztecPunct: {31: {aztecUpper, false}},
+	aztecDigit: {0: {aztecPunct, true}, 14: {aztecUpper, false}, 15: {aztecUpper, true}},
+}
+
+// AztecData decodes the data bits of an Aztec Code, stuffed bits removed. Decoding starts
+// in the upper case mode and ends when the bits left are too few for another code.
+// ECI designators are skipped, bytes are returned as they are encoded.
+func AztecData(bits []bool) ([]byte, error) {
+	var (
+		result = bytes.Buffer{}
+		index  = 0
+
+		// latch is the mode returned to after a shift, mode the mode of the next code
+		latch, mode = aztecUpper, aztecUpper
+	)
+
+	read := func(n int) (int, bool) {
+		if len(bits)-index < n {
+			return 0, false
+		}
+
+		code := 0
+		for _, bit := range bits[index : index+n] {
+			code <<= 1
+			if bit {
+				code |= 1
+			}
+		}
+		index += n
+
+		return code, true
+	}
+
+	for {
+		if mode == aztecBinary {
+			length, ok := read(5)
+			if !ok {
+				break
+			}
+
+			if length == 0 {
+				if length, ok = read(11); !ok {
+					break
+				}
+				length += 31
+			}
+
+			for i := 0; i < length; i++ {
+				b, ok := read(8)
+				if !ok {
+					break
+				}
+				result.WriteByte(byte(b))
+			}
+
+			mode = latch
+			continue
+		}
+
+		size := 5
+		if mode == aztecDigit {
+			size = 4
+		}
+
+		code, ok := read(size)
+		if !ok {
+			break
+		}
+
+		if mode == aztecPunct && code == 0 {
+			// FLG(n), FNC1 when n is 0 or an ECI designator of n digits
+			n, ok := read(3)
+			if !ok {
+				break
+			}
+
+			switch n {
+			case 0:
+				result.WriteByte(0x1D)
+			case 7:
+				return nil, errAztecData
+			default:
+				for i := 0; i < n; i++ {
+					if digit, ok := read(4); !ok || digit < 2 || digit > 11 {
+						return nil, errAztecData
+					}
+				}
+			}
+
+			mode = latch
+			continue
+		}
+
+		if s, ok := aztecSwitches[mode][code]; ok {
+			// a shift returns to the mode it was made from, even from another shift
+			latch = mode
+			mode = s.mode
+			if !s.shift {
+				latch = mode
+			}
+			continue
+		}
+
+		result.WriteString(aztecCharacters[mode][code])
+		mode = latch
+	}
+
+	return result.Bytes(), nil
+}
@@ -0,0 +1,254 @@
+package ar8t
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// aztecTests are Aztec Codes, compact and full-range, the damaged ones drawn undamaged by Test_AztecScan
+var aztecTests = []decoderTest[struct{}]{
+	{
+		name: "compact 1 layer",
+		symbol: `
+			.........#####.
+			....#.#.#....#.
+			.###.....#..#.#
+			.#############.
+			...#.......#.#.
+			##.#.#####.##.#
+			####.#...#.#...
+			##.#.#.#.#.##..
+			.#.#.#...#.#...
+			.#.#.#####.#.##
+			.#.#.......#...
+			##.############
+			.#..###.###....
+			#####.##.##.###
+			..##.#...#...#.
+		`,
+		want: "AR8T",
+	},
+	{
+		name: "compact 2 layers with errors",
+		symbol: `
+			..#..##...##...###.
+			.#.##.#.###...##..#
+			.####...##.#...##..
+			##.#...#.#.##...#..
+			....##.#..###.##..#
+			###.############..#
+			.#...#.......#.##..
+			##...#.#####.##.#.#
+			.#..##.#...#.#..###
+			.#.#.#.#.#.#.#..###
+			..##.#.#...#.#####.
+			##...#.#####.##.#..
+			..####.......#...#.
+			.#.#.##########...#
+			...#..###......#...
+			...#.##.#.######.#.
+			......#..#.....#...
+			##...#.....#.##...#
+			##..#....##.###...#
+		`,
+		flipped: [][2]int{{0, 0}, {18, 9}, {9, 1}},
+		want:    "Aztec Code 2026",
+	},
+	{
+		name: "full-range 1 layer",
+		symbol: `
+			....#.###..#.######
+			###.#...###..##...#
+			#.##............###
+			..###############.#
+			.#.#...........#...
+			####.#########.#...
+			#.##.#.......#.###.
+			##.#.#.#####.#.#.##
+			..##.#.#...#.#.#...
+			.#.#.#.#.#.#.#.#.#.
+			##.#.#.#...#.#.###.
+			.###.#.#####.#.####
+			.#.#.#.......#.#...
+			#..#.#########.##.#
+			#.##...........#.#.
+			...###############.
+			....#.....##.##..#.
+			.##.#.#.####..#.##.
+			#...#...#.#....##..
+		`,
+		want: "full-range",
+	},
+	{
+		name: "full-range 6 layers across the reference grid",
+		symbol: `
+			...##.##.##.#.#.#...##..####...##...#.##.
+			###..#..#.#..#.#.#...#.#....#.....##...#.
+			###.#.##....####..#.###.#..#.#.##..##..#.
+			##.#.####.....######.#..##.##.#..#......#
+			#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#
+			..##.##.##.#..#####..#......##....#......
+			#.#.#...#.##..#....##............##.##..#
+			##.#.#......#...##....#.##..##.##........
+			....##.#.##.#..##...#...#...##.#.#..#....
+			##....#.#######.#.#....######..#..##....#
+			.######.####..###.#.######..#..#.##.##...
+			.#....##..#.#.##..##.#...#.#....##.#.##..
+			#...#....#...####..#####.#.###.#...###...
+			#..#.#..##.#.##..#.#.......#.....###.#...
+			...####.######################..#.#.###.#
+			......#.#.#####...........##....#..#..#.#
+			#..###.##..#.##.#########.##.###..#.#####
+			.#...#...#..###.#.......#.#.#.##.#.#.....
+			.####..#..##.##.#.#####.#.#.......#.##.#.
+			...#.#...##.#.#.#.#...#.#.#..#...##...###
+			#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#
+			##.#.##.##...##.#.#...#.#.#...#...##..#..
+			....#..####..##.#.#####.#.##.####.#.##..#
+			.#.#..###.#.#.#.#.......#.#.###..#...#..#
+			...####..###..#.#########.###.#.######.#.
+			##....#.##..#.#...........##....###...##.
+			##..####..#...###############.#..#..#....
+			.#....##.#..#..##.#....#.#..###.#....###.
+			...###..####......#.#.##..#.#..#...##.###
+			...#..#..#.##...####.##..#.#..##..#..##.#
+			.#####.#....#.#..####.#..#...##...####...
+			#.##......#.#.##.###...##...#.##..#......
+			....##...#####.####.#.##...##.#...####.#.
+			...#.###.###......##.####.###.####.#....#
+			##..#####.#..#.##.###..##.#...####.#####.
+			##.#.#...#..###..#...##..#...#.#.#....##.
+			#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#
+			........#.##.#.#####.##.##.#.#.##.#...##.
+			..###.###.##...#...######..##.#.#.#.###..
+			##.#....#.....##..#.....##.#..#...##...#.
+			..######.#.#...##...##.....#...#...##..##
+		`,
+		flipped: [][2]int{{0, 0}, {4, 12}, {36, 30}, {20, 39}},
+		want:    "Full-range Aztec Code of six layers, across the reference grid: 0123456789",
+	},
+}
+
+func Test_AztecDecoder(t *testing.T) {
+	testDecoder(t, aztecTests, func(t *testing.T, modules BitMatrix, _ struct{}) ([]byte, error) {
+		return AztecDecoder{}.Decode(QRData{Modules: modules})
+	})
+}
+
+func Test_AztecScan(t *testing.T) {
+	for _, tt := range aztecTests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDecodeAll(t, SymbologyAztec, tt.symbol, 6, tt.want, 0, 90, 180, 270, 30)
+		})
+	}
+}
+
+// aztecBits unpacks a string of 0s and 1s, spaces are ignored
+func aztecBits(s string) []bool {
+	bits := []bool{}
+	for _, b := range strings.ReplaceAll(s, " ", "") {
+		bits = append(bits, b == '1')
+	}
+	return bits
+}
+
+func Test_AztecData(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "upper with padding",
+			bits: "00010 00011 000",
+			want: "AB",
+		},
+		{
+			name: "lower latch and upper shift",
+			bits: "11100 00010 11100 00011 00100",
+			want: "aBc",
+		},
+		{
+			name: "mixed latch",
+			bits: "11101 10100 01010 11101 00010",
+			want: "@\tA",
+		},
+		{
+			name: "mixed to lower",
+			bits: "11101 00100 11100 00010",
+			want: "\x03a",
+		},
+		{
+			name: "punctuation shift",
+			bits: "00010 00000 00110 00000 00010 00011",
+			want: "A!\r\nB",
+		},
+		{
+			name: "punctuation latch",
+			bits: "11101 11110 00011 10001 11101 11111 00010",
+			want: ". ,{A",
+		},
+		{
+			name: "digit latch",
+			bits: "11110 0011 0100 1100 0001 1101 1110 00010",
+			want: "12, .A",
+		},
+		{
+			name: "digit upper and punctuation shifts",
+			bits: "11110 0011 1111 00010 0000 10010 0100",
+			want: "1A-2",
+		},
+		{
+			name: "binary shift",
+			bits: "00010 11111 00010 11101001 00000000 00011",
+			want: "A\xE9\x00B",
+		},
+		{
+			name: "binary shift back to lower",
+			bits: "11100 11111 00001 11111111 00010",
+			want: "\xFFa",
+		},
+		{
+			name: "binary shift of more than 31 bytes",
+			bits: "11111 00000 00000000001 " + strings.Repeat("01000001 ", 32) + "00011",
+			want: strings.Repeat("A", 32) + "B",
+		},
+		{
+			name: "FNC1",
+			bits: "00000 00000 000 00010",
+			want: "\x1DA",
+		},
+		{
+			name: "ECI designator",
+			bits: "00000 00000 010 0100 1000 00010",
+			want: "A",
+		},
+		{
+			name:    "ECI designator of a non digit",
+			bits:    "00000 00000 001 1100 00010",
+			wantErr: errAztecData,
+		},
+		{
+			name:    "reserved FLG(7)",
+			bits:    "00000 00000 111 00010",
+			wantErr: errAztecData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AztecData(aztecBits(tt.bits))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
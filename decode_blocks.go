@@ -1,19 +1,32 @@
 package ar8t
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 type codewords struct {
 	currentByte, bitCount byte
 	blocks                blocks
+
+	// the lowest confidence of the bits in currentByte, scaled to 0-255.
+	// confidences are distributed into blocks the same way codewords are
+	currentConfidence byte
+	confidences       blocks
 }
 
-func (c *codewords) addBit(bit byte) {
+func (c *codewords) addBit(bit byte, confidence byte) {
 	c.currentByte *= 2 //shifts to the left by one
 	c.currentByte += bit
 	c.bitCount++
 
+	if c.bitCount == 1 || confidence < c.currentConfidence {
+		c.currentConfidence = confidence
+	}
+
 	if c.bitCount == 8 {
 		c.blocks.push(c.currentByte)
+		c.confidences.push(c.currentConfidence)
 		c.currentByte = 0
 		c.bitCount = 0
 	}
@@ -80,63 +93,82 @@ type alignmentLocation struct {
 type QRMask = func(QRData, uint32, uint32) byte
 
 func Blocks(data QRData, level ECLevel, mask QRMask) ([][]byte, error) {
-	blockInfo, err := GetBlockInfo(data.Version, level)
-	if err != nil {
-		return nil, err
+	blocks, _, err := readBlocks(data, level, func(*versionPatterns) BitMatrix {
+		// one module at a time, QRDecoder uses the precomputed mask patterns instead
+		unmasked := NewBitMatrix(int(data.Side), int(data.Side))
+		for y := uint32(0); y < data.Side; y++ {
+			for x := uint32(0); x < data.Side; x++ {
+				unmasked.Set(int(x), int(y), mask(data, x, y) == 1)
+			}
+		}
+		return unmasked
+	})
+	return blocks, err
+}
+
+// blocksWithConfidence reads the codewords from the modules of data, mask being the data mask pattern reference.
+// It also returns the confidence of every codeword in blocks,
+// the lowest confidence of its modules scaled to 0-255.
+// Without QRData.Confidence every codeword is fully confident.
+func blocksWithConfidence(data QRData, level ECLevel, mask byte) ([][]byte, [][]byte, error) {
+	if int(mask) >= len(maskPredicates) {
+		return nil, nil, errNoMask
 	}
 
-	codewords := codewords{blocks: newBlocks(blockInfo)}
-	x := data.Side - 1
+	return readBlocks(data, level, func(patterns *versionPatterns) BitMatrix {
+		return data.Modules.Xor(patterns.masks[mask])
+	})
+}
 
-	loc, err := getAlignmentLocation(data.Version)
+// readBlocks is blocksWithConfidence, with the modules of data unmasked by unmask
+func readBlocks(data QRData, level ECLevel, unmask func(*versionPatterns) BitMatrix) ([][]byte, [][]byte, error) {
+	blockInfo, err := GetBlockInfo(data.Version, level)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	for {
-		yRange := yRange(x, data.Side)
-		for y := uint32(0); y < data.Side; y++ {
-			y := yRange(y)
+	patterns, err := getVersionPatterns(data.Version)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			if isData(data, loc, x, y) {
-				codewords.addBit(mask(data, x, y))
-			}
+	if data.Modules.Width() != int(data.Side) || data.Modules.Height() != int(data.Side) {
+		return nil, nil, fmt.Errorf("expected %dx%d modules for version %d but found %dx%d",
+			data.Side, data.Side, data.Version, data.Modules.Width(), data.Modules.Height())
+	}
 
-			if isData(data, loc, x-1, y) {
-				codewords.addBit(mask(data, x-1, y))
-			}
-		}
+	codewords := codewords{blocks: newBlocks(blockInfo), confidences: newBlocks(blockInfo)}
 
-		if x == 1 {
-			break
-		}
+	unmasked := unmask(patterns)
 
-		x -= 2
-		if x == 6 {
-			// skip timing pattern
-			x = 5
-		}
+	for _, index := range patterns.dataOrder {
+		x, y := int(index%data.Side), int(index/data.Side)
+		codewords.addBit(unmasked.bit(x, y), data.confidence(index))
 	}
 
-	blockInfo, err = GetBlockInfo(data.Version, level)
-	if err != nil {
-		return nil, fmt.Errorf("blocks: failed to get block info: %w", err)
-	}
 	blocks := codewords.blocks.blocks
 
 	if len(blocks) != len(blockInfo) {
-		return nil, fmt.Errorf("expected %d blocks but found %d",
+		return nil, nil, fmt.Errorf("expected %d blocks but found %d",
 			len(blockInfo), len(blocks))
 	}
 
 	for i := range blocks {
 		if int(blockInfo[i].TotalPer) != len(blocks[i]) {
-			return nil, fmt.Errorf("expected %d codewords in block %d but found %d",
+			return nil, nil, fmt.Errorf("expected %d codewords in block %d but found %d",
 				blockInfo[i].TotalPer, i, len(blocks[i]))
 		}
 	}
 
-	return blocks, nil
+	return blocks, codewords.confidences.blocks, nil
+}
+
+// confidence of the module at index scaled to 0-255, fully confident without QRData.Confidence
+func (d QRData) confidence(index uint32) byte {
+	if int(index) >= len(d.Confidence) {
+		return 255
+	}
+	return byte(math.Round(d.Confidence[index] * 255))
 }
 
 func yRange(x, side uint32) func(i uint32) uint32 {
@@ -0,0 +1,197 @@
+package ar8t
+
+import "bytes"
+
+// code128Patterns are the runs of the 107 Code 128 symbols, 11 modules wide starting with a dark run,
+// the stop symbol being 13 modules wide with one more dark run
+var code128Patterns = [][]int{
+	{2, 1, 2, 2, 2, 2}, {2, 2, 2, 1, 2, 2}, {2, 2, 2, 2, 2, 1}, {1, 2, 1, 2, 2, 3}, {1, 2, 1, 3, 2, 2},
+	{1, 3, 1, 2, 2, 2}, {1, 2, 2, 2, 1, 3}, {1, 2, 2, 3, 1, 2}, {1, 3, 2, 2, 1, 2}, {2, 2, 1, 2, 1, 3},
+	{2, 2, 1, 3, 1, 2}, {2, 3, 1, 2, 1, 2}, {1, 1, 2, 2, 3, 2}, {1, 2, 2, 1, 3, 2}, {1, 2, 2, 2, 3, 1},
+	{1, 1, 3, 2, 2, 2}, {1, 2, 3, 1, 2, 2}, {1, 2, 3, 2, 2, 1}, {2, 2, 3, 2, 1, 1}, {2, 2, 1, 1, 3, 2},
+	{2, 2, 1, 2, 3, 1}, {2, 1, 3, 2, 1, 2}, {2, 2, 3, 1, 1, 2}, {3, 1, 2, 1, 3, 1}, {3, 1, 1, 2, 2, 2},
+	{3, 2, 1, 1, 2, 2}, {3, 2, 1, 2, 2, 1}, {3, 1, 2, 2, 1, 2}, {3, 2, 2, 1, 1, 2}, {3, 2, 2, 2, 1, 1},
+	{2, 1, 2, 1, 2, 3}, {2, 1, 2, 3, 2, 1}, {2, 3, 2, 1, 2, 1}, {1, 1, 1, 3, 2, 3}, {1, 3, 1, 1, 2, 3},
+	{1, 3, 1, 3, 2, 1}, {1, 1, 2, 3, 1, 3}, {1, 3, 2, 1, 1, 3}, {1, 3, 2, 3, 1, 1}, {2, 1, 1, 3, 1, 3},
+	{2, 3, 1, 1, 1, 3}, {2, 3, 1, 3, 1, 1}, {1, 1, 2, 1, 3, 3}, {1, 1, 2, 3, 3, 1}, {1, 3, 2, 1, 3, 1},
+	{1, 1, 3, 1, 2, 3}, {1, 1, 3, 3, 2, 1}, {1, 3, 3, 1, 2, 1}, {3, 1, 3, 1, 2, 1}, {2, 1, 1, 3, 3, 1},
+	{2, 3, 1, 1, 3, 1}, {2, 1, 3, 1, 1, 3}, {2, 1, 3, 3, 1, 1}, {2, 1, 3, 1, 3, 1}, {3, 1, 1, 1, 2, 3},
+	{3, 1, 1, 3, 2, 1}, {3, 3, 1, 1, 2, 1}, {3, 1, 2, 1, 1, 3}, {3, 1, 2, 3, 1, 1}, {3, 3, 2, 1, 1, 1},
+	{3, 1, 4, 1, 1, 1}, {2, 2, 1, 4, 1, 1}, {4, 3, 1, 1, 1, 1}, {1, 1, 1, 2, 2, 4}, {1, 1, 1, 4, 2, 2},
+	{1, 2, 1, 1, 2, 4}, {1, 2, 1, 4, 2, 1}, {1, 4, 1, 1, 2, 2}, {1, 4, 1, 2, 2, 1}, {1, 1, 2, 2, 1, 4},
+	{1, 1, 2, 4, 1, 2}, {1, 2, 2, 1, 1, 4}, {1, 2, 2, 4, 1, 1}, {1, 4, 2, 1, 1, 2}, {1, 4, 2, 2, 1, 1},
+	{2, 4, 1, 2, 1, 1}, {2, 2, 1, 1, 1, 4}, {4, 1, 3, 1, 1, 1}, {2, 4, 1, 1, 1, 2}, {1, 3, 4, 1, 1, 1},
+	{1, 1, 1, 2, 4, 2}, {1, 2, 1, 1, 4, 2}, {1, 2, 1, 2, 4, 1}, {1, 1, 4, 2, 1, 2}, {1, 2, 4, 1, 1, 2},
+	{1, 2, 4, 2, 1, 1}, {4, 1, 1, 2, 1, 2}, {4, 2, 1, 1, 1, 2}, {4, 2, 1, 2, 1, 1}, {2, 1, 2, 1, 4, 1},
+	{2, 1, 4, 1, 2, 1}, {4, 1, 2, 1, 2, 1}, {1, 1, 1, 1, 4, 3}, {1, 1, 1, 3, 4, 1}, {1, 3, 1, 1, 4, 1},
+	{1, 1, 4, 1, 1, 3}, {1, 1, 4, 3, 1, 1}, {4, 1, 1, 1, 1, 3}, {4, 1, 1, 3, 1, 1}, {1, 1, 3, 1, 4, 1},
+	{1, 1, 4, 1, 3, 1}, {3, 1, 1, 1, 4, 1}, {4, 1, 1, 1, 3, 1}, {2, 1, 1, 4, 1, 2}, {2, 1, 1, 2, 1, 4},
+	{2, 1, 1, 2, 3, 2}, {2, 3, 3, 1, 1, 1, 2},
+}
+
+const (
+	code128FNC1   = 102
+	code128StartA = 103
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+
+	code128MaxVariance = 0.25
+)
+
+// readCode128 reads a Code 128 barcode, checking its check symbol
+func readCode128(runs []int, start int) (Symbology, []byte, int, bool) {
+	symbol := func(i int) (int, bool) {
+		if i+6 > len(runs) {
+			return 0, false
+		}
+		// the stop symbol has 7 runs, the others 6
+		return bestPattern(runs[i:], code128Patterns, code128MaxVariance)
+	}
+
+	// the light run before the barcode is at least half as wide as the start symbol
+	if start+6 > len(runs) || float64(runs[start-1]) < float64(sum(runs[start:start+6]))/2 {
+		return 0, nil, 0, false
+	}
+
+	first, ok := bestPattern(runs[start:], code128Patterns[code128StartA:code128Stop], code128MaxVariance)
+	if !ok {
+		return 0, nil, 0, false
+	}
+	first += code128StartA
+
+	symbols := []int{first}
+	i := start + 6
+	for {
+		s, ok := symbol(i)
+		if !ok {
+			return 0, nil, 0, false
+		}
+
+		if s == code128Stop {
+			i += 7
+			break
+		}
+
+		symbols = append(symbols, s)
+		i += 6
+	}
+
+	if i >= len(runs) || float64(runs[i]) < float64(sum(runs[i-7:i]))/2 || len(symbols) < 2 {
+		return 0, nil, 0, false
+	}
+
+	// the check symbol is the sum of the symbols weighted by their position, the start symbol by 1
+	check := symbols[0]
+	for j, s := range symbols[1 : len(symbols)-1] {
+		check += (j + 1) * s
+	}
+
+	if check%103 != symbols[len(symbols)-1] {
+		return 0, nil, 0, false
+	}
+
+	data, ok := code128Data(symbols[:len(symbols)-1])
+	if !ok {
+		return 0, nil, 0, false
+	}
+
+	return SymbologyCode128, data, i, true
+}
+
+// code128Data decodes the symbols of a Code 128 barcode from its start symbol to before its check symbol.
+// Code sets A and B are ASCII, A having the control characters and B the lower case letters,
+// code set C has two digits in each symbol.
+func code128Data(symbols []int) ([]byte, bool) {
+	const (
+		setA = iota
+		setB
+		setC
+	)
+
+	var (
+		result = bytes.Buffer{}
+		set    = symbols[0] - code128StartA
+
+		// shift is set for a single symbol of code set A or B after a shift symbol
+		shift = -1
+
+		// FNC4 adds 128 to the next character, or to every one when it is latched by two of them
+		fnc4, fnc4Latched bool
+	)
+
+	for i := 1; i < len(symbols); i++ {
+		s := symbols[i]
+
+		current := set
+		if shift != -1 {
+			current, shift = shift, -1
+		}
+
+		if s >= code128StartA {
+			return nil, false
+		}
+
+		if current == setC {
+			switch s {
+			case 100:
+				set = setB
+			case 101:
+				set = setA
+			case code128FNC1:
+				// FNC1 first only marks GS1 data, anywhere else it separates fields
+				if i > 1 {
+					result.WriteByte(0x1D)
+				}
+			default:
+				result.WriteByte(byte(s/10) + '0')
+				result.WriteByte(byte(s%10) + '0')
+			}
+			continue
+		}
+
+		// FNC4 is 101 in code set A and 100 in code set B
+		isFNC4 := (s == 101 && current == setA) || (s == 100 && current == setB)
+
+		switch {
+		case s < 96:
+			c := byte(s + ' ')
+			if s >= 64 && current == setA {
+				c = byte(s - 64)
+			}
+
+			if fnc4 != fnc4Latched {
+				c += 128
+			}
+			fnc4 = false
+
+			result.WriteByte(c)
+		case s == 96 || s == 97:
+			// FNC3 and FNC2 are for the reader
+		case s == 98:
+			shift = setA
+			if current == setA {
+				shift = setB
+			}
+		case s == 99:
+			set = setC
+		case isFNC4:
+			// two FNC4 in a row latch it
+			if fnc4 {
+				fnc4, fnc4Latched = false, !fnc4Latched
+			} else {
+				fnc4 = true
+			}
+		case s == 100:
+			set = setB
+		case s == 101:
+			set = setA
+		case s == code128FNC1:
+			if i > 1 {
+				result.WriteByte(0x1D)
+			}
+		}
+	}
+
+	return result.Bytes(), true
+}
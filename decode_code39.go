@@ -0,0 +1,94 @@
+package ar8t
+
+import "strings"
+
+const code39Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-. $/+%"
+
+// code39Patterns are the wide runs of the characters of code39Alphabet, 5 dark and 4 light
+// runs starting with a dark one, 3 of them wide. The first run is the most significant bit.
+var code39Patterns = [...]int{
+	0x034, 0x121, 0x061, 0x160, 0x031, 0x130, 0x070, 0x025, 0x124, 0x064,
+	0x109, 0x049, 0x148, 0x019, 0x118, 0x058, 0x00D, 0x10C, 0x04C, 0x01C,
+	0x103, 0x043, 0x142, 0x013, 0x112, 0x052, 0x007, 0x106, 0x046, 0x016,
+	0x181, 0x0C1, 0x1C0, 0x091, 0x190, 0x0D0, 0x085, 0x184, 0x0C4, 0x0A8,
+	0x0A2, 0x08A, 0x02A,
+}
+
+// code39StartStop is *, which starts and ends every Code 39 barcode
+const code39StartStop = 0x094
+
+// readCode39 reads a Code 39 barcode, characters being separated by a narrow light run
+func (s LinearScan) readCode39(runs []int, start int) (Symbology, []byte, int, bool) {
+	character := func(i int) (int, bool) {
+		if i+9 > len(runs) {
+			return 0, false
+		}
+		return wideRuns(runs[i:i+9], 3)
+	}
+
+	// the light run before the barcode is at least half as wide as the start character
+	if start+9 > len(runs) {
+		return 0, nil, 0, false
+	}
+
+	width := sum(runs[start : start+9])
+	if runs[start-1] < width/2 {
+		return 0, nil, 0, false
+	}
+
+	if c, ok := character(start); !ok || c != code39StartStop {
+		return 0, nil, 0, false
+	}
+
+	data := []byte{}
+	i := start + 10
+	for {
+		c, ok := character(i)
+		if !ok {
+			return 0, nil, 0, false
+		}
+
+		// the characters are about as wide as each other, with a gap narrower than the wide runs
+		if w := sum(runs[i : i+9]); w < width*3/4 || w > width*5/4 || runs[i-1] > width/4 {
+			return 0, nil, 0, false
+		}
+
+		i += 9
+		if c == code39StartStop {
+			break
+		}
+
+		found := false
+		for j, p := range code39Patterns {
+			if p == c {
+				data, found = append(data, code39Alphabet[j]), true
+			}
+		}
+
+		if !found {
+			return 0, nil, 0, false
+		}
+
+		i++
+	}
+
+	if i >= len(runs) || runs[i] < width/2 || len(data) == 0 {
+		return 0, nil, 0, false
+	}
+
+	if s.Code39CheckDigit {
+		// the check character is the sum of the values of the other ones, modulo 43
+		check := 0
+		for _, c := range data[:len(data)-1] {
+			check += strings.IndexByte(code39Alphabet, c)
+		}
+
+		if len(data) < 2 || code39Alphabet[check%43] != data[len(data)-1] {
+			return 0, nil, 0, false
+		}
+
+		data = data[:len(data)-1]
+	}
+
+	return SymbologyCode39, data, i, true
+}
@@ -1,186 +1,116 @@
 package ar8t
 
 import (
-	"errors"
 	"math/bits"
 
+	"github.com/mrg0lden/ar8t/reedsolomon"
 	"golang.org/x/exp/slices"
 )
 
-var (
-	errFailedToCalcDist  = errors.New("could not calculate error distances")
-	errFailedToFixData   = errors.New("error correcting did not fix corrupted data")
-	errFailedToCalcSigma = errors.New("could not calculate SIGMA")
-)
-
 func Correct(block []byte, blockInfo BlockInfo) ([]byte, error) {
 	res, _, err := CorrectWithErrorCount(block, blockInfo)
 	return res, err
 }
 
 func CorrectWithErrorCount(block []byte, blockInfo BlockInfo) ([]byte, int, error) {
-	syndromes, allFine := calculateSyndromes(block, blockInfo)
-	if allFine {
-		return block, 0, nil
+	return CorrectWithErasures(block, blockInfo, nil)
+}
+
+// CorrectWithErasures corrects errors at unknown positions as well as erasures,
+// codewords at known indices of block that are likely to be wrong.
+// Every erasure takes half the capacity an error at an unknown position takes,
+// so up to 2*EC_Cap erasures can be corrected when there are no other errors.
+// The error count is the number of corrected bits.
+func CorrectWithErasures(block []byte, blockInfo BlockInfo, erasures []int) ([]byte, int, error) {
+	return correctIn(reedsolomon.QRCode, block, blockInfo, erasures)
+}
+
+// correctIn is CorrectWithErasures in any GF(256) field
+func correctIn(field *reedsolomon.Field, block []byte, blockInfo BlockInfo, erasures []int) ([]byte, int, error) {
+	original := slices.Clone(block)
+
+	symbols := make([]int, len(block))
+	for i, c := range block {
+		symbols[i] = int(c)
 	}
 
-	locs, err := findLocations(blockInfo, syndromes)
+	// only EC_Cap errors are corrected even when there are more error correction codewords,
+	// the rest are there to detect misdecoding
+	_, err := field.Decode(symbols, int(blockInfo.EC_Cap)*2, erasures)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	distances, ok := calculateDistances(syndromes, locs)
-	if !ok {
-		return nil, 0, errFailedToCalcDist
-	}
-
 	errCount := 0
-
-	for i := range locs {
-		errCount += bits.OnesCount8(distances[i].v)
-		index := int(blockInfo.TotalPer) - 1 - locs[i]
-		switch {
-		case index < 0:
-			index = 0
-		case index >= int(blockInfo.TotalPer):
-			index = int(blockInfo.TotalPer) - 1
-		}
-
-		block[index] ^= distances[i].v
-	}
-
-	if syndrome(block, exp8[0]) != (gf8{}) {
-		return nil, 0, errFailedToFixData
+	for i := range block {
+		block[i] = byte(symbols[i])
+		errCount += bits.OnesCount8(block[i] ^ original[i])
 	}
 
 	return block, errCount, nil
 }
 
-func calculateSyndromes(block []byte, blockInfo BlockInfo) ([]gf8, bool) {
-	syndromes := make([]gf8, blockInfo.EC_Cap*2)
+// BlockStats tells how a block was corrected
+type BlockStats struct {
+	Codewords, DataCodewords int
 
-	allFine := true
-	for i := uint8(0); i < blockInfo.EC_Cap*2; i++ {
-		syndromes[i] = syndrome(block, exp8[i])
-		if syndromes[i] != (gf8{}) {
-			allFine = false
-		}
-	}
+	// Errors is the number of bits corrected
+	Errors int
 
-	return syndromes, allFine
+	// Erasures is the number of codewords corrected as erasures for being the least confident,
+	// see QRExtract.Gray
+	Erasures int
 }
 
-func syndrome(block []byte, base gf8) gf8 {
-	synd, alpha := gf8{0}, gf8{1}
-	iFunc := revIndex(len(block))
-	for i := range block {
-		i := iFunc(i)
-		codeword := block[i]
-		synd = synd.AddOrSub(alpha.Mul(gf8{codeword}))
-		alpha = alpha.Mul(base)
-	}
-
-	return synd
+// correctSoft falls back to treating the least confident codewords as erasures
+// when Correct fails, confidence being the confidence of every codeword in block
+func correctSoft(block []byte, blockInfo BlockInfo, confidence []byte) ([]byte, BlockStats, error) {
+	return correctSoftIn(reedsolomon.QRCode, block, blockInfo, confidence)
 }
 
-func findLocations(info BlockInfo, syndromes []gf8) ([]int, error) {
-	z := info.EC_Cap
-	eq := make([][]gf8, z)
-	for i := uint8(0); i < z; i++ {
-		eq[i] = slices.Clone(syndromes)[i : z+i+1]
-	}
+// correctSoftIn is correctSoft in any GF(256) field
+func correctSoftIn(field *reedsolomon.Field, block []byte, blockInfo BlockInfo, confidence []byte) ([]byte, BlockStats, error) {
+	original := slices.Clone(block)
+	stats := BlockStats{Codewords: len(block), DataCodewords: int(blockInfo.DataPer)}
 
-	sigma, ok := solve(eq, gf8{1}, false)
-	if !ok {
-		return nil, errFailedToCalcSigma
+	corrected, errCount, err := correctIn(field, block, blockInfo, nil)
+	if err == nil || confidence == nil {
+		stats.Errors = errCount
+		return corrected, stats, err
 	}
 
-	locs := []int{}
+	candidates := erasureCandidates(confidence, int(blockInfo.EC_Cap)*2)
 
-	for i, exp := range exp8 {
-		if uint8(i) > info.TotalPer {
-			break
-		}
-
-		var (
-			x          = exp
-			checkValue = sigma[0]
-		)
-
-		for _, s := range sigma[1:] {
-			checkValue = checkValue.AddOrSub(x.Mul(s))
-			x = x.Mul(exp)
-		}
-
-		checkValue = checkValue.AddOrSub(x)
-
-		if checkValue == (gf8{}) {
-			locs = append(locs, i)
+	for n := 1; n <= len(candidates); n++ {
+		corrected, errCount, erasureErr := correctIn(field, slices.Clone(original), blockInfo, candidates[:n])
+		if erasureErr == nil {
+			stats.Errors, stats.Erasures = errCount, n
+			return corrected, stats, nil
 		}
 	}
 
-	return locs, nil
-
+	return nil, stats, err
 }
 
-func calculateDistances(syndromes []gf8, locs []int) ([]gf8, bool) {
-	eq := make([][]gf8, len(locs))
-	for i := range locs {
-		eq[i] = make([]gf8, len(locs)+1)
+// erasureCandidates returns the indices of at most n codewords that are not confident,
+// least confident first
+func erasureCandidates(confidence []byte, n int) []int {
+	const notConfident = 128
 
-		for j := range locs {
-			eq[i][j] = exp8[(i*locs[j])%255]
+	candidates := []int{}
+	for i, c := range confidence {
+		if c < notConfident {
+			candidates = append(candidates, i)
 		}
-
-		eq[i][len(locs)] = syndromes[i]
 	}
 
-	return solve(eq, gf8{1}, false)
-}
+	slices.SortStableFunc(candidates, func(a, b int) bool {
+		return confidence[a] < confidence[b]
+	})
 
-func solve(eq [][]gf8, one gf8, failOnRank bool) ([]gf8, bool) {
-	numEq := len(eq)
-	if numEq == 0 {
-		return nil, false
-	}
-
-	numCoeff := len(eq[0])
-	if numCoeff == 0 {
-		return nil, false
-	}
-
-	for i := 0; i < numEq; i++ {
-		jFunc := revIndex(numCoeff)
-		for j := 0; j < numCoeff; j++ {
-			j := jFunc(j)
-			eq[i][j] = eq[i][j].Div(eq[i][i])
-		}
-
-		for j := i + 1; j < numEq; j++ {
-			kFunc := revIndex(numCoeff)
-			for k := i; k < numCoeff; k++ {
-				k := kFunc(k)
-				eq[j][k] = eq[j][k].AddOrSub(eq[j][i].Mul(eq[i][k]))
-			}
-		}
-
-		if failOnRank && eq[i][numCoeff-1] == one {
-			return nil, false
-		}
-	}
-
-	solution := make([]gf8, numEq)
-
-	iFunc := revIndex(numEq)
-	for i := 0; i < numEq; i++ {
-		i := iFunc(i)
-		solution[i] = eq[i][numCoeff-1]
-		jFunc := revIndex(numCoeff - 1)
-		for j := i + 1; j < numCoeff-1; j++ {
-			j := jFunc(i)
-			solution[i] = solution[i].AddOrSub(eq[i][j].Mul(solution[j]))
-		}
+	if len(candidates) > n {
+		candidates = candidates[:n]
 	}
 
-	return solution, true
+	return candidates
 }
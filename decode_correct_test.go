@@ -0,0 +1,186 @@
+package ar8t
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/mrg0lden/ar8t/reedsolomon"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CorrectWithErasures(t *testing.T) {
+	tests := []struct {
+		name string
+		// number of erasures and errors for a block of the given capacity
+		damage func(r *rand.Rand, ecCap int) (erasures, errors int)
+	}{
+		{
+			name: "no damage",
+			damage: func(*rand.Rand, int) (int, int) {
+				return 0, 0
+			},
+		},
+		{
+			name: "errors only",
+			damage: func(r *rand.Rand, ecCap int) (int, int) {
+				return 0, 1 + r.Intn(ecCap)
+			},
+		},
+		{
+			name: "at capacity",
+			damage: func(_ *rand.Rand, ecCap int) (int, int) {
+				return 0, ecCap
+			},
+		},
+		{
+			name: "erasures only",
+			damage: func(r *rand.Rand, ecCap int) (int, int) {
+				return 1 + r.Intn(2*ecCap), 0
+			},
+		},
+		{
+			name: "erasures and errors",
+			damage: func(r *rand.Rand, ecCap int) (int, int) {
+				erasures := r.Intn(2*ecCap + 1)
+				return erasures, (2*ecCap - erasures) / 2
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+
+			for version := uint32(1); version <= 40; version++ {
+				for level := ECLevelLow; level <= ECLevelHigh; level++ {
+					for _, info := range levelsBlockInfo[version][level] {
+						for trial := 0; trial < 5; trial++ {
+							data := make([]byte, info.DataPer)
+							r.Read(data)
+							want := reedsolomon.Encode(data, int(info.TotalPer-info.DataPer))
+
+							erasureCount, errorCount := tt.damage(r, int(info.EC_Cap))
+							positions := r.Perm(int(info.TotalPer))
+							erasures := positions[:erasureCount]
+
+							block := append([]byte{}, want...)
+							for _, i := range positions[:erasureCount+errorCount] {
+								block[i] ^= byte(1 + r.Intn(255))
+							}
+
+							var (
+								got []byte
+								err error
+							)
+							if erasureCount == 0 {
+								got, err = Correct(block, info)
+							} else {
+								got, _, err = CorrectWithErasures(block, info, erasures)
+							}
+
+							if assert.NoError(t, err, "version %d level %d", version, level) {
+								assert.Equal(t, want, got, "version %d level %d", version, level)
+							}
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_CorrectWithErasures_tooMany(t *testing.T) {
+	info := levelsBlockInfo[1][ECLevelMedium][0]
+	block := reedsolomon.Encode(make([]byte, info.DataPer), int(info.TotalPer-info.DataPer))
+
+	erasures := make([]int, 2*info.EC_Cap+1)
+	for i := range erasures {
+		erasures[i] = i
+	}
+
+	_, _, err := CorrectWithErasures(block, info, erasures)
+	assert.ErrorIs(t, err, reedsolomon.ErrTooManyErasures)
+}
+
+func Test_erasureCandidates(t *testing.T) {
+	tests := []struct {
+		name       string
+		confidence []byte
+		n          int
+		want       []int
+	}{
+		{"all confident", []byte{255, 200, 128, 255}, 4, []int{}},
+		{"least confident first", []byte{255, 40, 127, 0, 90}, 4, []int{3, 1, 4, 2}},
+		{"at most n", []byte{255, 40, 127, 0, 90}, 2, []int{3, 1}},
+		{"ties keep their order", []byte{10, 255, 10, 5, 10}, 3, []int{3, 0, 2}},
+		{"none wanted", []byte{0, 0}, 0, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, erasureCandidates(tt.confidence, tt.n))
+		})
+	}
+}
+
+func Test_correctSoft(t *testing.T) {
+	info := levelsBlockInfo[5][ECLevelQuartile][0]
+	want := reedsolomon.Encode([]byte("soft decision sampling")[:info.DataPer], int(info.TotalPer-info.DataPer))
+
+	tests := []struct {
+		name string
+		// codewords damaged, the first unconfident of them also being unconfident
+		damaged, unconfident int
+		wantErr              bool
+		wantStats            BlockStats
+	}{
+		{
+			name:      "within capacity",
+			damaged:   int(info.EC_Cap),
+			wantStats: BlockStats{Errors: int(info.EC_Cap)},
+		},
+		{
+			name:        "erasures beyond capacity",
+			damaged:     int(info.EC_Cap) + 4,
+			unconfident: 8,
+			wantStats:   BlockStats{Errors: int(info.EC_Cap) + 4, Erasures: 8},
+		},
+		{
+			name:        "too many unconfident codewords",
+			damaged:     2*int(info.EC_Cap) + 1,
+			unconfident: 2*int(info.EC_Cap) + 1,
+			wantErr:     true,
+		},
+		{
+			name:    "confident errors beyond capacity",
+			damaged: int(info.EC_Cap) + 1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// every other codeword is damaged by a single bit, as counted by BlockStats.Errors
+			block := append([]byte{}, want...)
+			for i := 0; i < tt.damaged; i++ {
+				block[i*2%len(block)] ^= 0x10
+			}
+
+			confidence := bytes.Repeat([]byte{255}, len(block))
+			for i := 0; i < tt.unconfident; i++ {
+				confidence[i*2%len(block)] = byte(10 * i)
+			}
+
+			got, stats, err := correctSoft(block, info, confidence)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			tt.wantStats.Codewords, tt.wantStats.DataCodewords = int(info.TotalPer), int(info.DataPer)
+			if assert.NoError(t, err) {
+				assert.Equal(t, want, got)
+				assert.Equal(t, tt.wantStats, stats)
+			}
+		})
+	}
+}
@@ -5,56 +5,84 @@ import (
 	"fmt"
 )
 
+type segmentReader = func(chomp *Chomp, lengthBits uint8) ([]byte, error)
+
+var qrModes = map[byte]struct {
+	read segmentReader
+	// length of the character count indicator for versions 1 to 9, 10 to 26 and 27 to 40
+	lengthBits [3]uint8
+}{
+	0b0001: {numeric, [...]uint8{10, 12, 14}},
+	0b0010: {alphanumeric, [...]uint8{9, 11, 13}},
+	0b0100: {eightBit, [...]uint8{8, 16, 16}},
+	0b1000: {kanji, [...]uint8{8, 10, 12}},
+}
+
 func Data(input []byte, version uint32) ([]byte, error) {
-	chomp := NewChomp(input)
+	segments, err := Segments(input, version)
+	if err != nil {
+		return nil, err
+	}
+
 	result := bytes.Buffer{}
+	for _, segment := range segments {
+		result.Write(segment.Data)
+	}
 
-modeLoop:
-	for mode, ok := chomp.Chomp(4); ok; mode, ok = chomp.Chomp(4) {
-		var (
-			data []byte
-			err  error
-		)
+	return result.Bytes(), nil
+}
+
+// Segment is a run of data encoded in a single mode
+type Segment struct {
+	// Mode is the 4 bit mode indicator, 0b0001 for numeric, 0b0010 for alphanumeric,
+	// 0b0100 for byte and 0b1000 for Kanji mode
+	Mode byte
+
+	Data []byte
+}
 
-		switch mode {
-		case 0b0001:
-			data, err = numeric(chomp, version)
-		case 0b0010:
-			data, err = alphanumeric(chomp, version)
-		case 0b0100:
-			data, err = eightBit(chomp, version)
-		case 0b0000:
-			break modeLoop
-		default:
+// Segments decodes the data codewords of a QR Code into its segments, see Data
+func Segments(input []byte, version uint32) ([]Segment, error) {
+	chomp := NewChomp(input)
+	segments := []Segment{}
+
+	for mode, ok := chomp.Chomp(4); ok && mode != 0b0000; mode, ok = chomp.Chomp(4) {
+		m, ok := qrModes[mode]
+		if !ok {
 			return nil, fmt.Errorf("mode %.4b not yet implemented", mode)
 		}
 
+		lengthBits, err := characterCountBits(m.lengthBits, version)
 		if err != nil {
 			return nil, err
 		}
 
-		_, err = result.Write(data)
+		data, err := m.read(chomp, lengthBits)
 		if err != nil {
 			return nil, err
 		}
+
+		segments = append(segments, Segment{Mode: mode, Data: data})
 	}
 
-	return result.Bytes(), nil
+	return segments, nil
 }
 
-func numeric(chomp *Chomp, version uint32) ([]byte, error) {
-	var lengthBits uint8
+// characterCountBits picks the length of the character count indicator for version
+func characterCountBits(lengthBits [3]uint8, version uint32) (uint8, error) {
 	switch {
 	case version >= 1 && version <= 9:
-		lengthBits = 10
+		return lengthBits[0], nil
 	case version >= 10 && version <= 26:
-		lengthBits = 12
+		return lengthBits[1], nil
 	case version >= 27 && version <= 40:
-		lengthBits = 14
+		return lengthBits[2], nil
 	default:
-		return nil, fmt.Errorf("unknown version %d", version)
+		return 0, fmt.Errorf("unknown version %d", version)
 	}
+}
 
+func numeric(chomp *Chomp, lengthBits uint8) ([]byte, error) {
 	length, ok := chomp.ChompUint16(lengthBits)
 	if !ok {
 		return nil, fmt.Errorf("could not read %d bits for numeric length", lengthBits)
@@ -106,19 +134,7 @@ var alphanumericArr = [45]byte{
 	'%', '*', '+', '-', '.', '/', ':',
 }
 
-func alphanumeric(chomp *Chomp, version uint32) ([]byte, error) {
-	var lengthBits uint8
-	switch {
-	case version >= 1 && version <= 9:
-		lengthBits = 9
-	case version >= 10 && version <= 26:
-		lengthBits = 11
-	case version >= 27 && version <= 40:
-		lengthBits = 13
-	default:
-		return nil, fmt.Errorf("unknown version %d", version)
-	}
-
+func alphanumeric(chomp *Chomp, lengthBits uint8) ([]byte, error) {
 	length, ok := chomp.ChompUint16(lengthBits)
 	if !ok {
 		return nil, fmt.Errorf("could not read %d bits for alphanumeric length", lengthBits)
@@ -152,20 +168,7 @@ func alphanumeric(chomp *Chomp, version uint32) ([]byte, error) {
 	return result, nil
 }
 
-func eightBit(chomp *Chomp, version uint32) ([]byte, error) {
-	var lengthBits uint8
-
-	switch {
-	case version >= 1 && version <= 9:
-		lengthBits = 8
-	case version >= 10 && version <= 26:
-		fallthrough
-	case version >= 27 && version <= 40:
-		lengthBits = 16
-	default:
-		return nil, fmt.Errorf("unknown version %d", version)
-	}
-
+func eightBit(chomp *Chomp, lengthBits uint8) ([]byte, error) {
 	length, ok := chomp.ChompUint16(lengthBits)
 	if !ok {
 		return nil, fmt.Errorf("could not read %d bits for 8bits length", length)
@@ -189,6 +192,35 @@ func eightBit(chomp *Chomp, version uint32) ([]byte, error) {
 	return result, nil
 }
 
+// kanji reads 13 bit Kanji characters as the two bytes of their Shift JIS encoding,
+// which are left as they are like those of eightBit
+func kanji(chomp *Chomp, lengthBits uint8) ([]byte, error) {
+	length, ok := chomp.ChompUint16(lengthBits)
+	if !ok {
+		return nil, fmt.Errorf("could not read %d bits for kanji length", lengthBits)
+	}
+
+	result := []byte{}
+
+	for i := uint16(0); i < length; i++ {
+		bits, err := readBitsUint16(chomp, 13)
+		if err != nil {
+			return nil, err
+		}
+
+		char := bits/0xC0<<8 | bits%0xC0
+		if char < 0x1F00 {
+			char += 0x8140
+		} else {
+			char += 0xC140
+		}
+
+		result = append(result, byte(char>>8), byte(char))
+	}
+
+	return result, nil
+}
+
 func readBits(chomp *Chomp, nBits byte) (byte, error) {
 	bits, ok := chomp.Chomp(nBits)
 	if !ok {
@@ -0,0 +1,61 @@
+package ar8t
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// packBits packs a string of 0s and 1s into bytes, spaces are ignored
+// and the last byte is padded with 0s
+func packBits(bits string) []byte {
+	bits = strings.ReplaceAll(bits, " ", "")
+	result := make([]byte, (len(bits)+7)/8)
+
+	for i, b := range bits {
+		if b == '1' {
+			result[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+
+	return result
+}
+
+func Test_Segments(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    string
+		version uint32
+		want    []Segment
+	}{
+		{
+			name:    "kanji",
+			bits:    "1000 00000010 0110110011111 1101010101010 0000",
+			version: 1,
+			want:    []Segment{{Mode: 0b1000, Data: []byte{0x93, 0x5F, 0xE4, 0xAA}}},
+		},
+		{
+			name:    "kanji version 10",
+			bits:    "1000 0000000001 0110110011111 0000",
+			version: 10,
+			want:    []Segment{{Mode: 0b1000, Data: []byte{0x93, 0x5F}}},
+		},
+		{
+			name:    "numeric and kanji",
+			bits:    "0001 0000000001 0111 1000 00000001 1101010101010 0000",
+			version: 1,
+			want: []Segment{
+				{Mode: 0b0001, Data: []byte("7")},
+				{Mode: 0b1000, Data: []byte{0xE4, 0xAA}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Segments(packBits(tt.bits), tt.version)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,527 @@
+package ar8t
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mrg0lden/ar8t/reedsolomon"
+)
+
+var (
+	errDataMatrixSize = errors.New("not the size of a Data Matrix ECC200 symbol")
+	errDataMatrixData = errors.New("invalid Data Matrix codeword")
+)
+
+// DataMatrixDecoder decodes Data Matrix ECC200 symbols, 10x10 to 144x144 and 8x18 to 16x48.
+// QRData.Modules is the whole symbol, finder and clock tracks included, and QRData.Version
+// is 1 to 30, in the order of dataMatrixSizes, or 0 to take it from the size of the modules.
+type DataMatrixDecoder struct{}
+
+type dataMatrixSize struct {
+	rows, columns int
+
+	// every data region is surrounded by its own finder and clock track
+	regionRows, regionColumns int
+
+	// blocks are interleaved codeword by codeword, EC_Cap being half the error correction codewords
+	blocks []BlockInfo
+}
+
+// dataMatrixSizes are the square then the rectangular ECC200 symbols, version 1 being dataMatrixSizes[0]
+var dataMatrixSizes = [30]dataMatrixSize{
+	{10, 10, 8, 8, []BlockInfo{{1, 8, 3, 2}}},
+	{12, 12, 10, 10, []BlockInfo{{1, 12, 5, 3}}},
+	{14, 14, 12, 12, []BlockInfo{{1, 18, 8, 5}}},
+	{16, 16, 14, 14, []BlockInfo{{1, 24, 12, 6}}},
+	{18, 18, 16, 16, []BlockInfo{{1, 32, 18, 7}}},
+	{20, 20, 18, 18, []BlockInfo{{1, 40, 22, 9}}},
+	{22, 22, 20, 20, []BlockInfo{{1, 50, 30, 10}}},
+	{24, 24, 22, 22, []BlockInfo{{1, 60, 36, 12}}},
+	{26, 26, 24, 24, []BlockInfo{{1, 72, 44, 14}}},
+	{32, 32, 14, 14, []BlockInfo{{1, 98, 62, 18}}},
+	{36, 36, 16, 16, []BlockInfo{{1, 128, 86, 21}}},
+	{40, 40, 18, 18, []BlockInfo{{1, 162, 114, 24}}},
+	{44, 44, 20, 20, []BlockInfo{{1, 200, 144, 28}}},
+	{48, 48, 22, 22, []BlockInfo{{1, 242, 174, 34}}},
+	{52, 52, 24, 24, dataMatrixBlocks(2, 102, 0, 42)},
+	{64, 64, 14, 14, dataMatrixBlocks(2, 140, 0, 56)},
+	{72, 72, 16, 16, dataMatrixBlocks(4, 92, 0, 36)},
+	{80, 80, 18, 18, dataMatrixBlocks(4, 114, 0, 48)},
+	{88, 88, 20, 20, dataMatrixBlocks(4, 144, 0, 56)},
+	{96, 96, 22, 22, dataMatrixBlocks(4, 174, 0, 68)},
+	{104, 104, 24, 24, dataMatrixBlocks(6, 136, 0, 56)},
+	{120, 120, 18, 18, dataMatrixBlocks(6, 175, 0, 68)},
+	{132, 132, 20, 20, dataMatrixBlocks(8, 163, 0, 62)},
+	{144, 144, 22, 22, dataMatrixBlocks(8, 156, 2, 62)},
+
+	{8, 18, 6, 16, []BlockInfo{{1, 12, 5, 3}}},
+	{8, 32, 6, 14, []BlockInfo{{1, 21, 10, 5}}},
+	{12, 26, 10, 24, []BlockInfo{{1, 30, 16, 7}}},
+	{12, 36, 10, 16, []BlockInfo{{1, 40, 22, 9}}},
+	{16, 36, 14, 16, []BlockInfo{{1, 56, 32, 12}}},
+	{16, 48, 14, 22, []BlockInfo{{1, 77, 49, 14}}},
+}
+
+// dataMatrixBlocks are count blocks with data codewords each, followed by shorter blocks
+// with one data codeword less, all of them with ec error correction codewords
+func dataMatrixBlocks(count, data, shorter, ec int) []BlockInfo {
+	blocks := make([]BlockInfo, 0, count+shorter)
+	for i := 0; i < count+shorter; i++ {
+		d := data
+		if i >= count {
+			d--
+		}
+		blocks = append(blocks, BlockInfo{byte(count + shorter), byte(d + ec), byte(d), byte(ec / 2)})
+	}
+	return blocks
+}
+
+// dataMatrixPatterns caches the data module order of every size
+var dataMatrixPatterns [30]struct {
+	once      sync.Once
+	dataOrder []uint32
+}
+
+func (DataMatrixDecoder) Decode(qrData QRData) ([]byte, error) {
+	version, err := dataMatrixVersion(qrData.Modules.Width(), qrData.Modules.Height())
+	if err != nil {
+		return nil, err
+	}
+
+	if qrData.Version != 0 && qrData.Version != version {
+		return nil, errVersionMismatch
+	}
+
+	size := dataMatrixSizes[version-1]
+	dataOrder := getDataMatrixPatterns(version)
+
+	codewords := codewords{blocks: newBlocks(size.blocks), confidences: newBlocks(size.blocks)}
+
+	total := 0
+	for _, info := range size.blocks {
+		total += int(info.TotalPer)
+	}
+
+	// the fixed pattern filling the corner of some sizes is ignored
+	for _, index := range dataOrder[:total*8] {
+		x, y := int(index)%size.columns, int(index)/size.columns
+		codewords.addBit(qrData.Modules.bit(x, y), qrData.confidence(index))
+	}
+
+	blocks, confidences := codewords.blocks.blocks, codewords.confidences.blocks
+
+	for i, block := range blocks {
+		var confidence []byte
+		if qrData.Confidence != nil {
+			confidence = confidences[i]
+		}
+
+		corrected, _, err := correctSoftIn(reedsolomon.DataMatrix, block, size.blocks[i], confidence)
+		if err != nil {
+			return nil, err
+		}
+
+		// odd numbers of error correction codewords leave one to detect errors
+		symbols := make([]int, len(corrected))
+		for i, c := range corrected {
+			symbols[i] = int(c)
+		}
+		if _, valid := reedsolomon.DataMatrix.Syndromes(symbols, int(size.blocks[i].TotalPer-size.blocks[i].DataPer)); !valid {
+			return nil, reedsolomon.ErrTooManyErrors
+		}
+
+		blocks[i] = corrected
+	}
+
+	dataTotal := 0
+	for _, info := range size.blocks {
+		dataTotal += int(info.DataPer)
+	}
+
+	// the data codewords are interleaved one block after the other
+	data := make([]byte, 0, dataTotal)
+	for round := 0; len(data) < dataTotal; round++ {
+		for i, block := range blocks {
+			if round < int(size.blocks[i].DataPer) {
+				data = append(data, block[round])
+			}
+		}
+	}
+
+	return DataMatrixData(data)
+}
+
+// dataMatrixVersion is the version of the ECC200 symbol width x height modules large
+func dataMatrixVersion(width, height int) (uint32, error) {
+	for i, size := range dataMatrixSizes {
+		if size.columns == width && size.rows == height {
+			return uint32(i + 1), nil
+		}
+	}
+
+	return 0, errDataMatrixSize
+}
+
+// DataMatrixSize is the size in modules of a Data Matrix ECC200 version
+func DataMatrixSize(version uint32) (width, height int, err error) {
+	if version < 1 || version > uint32(len(dataMatrixSizes)) {
+		return 0, 0, errUnsupportedVersion
+	}
+
+	size := dataMatrixSizes[version-1]
+	return size.columns, size.rows, nil
+}
+
+// getDataMatrixPatterns computes the data module order of a Data Matrix version once
+func getDataMatrixPatterns(version uint32) []uint32 {
+	p := &dataMatrixPatterns[version-1]
+	p.once.Do(func() {
+		p.dataOrder = dataMatrixDataOrder(dataMatrixSizes[version-1])
+	})
+
+	return p.dataOrder
+}
+
+// dataMatrixDataOrder places the codewords in the mapping matrix, the data regions put together
+// without their finders and clock tracks, as ISO/IEC 16022 does. Codewords are placed diagonally
+// in the shape of an L with a corner cut off, up and right then down and left, wrapping around
+// the edges, with four special shapes for the corners.
+// Each module is returned as an index into the symbol, codeword by codeword from the most
+// significant bit.
+func dataMatrixDataOrder(size dataMatrixSize) []uint32 {
+	var (
+		rows    = size.rows / (size.regionRows + 2) * size.regionRows
+		columns = size.columns / (size.regionColumns + 2) * size.regionColumns
+
+		placed = make([]bool, rows*columns)
+		order  = []uint32{}
+	)
+
+	module := func(row, column int) {
+		if row < 0 {
+			row += rows
+			column += 4 - (rows+4)%8
+		}
+		if column < 0 {
+			column += columns
+			row += 4 - (columns+4)%8
+		}
+
+		placed[row*columns+column] = true
+
+		// back into the symbol, past the finders and clock tracks of the data regions
+		x := column/size.regionColumns*(size.regionColumns+2) + 1 + column%size.regionColumns
+		y := row/size.regionRows*(size.regionRows+2) + 1 + row%size.regionRows
+		order = append(order, uint32(y*size.columns+x))
+	}
+
+	shape := func(modules [8][2]int) {
+		for _, m := range modules {
+			module(m[0], m[1])
+		}
+	}
+
+	utah := func(row, column int) {
+		shape([8][2]int{
+			{row - 2, column - 2}, {row - 2, column - 1},
+			{row - 1, column - 2}, {row - 1, column - 1}, {row - 1, column},
+			{row, column - 2}, {row, column - 1}, {row, column},
+		})
+	}
+
+	row, column := 4, 0
+
+	for row < rows || column < columns {
+		switch {
+		case row == rows && column == 0:
+			shape([8][2]int{
+				{rows - 1, 0}, {rows - 1, 1}, {rows - 1, 2},
+				{0, columns - 2}, {0, columns - 1}, {1, columns - 1}, {2, columns - 1}, {3, columns - 1},
+			})
+		case row == rows-2 && column == 0 && columns%4 != 0:
+			shape([8][2]int{
+				{rows - 3, 0}, {rows - 2, 0}, {rows - 1, 0},
+				{0, columns - 4}, {0, columns - 3}, {0, columns - 2}, {0, columns - 1}, {1, columns - 1},
+			})
+		case row == rows-2 && column == 0 && columns%8 == 4:
+			shape([8][2]int{
+				{rows - 3, 0}, {rows - 2, 0}, {rows - 1, 0},
+				{0, columns - 2}, {0, columns - 1}, {1, columns - 1}, {2, columns - 1}, {3, columns - 1},
+			})
+		case row == rows+4 && column == 2 && columns%8 == 0:
+			shape([8][2]int{
+				{rows - 1, 0}, {rows - 1, columns - 1},
+				{0, columns - 3}, {0, columns - 2}, {0, columns - 1},
+				{1, columns - 3}, {1, columns - 2}, {1, columns - 1},
+			})
+		}
+
+		// up and right
+		for {
+			if row < rows && column >= 0 && !placed[row*columns+column] {
+				utah(row, column)
+			}
+			row, column = row-2, column+2
+			if row < 0 || column >= columns {
+				break
+			}
+		}
+		row, column = row+1, column+3
+
+		// down and left
+		for {
+			if row >= 0 && column < columns && !placed[row*columns+column] {
+				utah(row, column)
+			}
+			row, column = row+2, column-2
+			if row >= rows || column < 0 {
+				break
+			}
+		}
+		row, column = row+3, column+1
+	}
+
+	return order
+}
+
+// ASCII codewords that latch to the other encodation schemes, and the unlatch codeword
+// that returns to ASCII from C40, Text and X12
+const (
+	dataMatrixC40     = 230
+	dataMatrixBase256 = 231
+	dataMatrixX12     = 238
+	dataMatrixText    = 239
+	dataMatrixEDIFACT = 240
+	dataMatrixUnlatch = 254
+)
+
+// DataMatrixData decodes the data codewords of a Data Matrix ECC200 symbol.
+// Decoding starts in ASCII, which latches to the other encodation schemes,
+// and ends at the first pad codeword or at the end of the data.
+func DataMatrixData(input []byte) ([]byte, error) {
+	var (
+		result     = bytes.Buffer{}
+		trailer    []byte
+		upperShift bool
+		err        error
+	)
+
+	for i := 0; i < len(input); {
+		c := input[i]
+		i++
+
+		switch {
+		case c >= 1 && c <= 128:
+			b := c - 1
+			if upperShift {
+				b += 128
+				upperShift = false
+			}
+			result.WriteByte(b)
+		case c == 129:
+			// padding to the end of the symbol
+			i = len(input)
+		case c >= 130 && c <= 229:
+			result.WriteByte('0' + (c-130)/10)
+			result.WriteByte('0' + (c-130)%10)
+		case c == dataMatrixC40, c == dataMatrixText, c == dataMatrixX12:
+			i, err = dataMatrixTriplets(input, i, c, &result)
+		case c == dataMatrixBase256:
+			i, err = dataMatrixBase256Segment(input, i, &result)
+		case c == dataMatrixEDIFACT:
+			i = dataMatrixEDIFACTSegment(input, i, &result)
+		case c == 232:
+			// FNC1 in first position only marks GS1 data, anywhere else it separates fields
+			if i > 1 {
+				result.WriteByte(0x1D)
+			}
+		case c == 233:
+			// structured append, followed by the position of the symbol and a file identification
+			i += 3
+		case c == 234:
+			// reader programming
+		case c == 235:
+			upperShift = true
+		case c == 236:
+			// the header and trailer of 05 and 06 macros
+			result.WriteString("[)>\x1E05\x1D")
+			trailer = []byte("\x1E\x04")
+		case c == 237:
+			result.WriteString("[)>\x1E06\x1D")
+			trailer = []byte("\x1E\x04")
+		default:
+			return nil, fmt.Errorf("codeword %d not yet implemented", c)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Write(trailer)
+
+	return result.Bytes(), nil
+}
+
+// dataMatrixTriplets decodes C40, Text or X12 from input[i:], three values in every two codewords,
+// until the unlatch codeword. A single codeword left at the end of the data is in ASCII.
+// It returns where ASCII starts again.
+func dataMatrixTriplets(input []byte, i int, scheme byte, result *bytes.Buffer) (int, error) {
+	var (
+		shift      int
+		upperShift bool
+	)
+
+	write := func(c byte) {
+		if upperShift {
+			c += 128
+			upperShift = false
+		}
+		result.WriteByte(c)
+	}
+
+	for i+1 < len(input) && input[i] != dataMatrixUnlatch {
+		packed := int(input[i])*256 + int(input[i+1]) - 1
+		i += 2
+
+		for _, v := range [3]int{packed / 1600, packed / 40 % 40, packed % 40} {
+			switch {
+			case v >= 40:
+				return 0, errDataMatrixData
+			case scheme == dataMatrixX12:
+				switch {
+				case v < 3:
+					write("\r*>"[v])
+				case v == 3:
+					write(' ')
+				case v < 14:
+					write(byte('0' + v - 4))
+				default:
+					write(byte('A' + v - 14))
+				}
+			case shift == 0:
+				switch {
+				case v < 3:
+					shift = v + 1
+				case v == 3:
+					write(' ')
+				case v < 14:
+					write(byte('0' + v - 4))
+				case scheme == dataMatrixText:
+					write(byte('a' + v - 14))
+				default:
+					write(byte('A' + v - 14))
+				}
+			case shift == 1:
+				shift = 0
+				if v >= 32 {
+					return 0, errDataMatrixData
+				}
+				write(byte(v))
+			case shift == 2:
+				shift = 0
+				switch {
+				case v < 15:
+					write(byte('!' + v))
+				case v < 22:
+					write(byte(':' + v - 15))
+				case v < 27:
+					write(byte('[' + v - 22))
+				case v == 27:
+					// FNC1
+					write(0x1D)
+				case v == 30:
+					upperShift = true
+				default:
+					return 0, errDataMatrixData
+				}
+			default:
+				shift = 0
+				switch {
+				case v >= 32:
+					return 0, errDataMatrixData
+				case scheme == dataMatrixC40:
+					write(byte('`' + v))
+				case v == 0:
+					write('`')
+				case v < 27:
+					write(byte('A' + v - 1))
+				default:
+					write(byte('{' + v - 27))
+				}
+			}
+		}
+	}
+
+	if i < len(input) && input[i] == dataMatrixUnlatch {
+		i++
+	}
+
+	return i, nil
+}
+
+// dataMatrixEDIFACTSegment decodes EDIFACT from input[i:], four 6 bit values in every three codewords,
+// until the unlatch value 011111. The rest of the codeword after it is ignored, and fewer than
+// three codewords left at the end of the data are in ASCII. It returns where ASCII starts again.
+func dataMatrixEDIFACTSegment(input []byte, i int, result *bytes.Buffer) int {
+	for ; i+3 <= len(input); i += 3 {
+		packed := int(input[i])<<16 | int(input[i+1])<<8 | int(input[i+2])
+
+		for n := 0; n < 4; n++ {
+			v := packed >> (18 - 6*n) & 0x3F
+			if v == 0x1F {
+				return i + (6*(n+1)+7)/8
+			}
+
+			// 000000 to 011110 are @ to ^, 100000 to 111111 are space to ?
+			if v&0x20 == 0 {
+				v |= 0x40
+			}
+			result.WriteByte(byte(v))
+		}
+	}
+
+	return i
+}
+
+// dataMatrixBase256Segment decodes a Base 256 segment from input[i:], a length and as many bytes,
+// all of them randomized by their position. A length of 0 runs to the end of the data.
+// It returns where ASCII starts again.
+func dataMatrixBase256Segment(input []byte, i int, result *bytes.Buffer) (int, error) {
+	// positions are counted from 1
+	next := func() (int, bool) {
+		if i >= len(input) {
+			return 0, false
+		}
+		random := 149*(i+1)%255 + 1
+		i++
+		return (int(input[i-1]) - random + 256) % 256, true
+	}
+
+	length, ok := next()
+	switch {
+	case !ok:
+		return 0, errDataMatrixData
+	case length == 0:
+		length = len(input) - i
+	case length >= 250:
+		d2, ok := next()
+		if !ok {
+			return 0, errDataMatrixData
+		}
+		length = 250*(length-249) + d2
+	}
+
+	if i+length > len(input) {
+		return 0, errDataMatrixData
+	}
+
+	for n := 0; n < length; n++ {
+		b, _ := next()
+		result.WriteByte(byte(b))
+	}
+
+	return i, nil
+}
@@ -0,0 +1,220 @@
+package ar8t
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dataMatrixTests are Data Matrix symbols, square and rectangular, the damaged ones drawn undamaged by Test_DataMatrixScan
+var dataMatrixTests = []decoderTest[struct{}]{
+	{
+		name: "12x12 ASCII",
+		symbol: `
+			#.#.#.#.#.#.
+			#.#..#.#..##
+			#.#.#.#.#...
+			#.##......##
+			#.....#...#.
+			#........###
+			#..##.###...
+			#.##..##..##
+			#.#..##..##.
+			#..####..#.#
+			####.#.#..#.
+			############
+		`,
+		want: "AR8T",
+	},
+	{
+		name: "8x32 in two regions",
+		symbol: `
+			#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.#.
+			#.#.#..####..#.##..######.....##
+			##.##..######...#.#..###.####.#.
+			##...##..##...####.##.#..#..####
+			########.####...#.#....#.##..##.
+			##.#.#.###..#.###......#.#.#..##
+			##.##.#..##..##.###.######..#.#.
+			################################
+		`,
+		want: "datamatrix",
+	},
+	{
+		name: "16x16 with errors",
+		symbol: `
+			#.#.#.#.#.#.#.#.
+			##....##.#######
+			#.##.##...####..
+			###.#.##.##..###
+			##..###.#.##..#.
+			##.####.##.#####
+			##..#######..#..
+			###.#.#####..###
+			####...##..####.
+			##.#.#.##..#.###
+			#....##..#..##..
+			#..###..##.#...#
+			##.###..#...###.
+			##...#.#.##.##.#
+			###......#.##.#.
+			################
+		`,
+		flipped: [][2]int{{3, 3}, {8, 6}, {12, 11}},
+		want:    "label2026",
+	},
+}
+
+func Test_DataMatrixDecoder(t *testing.T) {
+	testDecoder(t, dataMatrixTests, func(t *testing.T, modules BitMatrix, _ struct{}) ([]byte, error) {
+		return DataMatrixDecoder{}.Decode(QRData{Modules: modules})
+	})
+}
+
+func Test_DataMatrixScan(t *testing.T) {
+	for _, tt := range dataMatrixTests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDecodeAll(t, SymbologyDataMatrix, tt.symbol, 6, tt.want, 0, 90, 180, 270, 30)
+		})
+	}
+}
+
+// base256 randomizes a Base 256 segment with the 255 state algorithm, the way an encoder does,
+// its first codeword being at position (counted from 1) in the data
+func base256(position int, segment ...byte) []byte {
+	randomized := make([]byte, len(segment))
+	for i, b := range segment {
+		randomized[i] = byte((int(b) + 149*(position+i)%255 + 1) % 256)
+	}
+	return randomized
+}
+
+func Test_DataMatrixData(t *testing.T) {
+	long := bytes.Repeat([]byte{0xE9, 0xFF, 0xFE, 0x80}, 65)
+
+	tests := []struct {
+		name      string
+		codewords []byte
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "C40",
+			codewords: []byte{230, 91, 11, 91, 11, 91, 11, 254},
+			want:      "AIMAIMAIM",
+		},
+		{
+			name:      "C40 shift 1",
+			codewords: []byte{230, 91, 11, 91, 11, 0, 55, 141, 159, 141, 159, 254, 74, 78, 129, 237},
+			want:      "AIMAIM\x01AIMAIMAIM",
+		},
+		{
+			name:      "C40 shift 2",
+			codewords: []byte{230, 91, 11, 91, 11, 6, 79, 141, 159, 141, 159, 254, 74, 78, 129, 237},
+			want:      "AIMAIM!AIMAIMAIM",
+		},
+		{
+			name:      "C40 shift 3",
+			codewords: []byte{230, 91, 11, 91, 11, 12, 183, 141, 159, 141, 159, 254, 74, 78, 129, 237},
+			want:      "AIMAIMaAIMAIMAIM",
+		},
+		{
+			name:      "C40 value out of range",
+			codewords: []byte{230, 255, 255},
+			wantErr:   errDataMatrixData,
+		},
+		{
+			name:      "Text",
+			codewords: []byte{239, 91, 11, 91, 11, 91, 11, 254},
+			want:      "aimaimaim",
+		},
+		{
+			name:      "Text shift 3",
+			codewords: []byte{239, 91, 11, 91, 11, 12, 191, 164, 199, 164, 199, 164, 199, 254, 110, 129},
+			want:      "aimaimAimaimaimaim",
+		},
+		{
+			name:      "Text upper shift",
+			codewords: []byte{239, 91, 11, 91, 11, 11, 7, 91, 11, 91, 11, 91, 11, 91, 11, 254},
+			want:      "aimaim\xE9aimaimaimaim",
+		},
+		{
+			name:      "X12 ending in ASCII",
+			codewords: []byte{238, 89, 233, 14, 192, 100, 207, 44, 31, 67},
+			want:      "ABC>ABC123>AB",
+		},
+		{
+			name:      "X12 separators",
+			codewords: []byte{238, 89, 233, 8, 128, 100, 15, 96, 67, 254},
+			want:      "ABC*ABC\rABC>",
+		},
+		{
+			name:      "EDIFACT",
+			codewords: []byte{240, 184, 27, 131, 198, 236, 238, 16, 21, 1, 187, 28, 179, 16, 21, 1, 187, 28, 179, 16, 21, 1},
+			want:      ".A.C1.3.DATA.123DATA.123DATA",
+		},
+		{
+			name:      "Base 256",
+			codewords: append(append([]byte{231}, base256(2, 6, 0xAB, 0xE4, 0xF6, 0xFC, 0xE9, 0xBB)...), 66, 129),
+			want:      "\xAB\xE4\xF6\xFC\xE9\xBBA",
+		},
+		{
+			name:      "Base 256 to the end of the data",
+			codewords: append([]byte{66, 231}, base256(3, 0, 0x00, 0x81, 0xFF)...),
+			want:      "A\x00\x81\xFF",
+		},
+		{
+			name:      "Base 256 with a 2 codeword length",
+			codewords: append(append([]byte{231}, base256(2, append([]byte{250, 10}, long...)...)...), 129),
+			want:      string(long),
+		},
+		{
+			name:      "Base 256 longer than the data",
+			codewords: append([]byte{231}, base256(2, 6, 1, 2)...),
+			wantErr:   errDataMatrixData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DataMatrixData(tt.codewords)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+// Test_DataMatrixDecoder_144x144 decodes the largest symbol, the only one whose blocks
+// differ in length: 8 of 156 data codewords and 2 of 155, interleaved
+func Test_DataMatrixDecoder_144x144(t *testing.T) {
+	file, err := os.ReadFile("testdata/datamatrix-144x144.txt")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	text, symbol, _ := strings.Cut(string(file), "\n")
+	modules, err := ParseBitMatrix(symbol)
+	if !assert.NoError(t, err) || !assert.Equal(t, 144, modules.Width()) {
+		return
+	}
+
+	// a burst of errors across neighbouring codewords, spread over the blocks by the interleaving
+	for x := 40; x < 60; x++ {
+		modules.Flip(x, 70)
+		modules.Flip(x, 71)
+	}
+
+	got, err := DataMatrixDecoder{}.Decode(QRData{Modules: modules})
+	if assert.NoError(t, err) {
+		assert.Equal(t, text, string(got))
+	}
+}
@@ -1,44 +1,87 @@
 package ar8t
 
 //QRDecoder is ready to use as is
-type QRDecoder struct{}
+type QRDecoder struct {
+	// Observer is told about the stages of decoding when set, see Observer
+	Observer Observer
+}
+
+// QRInfo is what decoding a QR Code tells about it besides its data
+type QRInfo struct {
+	Version uint32
+
+	// Format is nil when the format information can't be decoded
+	Format *FormatInfo
+
+	// Blocks tell how every block was corrected, up to the first one that couldn't be
+	Blocks []BlockStats
+
+	Segments []Segment
+}
+
+func (d QRDecoder) Decode(qrData QRData) ([]byte, error) {
+	data, _, err := d.DecodeWithInfo(qrData)
+	return data, err
+}
+
+// DecodeWithInfo decodes a QR Code along with its QRInfo.
+// When decoding fails the info is filled as far as it got.
+func (d QRDecoder) DecodeWithInfo(qrData QRData) ([]byte, QRInfo, error) {
+	observer := observerOr(d.Observer)
+	info := QRInfo{Version: qrData.Version}
 
-func (QRDecoder) Decode(qrData QRData) ([]byte, error) {
-	ecLevel, mask, err := Format(qrData)
+	format, err := DecodeFormat(qrData)
+	observer.Format(format, err)
 	if err != nil {
-		return nil, err
+		return nil, info, err
 	}
+	ecLevel := format.ECLevel
+	info.Format = &format
 
-	blocks, err := Blocks(qrData, ecLevel, mask)
+	if version, err := DecodeVersion(qrData); err == nil && version != qrData.Version {
+		return nil, info, errVersionMismatch
+	}
+
+	blocks, confidences, err := blocksWithConfidence(qrData, ecLevel, format.Mask)
 	if err != nil {
-		return nil, err
+		return nil, info, err
 	}
 
 	blockInfo, err := GetBlockInfo(qrData.Version, ecLevel)
 	if err != nil {
-		return nil, err
+		return nil, info, err
 	}
 
 	allBlocks := []byte{}
 
 	for i := 0; i < len(blocks) && i < len(blockInfo); i++ {
-		corrected, err := Correct(blocks[i], blockInfo[i])
+		var confidence []byte
+		if qrData.Confidence != nil {
+			confidence = confidences[i]
+		}
+
+		corrected, stats, err := correctSoft(blocks[i], blockInfo[i], confidence)
+		observer.BlockCorrected(i, stats, err)
 		if err != nil {
-			return nil, err
+			return nil, info, err
 		}
+		info.Blocks = append(info.Blocks, stats)
 
 		allBlocks = append(allBlocks, corrected[:blockInfo[i].DataPer]...)
 	}
 
-	data, err := Data(allBlocks, qrData.Version)
+	segments, err := Segments(allBlocks, qrData.Version)
+	observer.Segments(segments, err)
 	if err != nil {
-		return nil, err
+		return nil, info, err
 	}
+	info.Segments = segments
 
-	return data, nil
-
-}
+	data := []byte{}
+	for _, segment := range segments {
+		data = append(data, segment.Data...)
+	}
 
-// type QRDecoderWithInfo struct{}
+	return data, info, nil
 
-// func (QRDecoderWithInfo) Decode(qrData QRData)
+}
@@ -0,0 +1,70 @@
+package ar8t
+
+import (
+	"testing"
+
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_QRDecoder_DecodeWithInfo(t *testing.T) {
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// version 1, a single block of 26 codewords that corrects 3 of them
+	symbol := symbols[0]
+	modules := NewBitMatrix(len(symbol.Modules), len(symbol.Modules))
+	for y, row := range symbol.Modules {
+		for x, dark := range row {
+			modules.Set(x, y, dark)
+		}
+	}
+
+	// the bottom right 8 x 8 modules are all data, flipping them leaves too many errors to correct
+	tooDamaged := [][2]int{}
+	for y := 13; y < 21; y++ {
+		for x := 13; x < 21; x++ {
+			tooDamaged = append(tooDamaged, [2]int{x, y})
+		}
+	}
+
+	tests := []struct {
+		name       string
+		flipped    [][2]int
+		wantBlocks []BlockStats
+		wantErr    bool
+	}{
+		{
+			name:       "corrected",
+			flipped:    [][2]int{{20, 20}, {19, 19}},
+			wantBlocks: []BlockStats{{Codewords: 26, DataCodewords: 19, Errors: 2}},
+		},
+		{
+			name:    "too damaged",
+			flipped: tooDamaged,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			damaged := modules.Clone()
+			for _, module := range tt.flipped {
+				damaged.Flip(module[0], module[1])
+			}
+
+			got, info, err := QRDecoder{}.DecodeWithInfo(QRData{Modules: damaged, Version: 1, Side: 21})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, symbol.Text, string(got))
+			}
+
+			// only the blocks corrected are told about
+			assert.NotNil(t, info.Format)
+			assert.Equal(t, tt.wantBlocks, info.Blocks)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package ar8t
+
+// The digits of EAN and UPC barcodes are 7 modules and 4 runs wide. Digits left of the middle
+// guard start with a light run, in either the L or the G code, G being L backwards. Digits right
+// of it are in the R code, the runs of the L code starting with a dark run.
+var eanDigits = func() [][]int {
+	l := [][]int{
+		{3, 2, 1, 1},
+		{2, 2, 2, 1},
+		{2, 1, 2, 2},
+		{1, 4, 1, 1},
+		{1, 1, 3, 2},
+		{1, 2, 3, 1},
+		{1, 1, 1, 4},
+		{1, 3, 1, 2},
+		{1, 2, 1, 3},
+		{3, 1, 1, 2},
+	}
+
+	// the L code then the G code
+	digits := append([][]int{}, l...)
+	for _, runs := range l {
+		digits = append(digits, []int{runs[3], runs[2], runs[1], runs[0]})
+	}
+
+	return digits
+}()
+
+var (
+	eanGuard       = []int{1, 1, 1}
+	eanMiddleGuard = []int{1, 1, 1, 1, 1}
+	upcEEndGuard   = []int{1, 1, 1, 1, 1, 1}
+)
+
+// eanParities are the codes of the 6 digits left of the middle of EAN-13 barcodes for their
+// first digit, a set bit for the G code, the first digit being the most significant bit
+var eanParities = [10]int{0x00, 0x0B, 0x0D, 0x0E, 0x13, 0x19, 0x1C, 0x15, 0x16, 0x1A}
+
+// upcEParities are the codes of the digits of UPC-E barcodes of number system 1 for their check digit
+var upcEParities = [10]int{0x07, 0x0B, 0x0D, 0x0E, 0x13, 0x19, 0x1C, 0x15, 0x16, 0x1A}
+
+const eanMaxVariance = 0.48
+
+// readEAN reads EAN-13, UPC-A, EAN-8 and UPC-E barcodes, UPC-A being EAN-13 starting with a 0
+func readEAN(runs []int, start int) (Symbology, []byte, int, bool) {
+	if runVariance(runs[start:], eanGuard) > eanMaxVariance {
+		return 0, nil, 0, false
+	}
+
+	// the light runs around the barcode are at least as wide as the guard
+	guard := sum(runs[start : start+3])
+	if runs[start-1] < guard {
+		return 0, nil, 0, false
+	}
+
+	quiet := func(end int) bool {
+		return end < len(runs) && runs[end] >= guard
+	}
+
+	if digits, end, ok := readEAN13(runs, start+3); ok && quiet(end) {
+		if digits[0] == '0' {
+			return SymbologyUPCA, digits[1:], end, true
+		}
+		return SymbologyEAN13, digits, end, true
+	}
+
+	if digits, end, ok := readEAN8(runs, start+3); ok && quiet(end) {
+		return SymbologyEAN8, digits, end, true
+	}
+
+	if digits, end, ok := readUPCE(runs, start+3); ok && quiet(end) {
+		return SymbologyUPCE, digits, end, true
+	}
+
+	return 0, nil, 0, false
+}
+
+// eanDigit reads the digit in the 4 runs at i, and whether it is in the G code
+func eanDigit(runs []int, i int, g bool) (byte, bool, bool) {
+	if i+4 > len(runs) {
+		return 0, false, false
+	}
+
+	patterns := eanDigits[:10]
+	if g {
+		patterns = eanDigits
+	}
+
+	best, ok := bestPattern(runs[i:i+4], patterns, eanMaxVariance)
+	if !ok {
+		return 0, false, false
+	}
+
+	return byte(best%10) + '0', best >= 10, true
+}
+
+// eanHalf reads n digits from the runs at i, and the codes they are in
+func eanHalf(runs []int, i, n int, g bool) ([]byte, int, bool) {
+	digits := make([]byte, 0, n)
+	parities := 0
+
+	for j := 0; j < n; j++ {
+		digit, isG, ok := eanDigit(runs, i+4*j, g)
+		if !ok {
+			return nil, 0, false
+		}
+
+		digits = append(digits, digit)
+		parities <<= 1
+		if isG {
+			parities |= 1
+		}
+	}
+
+	return digits, parities, true
+}
+
+// readEAN13 reads the digits of an EAN-13 barcode from the runs after its start guard at i
+func readEAN13(runs []int, i int) ([]byte, int, bool) {
+	left, parities, ok := eanHalf(runs, i, 6, true)
+	if !ok || runVariance(runs[i+24:], eanMiddleGuard) > eanMaxVariance {
+		return nil, 0, false
+	}
+
+	right, _, ok := eanHalf(runs, i+29, 6, false)
+	if !ok || runVariance(runs[i+53:], eanGuard) > eanMaxVariance {
+		return nil, 0, false
+	}
+
+	first := -1
+	for d, p := range eanParities {
+		if p == parities {
+			first = d
+		}
+	}
+
+	if first == -1 {
+		return nil, 0, false
+	}
+
+	digits := append(append([]byte{byte(first) + '0'}, left...), right...)
+	if mod10CheckDigit(digits[:12]) != digits[12] {
+		return nil, 0, false
+	}
+
+	return digits, i + 56, true
+}
+
+// readEAN8 reads the digits of an EAN-8 barcode from the runs after its start guard at i
+func readEAN8(runs []int, i int) ([]byte, int, bool) {
+	left, _, ok := eanHalf(runs, i, 4, false)
+	if !ok || runVariance(runs[i+16:], eanMiddleGuard) > eanMaxVariance {
+		return nil, 0, false
+	}
+
+	right, _, ok := eanHalf(runs, i+21, 4, false)
+	if !ok || runVariance(runs[i+37:], eanGuard) > eanMaxVariance {
+		return nil, 0, false
+	}
+
+	digits := append(left, right...)
+	if mod10CheckDigit(digits[:7]) != digits[7] {
+		return nil, 0, false
+	}
+
+	return digits, i + 40, true
+}
+
+// readUPCE reads the digits of a UPC-E barcode from the runs after its start guard at i.
+// The number system and the check digit are in the codes of its 6 digits, the check digit
+// being that of the UPC-A barcode it is short for.
+func readUPCE(runs []int, i int) ([]byte, int, bool) {
+	middle, parities, ok := eanHalf(runs, i, 6, true)
+	if !ok || runVariance(runs[i+24:], upcEEndGuard) > eanMaxVariance {
+		return nil, 0, false
+	}
+
+	for system := 0; system < 2; system++ {
+		for check, p := range upcEParities {
+			// number system 0 has the codes of number system 1 the other way round
+			if system == 0 {
+				p ^= 0x3F
+			}
+
+			if p != parities {
+				continue
+			}
+
+			digits := append(append([]byte{byte(system) + '0'}, middle...), byte(check)+'0')
+			upcA := expandUPCE(digits)
+			if mod10CheckDigit(upcA[:11]) != upcA[11] {
+				return nil, 0, false
+			}
+
+			return digits, i + 30, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// expandUPCE is the UPC-A barcode a UPC-E one is short for, its last digit
+// telling where the zeros suppressed from the manufacturer and product numbers go
+func expandUPCE(upcE []byte) []byte {
+	d := upcE[1:7]
+
+	upcA := []byte{upcE[0]}
+	switch last := d[5]; {
+	case last <= '2':
+		upcA = append(append(append(upcA, d[0], d[1], last), "0000"...), d[2:5]...)
+	case last == '3':
+		upcA = append(append(upcA, d[:3]...), "00000"...)
+		upcA = append(upcA, d[3:5]...)
+	case last == '4':
+		upcA = append(append(upcA, d[:4]...), "00000"...)
+		upcA = append(upcA, d[4])
+	default:
+		upcA = append(append(upcA, d[:5]...), "0000"...)
+		upcA = append(upcA, last)
+	}
+
+	return append(upcA, upcE[7])
+}
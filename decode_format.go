@@ -1,145 +1,171 @@
 package ar8t
 
-import "errors"
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	errFormatCorrupted = errors.New("format information corrupted")
+	errNoMask          = errors.New("failed to obtain a mask")
+)
+
+// FormatInfo is the format information of a QR Code, decoded from both of its copies
+type FormatInfo struct {
+	ECLevel ECLevel
+
+	// Mask is the data mask pattern reference, 0 to 7
+	Mask byte
+
+	// Errors is the number of bits in error in the copy around the top left finder,
+	// and in the copy split between the other two finders
+	Errors [2]int
+
+	// CopiesDisagree reports that both copies decode to different format information
+	// on their own, so at least one of them is damaged beyond correction
+	CopiesDisagree bool
+}
 
-var maskArr = [15]byte{1, 0, 1, 0, 1, 0, 0, 0, 0, 0, 1, 0, 0, 1, 0}
+// the 15 bit format information is XORed with this mask, so it's never all zeros
+const formatInfoMask = 0b101010000010010
 
-func Format(data QRData) (ECLevel, QRMask, error) {
-	format, err := format1(data)
-	if err != nil {
-		format, err = format2(data)
+// formatCodewords are all valid (masked) format information codewords,
+// indexed by their 5 data bits: 2 bits of error correction level and 3 bits of mask
+var formatCodewords = func() (codewords [32]uint16) {
+	for data := range codewords {
+		codewords[data] = formatBCH(uint16(data)) ^ formatInfoMask
 	}
 
-	if err != nil {
-		return 0, nil, err
+	return
+}()
+
+// formatBCH appends the 10 BCH(15, 5) error correction bits to 5 bits of format information
+func formatBCH(data uint16) uint16 {
+	const generator = 0b10100110111
+
+	remainder := data << 10
+	for i := 14; i >= 10; i-- {
+		if remainder&(1<<i) != 0 {
+			remainder ^= generator << (i - 10)
+		}
 	}
 
-	correction, ok := errorCorrection(2*format[0] + format[1])
-	if !ok {
-		return 0, nil, errors.New("failed to correct errors")
+	return data<<10 | remainder
+}
+
+func Format(data QRData) (ECLevel, QRMask, error) {
+	info, err := DecodeFormat(data)
+	if err != nil {
+		return 0, nil, err
 	}
 
-	mask, ok := mask(4*format[2] + 2*format[3] + format[4])
+	mask, ok := mask(info.Mask)
 	if !ok {
-		return 0, nil, errors.New("failed to obtain a mask")
+		return 0, nil, errNoMask
 	}
 
-	return correction, mask, nil
+	return info.ECLevel, mask, nil
+}
 
+// DecodeFormat compares both copies of the format information to every valid format codeword.
+// Among the codewords within 3 bits of either copy, the one closest to both copies combined wins.
+// The combined distance itself isn't limited to 3 bits, so that a symbol with one copy intact
+// still decodes when the other is destroyed by a damaged corner.
+func DecodeFormat(data QRData) (FormatInfo, error) {
+	return decodeFormat(data.Modules)
 }
 
-func format1(data QRData) ([]byte, error) {
-	format := []byte{}
+func decodeFormat(modules BitMatrix) (FormatInfo, error) {
+	copies := [2]uint16{formatCopy1(modules), formatCopy2(modules)}
 
-	for x := 0; x < 9; x++ {
-		if x == 6 {
-			continue
-		}
+	const maxErrors = 3
 
-		format = append(format, data.Index(uint32(x), 8))
-	}
+	var (
+		best     = -1
+		bestDist int
+		nearest  [2]int
+		nearDist = [2]int{16, 16}
+	)
 
-	yFunc := revIndex(8)
-	for y := 0; y < 8; y++ {
-		y := yFunc(y)
-		if y == 6 {
-			continue
+	for i, codeword := range formatCodewords {
+		dist1 := bits.OnesCount16(copies[0] ^ codeword)
+		dist2 := bits.OnesCount16(copies[1] ^ codeword)
+
+		if dist1 < nearDist[0] {
+			nearest[0], nearDist[0] = i, dist1
+		}
+		if dist2 < nearDist[1] {
+			nearest[1], nearDist[1] = i, dist2
 		}
 
-		format = append(format, data.Index(8, uint32(y)))
-	}
+		if dist1 > maxErrors && dist2 > maxErrors {
+			continue
+		}
 
-	for i := range format {
-		format[i] ^= maskArr[i]
+		if best == -1 || dist1+dist2 < bestDist {
+			best, bestDist = i, dist1+dist2
+		}
 	}
 
-	return correct(format)
-}
-
-func format2(data QRData) ([]byte, error) {
-	format := []byte{}
-	yFunc := revIndex(data.Side)
-	for y := data.Side - 7; y < data.Side; y++ {
-		y := yFunc(y)
-		format = append(format, data.Index(8, y))
+	if best == -1 {
+		return FormatInfo{}, errFormatCorrupted
 	}
 
-	for x := data.Side - 8; x < data.Side; x++ {
-		format = append(format, data.Index(uint32(x), 8))
+	level, ok := errorCorrection(byte(best >> 3))
+	if !ok {
+		return FormatInfo{}, errFormatCorrupted
 	}
 
-	for i := range format {
-		format[i] ^= maskArr[i]
-	}
+	codeword := formatCodewords[best]
 
-	return correct(format)
+	return FormatInfo{
+		ECLevel: level,
+		Mask:    byte(best & 0b111),
+		Errors: [2]int{
+			bits.OnesCount16(copies[0] ^ codeword),
+			bits.OnesCount16(copies[1] ^ codeword),
+		},
+		CopiesDisagree: nearest[0] != nearest[1],
+	}, nil
 }
 
-// this function is a copy pasta,
-// maybe can be written in a better way
-func correct(format []byte) ([]byte, error) {
-	s1 := gf4{}
-
-	for i := range format {
-		valIndex := len(format) - i - 1
-		s1 = s1.AddOrSub(gf4{format[valIndex]}.Mul(exp4[i%15]))
-	}
-
-	if s1 == (gf4{}) {
-		// syndrome == 0, no error detected
-		return format, nil
-	}
-
-	var (
-		s2 = s1.Mul(s1)
-		s4 = s2.Mul(s2)
+// formatCopy1 reads the format information around the top left finder
+func formatCopy1(modules BitMatrix) uint16 {
+	var format uint16
 
-		s3, s5 gf4
-	)
+	for x := 0; x < 9; x++ {
+		if x == 6 {
+			continue
+		}
 
-	for i := range format {
-		index := len(format) - i - 1
-		s3 = s3.AddOrSub(gf4{format[index]}.Mul(exp4[(3*i)%15]))
-		s5 = s5.AddOrSub(gf4{format[index]}.Mul(exp4[(5*i)%15]))
+		format = format<<1 | uint16(modules.bit(x, 8))
 	}
 
-	sigma1 := s1
-	sigma2 := s5.AddOrSub(s4.Mul(sigma1)).AddOrSub(s2.Mul(s3.AddOrSub(s2.Mul(sigma1))).Div(s3.AddOrSub(s1.Mul(s2))))
-	sigma3 := s3.AddOrSub(s2.Mul(sigma1)).AddOrSub(s1.Mul(sigma2))
-
-	errorPos := []byte{}
-
-	for i := byte(0); i < 16; i++ {
-		x := gf4{i}
-		if sigma3.AddOrSub(sigma2.Mul(x)).
-			AddOrSub(sigma1.Mul(x).Mul(x)).
-			AddOrSub(x.Mul(x).Mul(x)) == (gf4{}) {
-			log := log4[i]
-			if log != 0 {
-				errorPos = append(errorPos, log)
-			}
+	for y := 7; y >= 0; y-- {
+		if y == 6 {
+			continue
 		}
-	}
 
-	for _, err := range errorPos {
-		if i := len(format) - int(err) - 1; i >= 0 {
-			format[i] ^= 1
-		}
+		format = format<<1 | uint16(modules.bit(8, y))
 	}
 
-	s1 = gf4{}
+	return format
+}
+
+// formatCopy2 reads the format information next to the bottom left and top right finders
+func formatCopy2(modules BitMatrix) uint16 {
+	var format uint16
+	side := modules.width
 
-	for i := range format {
-		s1 = s1.AddOrSub(gf4{format[len(format)-i-1]}.Mul(exp4[i%15]))
+	for y := side - 1; y >= side-7; y-- {
+		format = format<<1 | uint16(modules.bit(8, y))
 	}
 
-	if s1 == (gf4{}) {
-		// syndrome == 0, no error detected
-		return format, nil
+	for x := side - 8; x < side; x++ {
+		format = format<<1 | uint16(modules.bit(x, 8))
 	}
 
-	return nil, errors.New("format information corrupted")
-
+	return format
 }
 
 func errorCorrection(bits byte) (level ECLevel, ok bool) {
@@ -158,38 +184,3 @@ func errorCorrection(bits byte) (level ECLevel, ok bool) {
 	}
 	return
 }
-
-func mask(bits byte) (QRMask, bool) {
-	switch bits {
-	case 0b000:
-		return qrMask(func(j, i uint32) bool { return (i+j)%2 == 0 })
-	case 0b001:
-		return qrMask(func(_, i uint32) bool { return i%2 == 0 })
-	case 0b010:
-		return qrMask(func(j, _ uint32) bool { return j%3 == 0 })
-	case 0b011:
-		return qrMask(func(j, i uint32) bool { return (i+j)%3 == 0 })
-	case 0b100:
-		return qrMask(func(j, i uint32) bool { return (i/2+j/3)%2 == 0 })
-	case 0b101:
-		return qrMask(func(j, i uint32) bool { return (i*j)%2+(i*j)%3 == 0 })
-	case 0b110:
-		return qrMask(func(j, i uint32) bool { return ((i*j)%2+(i*j)%3)%2 == 0 })
-	case 0b111:
-		return qrMask(func(j, i uint32) bool { return ((i*j)%3+(i+j)%2)%2 == 0 })
-	default:
-		return nil, false
-	}
-}
-
-type formatMask = func(a, b uint32) bool
-
-func qrMask(mask formatMask) (QRMask, bool) {
-	return func(q QRData, i, j uint32) byte {
-		maskVal := 0
-		if mask(i, j) {
-			maskVal = 1
-		}
-		return q.Index(i, j) ^ byte(maskVal)
-	}, true
-}
@@ -0,0 +1,162 @@
+package ar8t
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// formatQRData returns a version 1 symbol holding only the two format copies
+func formatQRData(copy1, copy2 uint16) QRData {
+	data := QRData{Modules: NewBitMatrix(21, 21), Version: 1, Side: 21}
+
+	bit := 14
+	set := func(x, y uint32, word uint16) {
+		if word&(1<<bit) != 0 {
+			data.Modules.Set(int(x), int(y), true)
+		}
+		bit--
+	}
+
+	for x := uint32(0); x < 9; x++ {
+		if x != 6 {
+			set(x, 8, copy1)
+		}
+	}
+	for y := 7; y >= 0; y-- {
+		if y != 6 {
+			set(8, uint32(y), copy1)
+		}
+	}
+
+	bit = 14
+	for y := data.Side - 1; y >= data.Side-7; y-- {
+		set(8, y, copy2)
+	}
+	for x := data.Side - 8; x < data.Side; x++ {
+		set(x, 8, copy2)
+	}
+
+	return data
+}
+
+func Test_DecodeFormat(t *testing.T) {
+	// quartile, mask 0b101
+	codeword := formatCodewords[0b11101]
+
+	tests := []struct {
+		name           string
+		copy1, copy2   uint16
+		wantErr        bool
+		wantErrors     [2]int
+		copiesDisagree bool
+	}{
+		{
+			name:  "intact",
+			copy1: codeword, copy2: codeword,
+		},
+		{
+			name:  "top left copy destroyed",
+			copy1: codeword ^ 0b111111100000000, copy2: codeword,
+			wantErrors:     [2]int{7, 0},
+			copiesDisagree: true,
+		},
+		{
+			name:  "other copy destroyed",
+			copy1: codeword ^ 0b000000000000001, copy2: codeword ^ 0b000001111111000,
+			wantErrors:     [2]int{1, 7},
+			copiesDisagree: true,
+		},
+		{
+			name:  "both copies damaged",
+			copy1: codeword ^ 0b100000000000101, copy2: codeword ^ 0b010000000000010,
+			wantErrors: [2]int{3, 2},
+		},
+		{
+			name:  "both copies destroyed",
+			copy1: codeword ^ 0b111100000000000, copy2: codeword ^ 0b000000000001111,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := DecodeFormat(formatQRData(tt.copy1, tt.copy2))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, FormatInfo{
+					ECLevel:        ECLevelQuartile,
+					Mask:           0b101,
+					Errors:         tt.wantErrors,
+					CopiesDisagree: tt.copiesDisagree,
+				}, info)
+			}
+		})
+	}
+}
+
+func Test_FormatBlocks(t *testing.T) {
+	modules, err := ParseBitMatrix(`
+		#######..###..#######
+		#.....#....##.#.....#
+		#.###.#.##.#..#.###.#
+		#.###.#.##.##.#.###.#
+		#.###.#.#.#.#.#.###.#
+		#.....#.#.##..#.....#
+		#######.#.#.#.#######
+		........#............
+		#.#####...##..#####..
+		.##.#...###.##..#.###
+		#..#..#...#.#......#.
+		#.#..#.##....#..#.##.
+		.###.##.####..#.##...
+		........#......##...#
+		#######....###....##.
+		#.....#.####.#.#####.
+		#.###.#.#..#.###.#.##
+		#.###.#.#....#..###..
+		#.###.#.#.###.....#..
+		#.....#...####.#..#..
+		#######.#.....##...#.
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data := QRData{Modules: modules, Version: 1, Side: 21}
+
+	// the exported mask function reads the same codewords as the precomputed mask patterns
+	level, mask, err := Format(data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	blocks, err := Blocks(data, level, mask)
+	if !assert.NoError(t, err) || !assert.Len(t, blocks, 1) {
+		return
+	}
+
+	info, err := DecodeFormat(data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	want, _, err := blocksWithConfidence(data, info.ECLevel, info.Mask)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, blocks)
+	}
+
+	blockInfo, err := GetBlockInfo(data.Version, level)
+	if !assert.NoError(t, err) {
+		return
+	}
+	corrected, err := Correct(blocks[0], blockInfo[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	decoded, err := Data(corrected[:blockInfo[0].DataPer], data.Version)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ar8t rotation", string(decoded))
+	}
+}
@@ -0,0 +1,83 @@
+package ar8t
+
+// itfDigits are the wide runs of the digits of ITF barcodes, 2 of 5 runs being wide.
+// The first run is the most significant bit.
+var itfDigits = [10]int{0x06, 0x11, 0x09, 0x18, 0x05, 0x14, 0x0C, 0x03, 0x12, 0x0A}
+
+// the shortest ITF barcodes read, shorter runs of digits are too often found in other barcodes and text
+const itfMinDigits = 6
+
+// readITF reads an Interleaved 2 of 5 barcode. Its digits go in pairs, the dark runs of a pair
+// being the first digit and the light runs between them the second one.
+func (s LinearScan) readITF(runs []int, start int) (Symbology, []byte, int, bool) {
+	// the start pattern is 4 narrow runs, the light runs around the barcode are 10 of them wide
+	if start+4 > len(runs) {
+		return 0, nil, 0, false
+	}
+
+	narrow := float64(sum(runs[start:start+4])) / 4
+	quiet := func(i int) bool {
+		return float64(runs[i]) >= 10*narrow
+	}
+
+	if !quiet(start-1) || runVariance(runs[start:], []int{1, 1, 1, 1}) > eanMaxVariance {
+		return 0, nil, 0, false
+	}
+
+	digit := func(runs []int) (byte, bool) {
+		wide, ok := wideRuns(runs, 2)
+		if !ok {
+			return 0, false
+		}
+
+		for d, p := range itfDigits {
+			if p == wide {
+				return byte(d) + '0', true
+			}
+		}
+		return 0, false
+	}
+
+	data := []byte{}
+	i := start + 4
+	for {
+		// the stop pattern is a wide dark run then two narrow runs
+		if i+3 < len(runs) && quiet(i+3) && runVariance(runs[i:], []int{2, 1, 1}) <= eanMaxVariance {
+			i += 3
+			break
+		}
+
+		if i+10 > len(runs) {
+			return 0, nil, 0, false
+		}
+
+		var dark, light [5]int
+		for j := 0; j < 5; j++ {
+			dark[j], light[j] = runs[i+2*j], runs[i+2*j+1]
+		}
+
+		first, ok := digit(dark[:])
+		if !ok {
+			return 0, nil, 0, false
+		}
+
+		second, ok := digit(light[:])
+		if !ok {
+			return 0, nil, 0, false
+		}
+
+		data = append(data, first, second)
+		i += 10
+	}
+
+	if len(data) < itfMinDigits {
+		return 0, nil, 0, false
+	}
+
+	// the check digit is part of the number, as in ITF-14
+	if s.ITFCheckDigit && mod10CheckDigit(data[:len(data)-1]) != data[len(data)-1] {
+		return 0, nil, 0, false
+	}
+
+	return SymbologyITF, data, i, true
+}
@@ -0,0 +1,109 @@
+package ar8t
+
+import (
+	"math"
+	"sort"
+)
+
+// linearReader reads a barcode whose first bar is the dark run runs[start]. runs are the lengths
+// of the runs along a line, light ones at even indices and dark ones at odd indices.
+// It returns the symbology and content of the barcode and the index of the light run after it.
+type linearReader func(runs []int, start int) (Symbology, []byte, int, bool)
+
+// the largest difference between a run and the pattern it is matched to, in modules
+const maxRunVariance = 0.7
+
+// runVariance scales pattern, in modules, to the length of as many runs and returns the mean
+// difference between the runs and the pattern per pixel. It is infinite when there are fewer
+// pixels than modules or a single run is further off than maxRunVariance.
+func runVariance(runs, pattern []int) float64 {
+	if len(runs) < len(pattern) {
+		return math.Inf(1)
+	}
+
+	total, modules := 0, 0
+	for i, p := range pattern {
+		total += runs[i]
+		modules += p
+	}
+
+	if total < modules {
+		return math.Inf(1)
+	}
+
+	moduleWidth := float64(total) / float64(modules)
+
+	variance := 0.0
+	for i, p := range pattern {
+		d := math.Abs(float64(runs[i]) - float64(p)*moduleWidth)
+		if d > maxRunVariance*moduleWidth {
+			return math.Inf(1)
+		}
+
+		variance += d
+	}
+
+	return variance / float64(total)
+}
+
+// bestPattern is the index of the pattern closest to runs, if its variance is below maxVariance
+func bestPattern(runs []int, patterns [][]int, maxVariance float64) (int, bool) {
+	best, bestVariance := -1, maxVariance
+	for i, pattern := range patterns {
+		if v := runVariance(runs, pattern); v < bestVariance {
+			best, bestVariance = i, v
+		}
+	}
+
+	return best, best != -1
+}
+
+// wideRuns tells the wide runs from the narrow ones when exactly wide of them are wider than
+// the others, and half as wide again as them on average. The first run is the most significant bit.
+func wideRuns(runs []int, wide int) (int, bool) {
+	sorted := append([]int{}, runs...)
+	sort.Ints(sorted)
+
+	n := len(sorted) - wide
+	if sorted[n] == sorted[n-1] {
+		return 0, false
+	}
+
+	narrowMean := float64(sum(sorted[:n])) / float64(n)
+	wideMean := float64(sum(sorted[n:])) / float64(wide)
+	if wideMean < narrowMean*1.5 {
+		return 0, false
+	}
+
+	bits := 0
+	for _, run := range runs {
+		bits <<= 1
+		if run >= sorted[n] {
+			bits |= 1
+		}
+	}
+
+	return bits, true
+}
+
+func sum(runs []int) int {
+	total := 0
+	for _, run := range runs {
+		total += run
+	}
+	return total
+}
+
+// mod10CheckDigit is the check digit of EAN, UPC and ITF-14 digits, the last digit being weighted by 3
+func mod10CheckDigit(digits []byte) byte {
+	total := 0
+	for i := range digits {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		total += d
+	}
+
+	return byte((10-total%10)%10) + '0'
+}
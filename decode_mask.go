@@ -0,0 +1,107 @@
+package ar8t
+
+import "sync"
+
+// maskPredicates tell whether the module in row i, column j is inverted by the mask
+var maskPredicates = [8]func(i, j uint32) bool{
+	func(i, j uint32) bool { return (i+j)%2 == 0 },
+	func(i, _ uint32) bool { return i%2 == 0 },
+	func(_, j uint32) bool { return j%3 == 0 },
+	func(i, j uint32) bool { return (i+j)%3 == 0 },
+	func(i, j uint32) bool { return (i/2+j/3)%2 == 0 },
+	func(i, j uint32) bool { return (i*j)%2+(i*j)%3 == 0 },
+	func(i, j uint32) bool { return ((i*j)%2+(i*j)%3)%2 == 0 },
+	func(i, j uint32) bool { return ((i*j)%3+(i+j)%2)%2 == 0 },
+}
+
+// mask returns the data mask pattern of reference, 0 to 7
+func mask(reference byte) (QRMask, bool) {
+	if int(reference) >= len(maskPredicates) {
+		return nil, false
+	}
+
+	predicate := maskPredicates[reference]
+	return func(data QRData, x, y uint32) byte {
+		module := data.Modules.bit(int(x), int(y))
+		if predicate(y, x) {
+			return module ^ 1
+		}
+		return module
+	}, true
+}
+
+// versionPatterns are computed once per version, the first time a symbol of that version is decoded
+type versionPatterns struct {
+	once sync.Once
+
+	masks [8]BitMatrix
+
+	// dataOrder are the data modules, as y*side + x, in the order codewords are placed
+	dataOrder []uint32
+}
+
+var patterns [41]versionPatterns
+
+func getVersionPatterns(version uint32) (*versionPatterns, error) {
+	if version < 1 || version > 40 {
+		return nil, errUnsupportedVersion
+	}
+
+	p := &patterns[version]
+	p.once.Do(func() {
+		side := 17 + 4*version
+
+		for i, predicate := range maskPredicates {
+			m := NewBitMatrix(int(side), int(side))
+			for y := uint32(0); y < side; y++ {
+				for x := uint32(0); x < side; x++ {
+					if predicate(y, x) {
+						m.Set(int(x), int(y), true)
+					}
+				}
+			}
+			p.masks[i] = m
+		}
+
+		p.dataOrder = dataModuleOrder(version, side)
+	})
+
+	return p, nil
+}
+
+// dataModuleOrder walks the symbol in two module wide columns from the bottom right,
+// upwards and downwards in turn, skipping function patterns
+func dataModuleOrder(version, side uint32) []uint32 {
+	data := QRData{Version: version, Side: side}
+	loc, _ := getAlignmentLocation(version)
+
+	order := []uint32{}
+	x := side - 1
+
+	for {
+		yRange := yRange(x, side)
+		for y := uint32(0); y < side; y++ {
+			y := yRange(y)
+
+			if isData(data, loc, x, y) {
+				order = append(order, y*side+x)
+			}
+
+			if isData(data, loc, x-1, y) {
+				order = append(order, y*side+x-1)
+			}
+		}
+
+		if x == 1 {
+			break
+		}
+
+		x -= 2
+		if x == 6 {
+			// skip timing pattern
+			x = 5
+		}
+	}
+
+	return order
+}
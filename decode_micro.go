@@ -0,0 +1,327 @@
+package ar8t
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/mrg0lden/ar8t/reedsolomon"
+)
+
+var errMicroFormatCorrupted = errors.New("micro QR format information corrupted")
+
+// MicroQRDecoder decodes Micro QR Codes, M1 to M4.
+// QRData.Version is 1 to 4 for M1 to M4, the side of the symbol being 9 + 2*Version.
+type MicroQRDecoder struct{}
+
+// MicroFormatInfo is the format information of a Micro QR Code
+type MicroFormatInfo struct {
+	// Version is 1 to 4 for M1 to M4
+	Version uint32
+
+	// ECLevel is ECLevelLow for M1, which only detects errors
+	ECLevel ECLevel
+
+	// Mask is one of the four Micro QR masks, 0 to 3
+	Mask byte
+
+	// Errors is the number of bits in error in the format information
+	Errors int
+}
+
+// the 15 bit format information of Micro QR Codes is XORed with this mask instead
+const microFormatInfoMask = 0b100010001000101
+
+// microSymbols are the versions and error correction levels of the 3 bit symbol numbers
+// in the format information
+var microSymbols = [8]struct {
+	version uint32
+	level   ECLevel
+}{
+	{1, ECLevelLow},
+	{2, ECLevelLow}, {2, ECLevelMedium},
+	{3, ECLevelLow}, {3, ECLevelMedium},
+	{4, ECLevelLow}, {4, ECLevelMedium}, {4, ECLevelQuartile},
+}
+
+// microFormatCodewords are indexed by their 5 data bits: 3 bits of symbol number and 2 bits of mask
+var microFormatCodewords = func() (codewords [32]uint16) {
+	for data := range codewords {
+		codewords[data] = formatBCH(uint16(data)) ^ microFormatInfoMask
+	}
+
+	return
+}()
+
+// microBlockInfo of every Micro QR Code, each has a single block.
+// The last data codeword of M1 and M3 is only 4 bits long.
+var microBlockInfo = map[uint32]map[ECLevel]BlockInfo{
+	1: {
+		ECLevelLow: {1, 5, 3, 0},
+	},
+	2: {
+		ECLevelLow:    {1, 10, 5, 1},
+		ECLevelMedium: {1, 10, 4, 2},
+	},
+	3: {
+		ECLevelLow:    {1, 17, 11, 2},
+		ECLevelMedium: {1, 17, 9, 4},
+	},
+	4: {
+		ECLevelLow:      {1, 24, 16, 3},
+		ECLevelMedium:   {1, 24, 14, 5},
+		ECLevelQuartile: {1, 24, 10, 7},
+	},
+}
+
+// microMaskPredicates are the four QR Code masks Micro QR Codes use
+var microMaskPredicates = [4]func(i, j uint32) bool{
+	maskPredicates[1],
+	maskPredicates[4],
+	maskPredicates[6],
+	maskPredicates[7],
+}
+
+var microPatterns [5]versionPatterns
+
+// microModes are the segment readers in the order of their mode indicators,
+// with the length of their character count indicator for M1 to M4, 0 when the mode is not available
+var microModes = []struct {
+	read       segmentReader
+	lengthBits [4]uint8
+}{
+	{numeric, [...]uint8{3, 4, 5, 6}},
+	{alphanumeric, [...]uint8{0, 3, 4, 5}},
+	{eightBit, [...]uint8{0, 0, 4, 5}},
+	{kanji, [...]uint8{0, 0, 3, 4}},
+}
+
+func (MicroQRDecoder) Decode(qrData QRData) ([]byte, error) {
+	format, err := DecodeMicroFormat(qrData)
+	if err != nil {
+		return nil, err
+	}
+
+	if 9+2*format.Version != qrData.Side {
+		return nil, errVersionMismatch
+	}
+
+	info := microBlockInfo[format.Version][format.ECLevel]
+
+	block, confidence, err := microCodewords(qrData, format.Version, info, format.Mask)
+	if err != nil {
+		return nil, err
+	}
+
+	if qrData.Confidence == nil {
+		confidence = nil
+	}
+
+	corrected, _, err := correctSoft(block, info, confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	// the codewords left over after correction (all of M1's) only detect errors
+	symbols := make([]int, len(corrected))
+	for i, c := range corrected {
+		symbols[i] = int(c)
+	}
+	if _, valid := reedsolomon.QRCode.Syndromes(symbols, int(info.TotalPer-info.DataPer)); !valid {
+		return nil, reedsolomon.ErrTooManyErrors
+	}
+
+	return MicroData(corrected[:info.DataPer], format.Version)
+}
+
+// DecodeMicroFormat reads the format information next to the finder of a Micro QR Code,
+// the closest valid codeword within 3 bits wins
+func DecodeMicroFormat(data QRData) (MicroFormatInfo, error) {
+	var format uint16
+
+	for x := 1; x <= 8; x++ {
+		format = format<<1 | uint16(data.Modules.bit(x, 8))
+	}
+
+	for y := 7; y >= 1; y-- {
+		format = format<<1 | uint16(data.Modules.bit(8, y))
+	}
+
+	const maxErrors = 3
+
+	best, bestDist := -1, maxErrors+1
+	for i, codeword := range microFormatCodewords {
+		if dist := bits.OnesCount16(format ^ codeword); dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+
+	if best == -1 {
+		return MicroFormatInfo{}, errMicroFormatCorrupted
+	}
+
+	symbol := microSymbols[best>>2]
+
+	return MicroFormatInfo{
+		Version: symbol.version,
+		ECLevel: symbol.level,
+		Mask:    byte(best & 0b11),
+		Errors:  bestDist,
+	}, nil
+}
+
+// microCodewords reads the codewords of a Micro QR Code and their confidence.
+// The 4 bit data codeword of M1 and M3 is returned in the high bits of its byte.
+func microCodewords(data QRData, version uint32, info BlockInfo, mask byte) ([]byte, []byte, error) {
+	if int(mask) >= len(microMaskPredicates) {
+		return nil, nil, errNoMask
+	}
+
+	side := 9 + 2*version
+	if data.Modules.Width() != int(side) || data.Modules.Height() != int(side) {
+		return nil, nil, fmt.Errorf("expected %dx%d modules for M%d but found %dx%d",
+			side, side, version, data.Modules.Width(), data.Modules.Height())
+	}
+
+	patterns := getMicroPatterns(version)
+	unmasked := data.Modules.Xor(patterns.masks[mask])
+
+	var (
+		block      = make([]byte, 0, info.TotalPer)
+		confidence = make([]byte, 0, info.TotalPer)
+
+		current, currentConfidence byte
+		bitCount                   int
+	)
+
+	codewordBits := func(i int) int {
+		if (version == 1 || version == 3) && i == int(info.DataPer)-1 {
+			return 4
+		}
+		return 8
+	}
+
+	for _, index := range patterns.dataOrder {
+		current = current<<1 | unmasked.bit(int(index%side), int(index/side))
+		bitCount++
+
+		if c := data.confidence(index); bitCount == 1 || c < currentConfidence {
+			currentConfidence = c
+		}
+
+		if n := codewordBits(len(block)); bitCount == n {
+			block = append(block, current<<(8-n))
+			confidence = append(confidence, currentConfidence)
+			current, bitCount = 0, 0
+		}
+	}
+
+	if len(block) != int(info.TotalPer) {
+		return nil, nil, fmt.Errorf("expected %d codewords but found %d", info.TotalPer, len(block))
+	}
+
+	return block, confidence, nil
+}
+
+// getMicroPatterns computes the masks and data module order of a Micro QR version once
+func getMicroPatterns(version uint32) *versionPatterns {
+	p := &microPatterns[version]
+	p.once.Do(func() {
+		side := 9 + 2*version
+
+		for i, predicate := range microMaskPredicates {
+			m := NewBitMatrix(int(side), int(side))
+			for y := uint32(0); y < side; y++ {
+				for x := uint32(0); x < side; x++ {
+					if predicate(y, x) {
+						m.Set(int(x), int(y), true)
+					}
+				}
+			}
+			p.masks[i] = m
+		}
+
+		p.dataOrder = microDataModuleOrder(side)
+	})
+
+	return p
+}
+
+// microDataModuleOrder walks the symbol in two module wide columns from the bottom right,
+// upwards and downwards in turn. Unlike QR Codes the timing patterns are on the edges,
+// so there is no column to skip.
+func microDataModuleOrder(side uint32) []uint32 {
+	isData := func(x, y uint32) bool {
+		return x > 0 && y > 0 && (x > 8 || y > 8)
+	}
+
+	order := []uint32{}
+	upwards := true
+
+	for x := side - 1; x >= 1; x -= 2 {
+		for i := uint32(0); i < side; i++ {
+			y := i
+			if upwards {
+				y = side - 1 - i
+			}
+
+			if isData(x, y) {
+				order = append(order, y*side+x)
+			}
+
+			if isData(x-1, y) {
+				order = append(order, y*side+x-1)
+			}
+		}
+
+		upwards = !upwards
+	}
+
+	return order
+}
+
+// MicroData decodes the data codewords of a Micro QR Code.
+// Mode indicators are version-1 bits long, M1 only having numeric mode,
+// and the terminator is a numeric segment of length 0.
+func MicroData(input []byte, version uint32) ([]byte, error) {
+	if version < 1 || version > 4 {
+		return nil, errUnsupportedVersion
+	}
+
+	chomp := NewChomp(input)
+	result := bytes.Buffer{}
+	modeBits := uint8(version - 1)
+
+	for chomp.bitsLeft > uint32(modeBits) {
+		var mode byte
+		if modeBits > 0 {
+			mode, _ = chomp.Chomp(modeBits)
+		}
+
+		if int(mode) >= len(microModes) || microModes[mode].lengthBits[version-1] == 0 {
+			return nil, fmt.Errorf("mode %.*b not yet implemented", modeBits, mode)
+		}
+
+		m := microModes[mode]
+		lengthBits := m.lengthBits[version-1]
+
+		// the terminator is cut short when the data fills the symbol
+		if chomp.bitsLeft < uint32(lengthBits) {
+			break
+		}
+
+		data, err := m.read(chomp, lengthBits)
+		if err != nil {
+			return nil, err
+		}
+
+		if mode == 0 && len(data) == 0 {
+			break
+		}
+
+		result.Write(data)
+	}
+
+	return result.Bytes(), nil
+}
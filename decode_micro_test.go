@@ -0,0 +1,130 @@
+package ar8t
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// microTests are Micro QR Codes of M1, M2 and M4, the damaged ones drawn undamaged by Test_MicroScan
+var microTests = []decoderTest[MicroFormatInfo]{
+	{
+		name: "M1 numeric",
+		symbol: `
+			#######.#.#
+			#.....#..#.
+			#.###.#.#..
+			#.###.#..#.
+			#.###.#...#
+			#.....#.##.
+			#######.#..
+			........#..
+			##.....#...
+			...##...#.#
+			##.##.#.##.
+		`,
+		format: MicroFormatInfo{Version: 1, ECLevel: ECLevelLow, Mask: 1},
+		want:   "12345",
+	},
+	{
+		name: "M2 alphanumeric",
+		symbol: `
+			#######.#.#.#
+			#.....#.##.##
+			#.###.#..##.#
+			#.###.#.#.#..
+			#.###.#.##..#
+			#.....#.#..#.
+			#######.##.#.
+			........#..#.
+			###.##.####..
+			..#####..#.#.
+			#.#.#..#...##
+			.#.....##.###
+			##...###..#..
+		`,
+		format: MicroFormatInfo{Version: 2, ECLevel: ECLevelMedium, Mask: 2},
+		want:   "AR8T",
+	},
+	{
+		name: "M4 byte and numeric",
+		symbol: `
+			#######.#.#.#.#.#
+			#.....#......#...
+			#.###.#....####.#
+			#.###.#.#.#..#.##
+			#.###.#.#...##.#.
+			#.....#..#....#..
+			#######.####.....
+			........########.
+			#..##....##..####
+			....##..#.##.#...
+			#.#.#.#.#.#.#####
+			..#.##....#..####
+			##..####..#.#####
+			....########....#
+			##.#.##.#####.###
+			.#######.#.#.###.
+			####.###.#..##.#.
+		`,
+		format:  MicroFormatInfo{Version: 4, ECLevel: ECLevelLow, Mask: 3},
+		flipped: [][2]int{{16, 16}, {10, 14}, {3, 12}},
+		want:    "micro42",
+	},
+}
+
+func Test_MicroQRDecoder(t *testing.T) {
+	testDecoder(t, microTests, func(t *testing.T, modules BitMatrix, want MicroFormatInfo) ([]byte, error) {
+		data := QRData{Modules: modules, Version: want.Version, Side: uint32(modules.Width())}
+
+		format, err := DecodeMicroFormat(data)
+		if assert.NoError(t, err) {
+			assert.Equal(t, want, format)
+		}
+
+		return MicroQRDecoder{}.Decode(data)
+	})
+}
+
+func Test_MicroScan(t *testing.T) {
+	for _, tt := range microTests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDecodeAll(t, SymbologyMicroQR, tt.symbol, 6, tt.want, 0, 90, 180, 270, 30)
+		})
+	}
+}
+
+func Test_MicroData(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    string
+		version uint32
+		want    []byte
+	}{
+		{
+			name:    "M3 kanji",
+			bits:    "11 001 0110110011111 0000000",
+			version: 3,
+			want:    []byte{0x93, 0x5F},
+		},
+		{
+			name:    "M4 kanji",
+			bits:    "011 0010 0110110011111 1101010101010 000000000",
+			version: 4,
+			want:    []byte{0x93, 0x5F, 0xE4, 0xAA},
+		},
+		{
+			name:    "M4 numeric and kanji",
+			bits:    "000 000001 0111 011 0001 1101010101010 000000000",
+			version: 4,
+			want:    []byte{'7', 0xE4, 0xAA},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MicroData(packBits(tt.bits), tt.version)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package ar8t
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/mrg0lden/ar8t/reedsolomon"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	errPDF417Length = errors.New("invalid PDF417 symbol length descriptor")
+	errPDF417Data   = errors.New("invalid PDF417 data")
+)
+
+// PDF417Decoder decodes PDF417 symbols from their codewords, see PDF417Extract
+type PDF417Decoder struct{}
+
+func (PDF417Decoder) Decode(codewords PDF417Codewords) ([]byte, error) {
+	words := slices.Clone(codewords.Codewords)
+	ecLen := 2 << codewords.ECLevel
+	if ecLen >= len(words) {
+		return nil, errPDF417Length
+	}
+
+	if _, err := reedsolomon.PDF417.Decode(words, ecLen, codewords.Erasures); err != nil {
+		return nil, err
+	}
+
+	// two error correction codewords are kept to detect misdecoding,
+	// errors at unknown positions take twice as many as erasures
+	errorCount := 0
+	for i := range words {
+		if words[i] != codewords.Codewords[i] && !slices.Contains(codewords.Erasures, i) {
+			errorCount++
+		}
+	}
+
+	if 2*errorCount+len(codewords.Erasures) > max(ecLen-2, 0) {
+		return nil, reedsolomon.ErrTooManyErrors
+	}
+
+	// the first codeword is the number of data codewords, counting itself
+	length := words[0]
+	if length < 1 || length > len(words)-ecLen {
+		return nil, errPDF417Length
+	}
+
+	return PDF417Data(words[1:length])
+}
+
+// the codewords switching between compaction modes, and the other function codewords
+const (
+	pdf417Text            = 900
+	pdf417Byte            = 901
+	pdf417Numeric         = 902
+	pdf417ByteShift       = 913
+	pdf417ReaderInit      = 921
+	pdf417MacroTerminator = 922
+	pdf417MacroOptional   = 923
+	pdf417Byte6           = 924
+	pdf417ECIUser         = 925
+	pdf417ECIGeneral      = 926
+	pdf417ECI             = 927
+	pdf417MacroControl    = 928
+)
+
+// the submodes of text compaction
+const (
+	pdf417Alpha = iota
+	pdf417Lower
+	pdf417Mixed
+	pdf417Punct
+)
+
+// pdf417TextCharacters are the characters of every text submode by value. The values switching
+// submodes are past them, but for the one in the middle of the mixed submode written as 0.
+var pdf417TextCharacters = [...]string{
+	pdf417Alpha: "ABCDEFGHIJKLMNOPQRSTUVWXYZ ",
+	pdf417Lower: "abcdefghijklmnopqrstuvwxyz ",
+	pdf417Mixed: "0123456789&\r\t,:#-.$/+%*=^\x00 ",
+	pdf417Punct: ";<>@[\\]_`~!\r\t,:\n-.$/\"|*()?{}'",
+}
+
+// pdf417TextSwitches are the values switching submodes, a shift only for the next character
+var pdf417TextSwitches = [...]map[int]struct {
+	submode int
+	shift   bool
+}{
+	pdf417Alpha: {27: {pdf417Lower, false}, 28: {pdf417Mixed, false}, 29: {pdf417Punct, true}},
+	pdf417Lower: {27: {pdf417Alpha, true}, 28: {pdf417Mixed, false}, 29: {pdf417Punct, true}},
+	pdf417Mixed: {25: {pdf417Punct, false}, 27: {pdf417Lower, false}, 28: {pdf417Alpha, false}, 29: {pdf417Punct, true}},
+	pdf417Punct: {29: {pdf417Alpha, false}},
+}
+
+// PDF417Data decodes the data codewords of a PDF417 symbol, after the symbol length descriptor.
+// The data starts in text compaction, in its alpha submode.
+func PDF417Data(words []int) ([]byte, error) {
+	var (
+		result = bytes.Buffer{}
+		mode   = pdf417Text
+
+		// the submode of text compaction, kept across byte shifts
+		submode = pdf417Alpha
+	)
+
+	i := 0
+	for i < len(words) {
+		// the codewords up to the next mode switch
+		end := i
+		for end < len(words) && words[end] < pdf417Text {
+			end++
+		}
+
+		switch mode {
+		case pdf417Text:
+			submode = pdf417TextData(&result, words[i:end], submode)
+		case pdf417Byte, pdf417Byte6:
+			pdf417ByteData(&result, words[i:end], mode == pdf417Byte6)
+		case pdf417Numeric:
+			if err := pdf417NumericData(&result, words[i:end]); err != nil {
+				return nil, err
+			}
+		}
+
+		if end == len(words) {
+			break
+		}
+
+		i = end + 1
+		switch words[end] {
+		case pdf417Text:
+			mode, submode = pdf417Text, pdf417Alpha
+		case pdf417Byte, pdf417Byte6, pdf417Numeric:
+			mode = words[end]
+		case pdf417ByteShift:
+			// a single byte in text compaction
+			if i == len(words) {
+				return nil, errPDF417Data
+			}
+			result.WriteByte(byte(words[i]))
+			i++
+		case pdf417ECI, pdf417ECIUser:
+			// the designator is left to the application, as in AztecData
+			i++
+		case pdf417ECIGeneral:
+			i += 2
+		case pdf417ReaderInit:
+		case pdf417MacroControl, pdf417MacroOptional, pdf417MacroTerminator:
+			// the Macro PDF417 control block ends the data
+			return result.Bytes(), nil
+		default:
+			return nil, errPDF417Data
+		}
+	}
+
+	return result.Bytes(), nil
+}
+
+// pdf417TextData decodes text compaction codewords, two values each,
+// and returns the submode they end in
+func pdf417TextData(result *bytes.Buffer, words []int, submode int) int {
+	// latch is the submode returned to after a shift
+	latch := submode
+
+	for _, word := range words {
+		for _, value := range [...]int{word / 30, word % 30} {
+			if s, ok := pdf417TextSwitches[submode][value]; ok {
+				latch = submode
+				submode = s.submode
+				if !s.shift {
+					latch = submode
+				}
+				continue
+			}
+
+			result.WriteByte(pdf417TextCharacters[submode][value])
+			submode = latch
+		}
+	}
+
+	return latch
+}
+
+// pdf417ByteData decodes byte compaction codewords, 5 codewords in base 900 for every 6 bytes.
+// After latching with 901 rather than 924 the last 1 to 5 codewords are a byte each.
+func pdf417ByteData(result *bytes.Buffer, words []int, whole bool) {
+	groups := len(words) / 5
+	if !whole && len(words) > 0 {
+		groups = (len(words) - 1) / 5
+	}
+
+	for g := 0; g < groups; g++ {
+		value := uint64(0)
+		for _, word := range words[5*g : 5*g+5] {
+			value = value*900 + uint64(word)
+		}
+
+		for shift := 40; shift >= 0; shift -= 8 {
+			result.WriteByte(byte(value >> shift))
+		}
+	}
+
+	for _, word := range words[5*groups:] {
+		result.WriteByte(byte(word))
+	}
+}
+
+// pdf417NumericData decodes numeric compaction codewords, every 15 codewords in base 900
+// being a number with a leading 1 that is not part of the data
+func pdf417NumericData(result *bytes.Buffer, words []int) error {
+	for len(words) > 0 {
+		group := words[:min(len(words), 15)]
+		words = words[len(group):]
+
+		value := big.NewInt(0)
+		for _, word := range group {
+			value.Mul(value, big.NewInt(900))
+			value.Add(value, big.NewInt(int64(word)))
+		}
+
+		digits := value.String()
+		if digits[0] != '1' {
+			return errPDF417Data
+		}
+		result.WriteString(digits[1:])
+	}
+
+	return nil
+}
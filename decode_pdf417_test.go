@@ -0,0 +1,90 @@
+package ar8t
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PDF417Decoder(t *testing.T) {
+	tests := []struct {
+		name      string
+		codewords []int
+		ecLevel   int
+		flipped   []int
+		erasures  []int
+		want      string
+	}{
+		{
+			name:      "text",
+			codewords: []int{5, 453, 178, 121, 239, 452, 327, 657, 619},
+			ecLevel:   1,
+			want:      "PDF417",
+		},
+		{
+			name:      "text submodes with errors",
+			codewords: []int{20, 387, 263, 123, 854, 807, 1, 89, 27, 717, 747, 776, 841, 63, 484, 156, 795, 239, 900, 900, 554, 748, 15, 254},
+			ecLevel:   1,
+			flipped:   []int{3},
+			want:      "Mixed: abc;XYZ 123-456 #7",
+		},
+		{
+			name:      "numeric with erasures",
+			codewords: []int{14, 902, 32, 233, 711, 421, 825, 143, 559, 387, 567, 648, 11, 223, 666, 701, 261, 504},
+			ecLevel:   1,
+			erasures:  []int{4, 9},
+			want:      "0123456789012345678901234567890123",
+		},
+		{
+			name:      "byte",
+			codewords: []int{14, 901, 111, 359, 741, 47, 182, 54, 255, 257, 739, 547, 33, 900, 373, 198, 714, 396},
+			ecLevel:   1,
+			want:      "Byte\x01\x02 data\xff!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codewords := PDF417Codewords{
+				Codewords: append([]int{}, tt.codewords...),
+				ECLevel:   tt.ecLevel,
+				Erasures:  tt.erasures,
+			}
+
+			for _, i := range tt.flipped {
+				codewords.Codewords[i] = (codewords.Codewords[i] + 1) % 929
+			}
+			for _, i := range tt.erasures {
+				codewords.Codewords[i] = 0
+			}
+
+			got, err := PDF417Decoder{}.Decode(codewords)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+// Test_PDF417Scan decodes a symbol of 3 columns and 5 rows, each row 3 modules high
+func Test_PDF417Scan(t *testing.T) {
+	const symbol = `
+		########.#.#.#...####.#.#.####....###.#.#...###....###.###.##..##...#####.##.#.####..#####.#.#.#####..#######.#...#.#..#
+		########.#.#.#...####.#.#.####....###.#.#...###....###.###.##..##...#####.##.#.####..#####.#.#.#####..#######.#...#.#..#
+		########.#.#.#...####.#.#.####....###.#.#...###....###.###.##..##...#####.##.#.####..#####.#.#.#####..#######.#...#.#..#
+		########.#.#.#...######.#.#...###.####.#...#.#.....####..#.###..###.#.######.#.##....######.#.#.###...#######.#...#.#..#
+		########.#.#.#...######.#.#...###.####.#...#.#.....####..#.###..###.#.######.#.##....######.#.#.###...#######.#...#.#..#
+		########.#.#.#...######.#.#...###.####.#...#.#.....####..#.###..###.#.######.#.##....######.#.#.###...#######.#...#.#..#
+		########.#.#.#...#.#.#..####......##...#####..#..#.##...#.#..#####..#....###..#.##...###.#.#...######.#######.#...#.#..#
+		########.#.#.#...#.#.#..####......##...#####..#..#.##...#.#..#####..#....###..#.##...###.#.#...######.#######.#...#.#..#
+		########.#.#.#...#.#.#..####......##...#####..#..#.##...#.#..#####..#....###..#.##...###.#.#...######.#######.#...#.#..#
+		########.#.#.#...#.#.####..####...##..#..#.##......####.#.##..#####.##.##..#.....#...##.#.####..#####.#######.#...#.#..#
+		########.#.#.#...#.#.####..####...##..#..#.##......####.#.##..#####.##.##..#.....#...##.#.####..#####.#######.#...#.#..#
+		########.#.#.#...#.#.####..####...##..#..#.##......####.#.##..#####.##.##..#.....#...##.#.####..#####.#######.#...#.#..#
+		########.#.#.#...###.#.###....##..###..#.###...##..###...##..###..#.####..#...##.##..###.#.###..##....#######.#...#.#..#
+		########.#.#.#...###.#.###....##..###..#.###...##..###...##..###..#.####..#...##.##..###.#.###..##....#######.#...#.#..#
+		########.#.#.#...###.#.###....##..###..#.###...##..###...##..###..#.####..#...##.##..###.#.###..##....#######.#...#.#..#
+	`
+
+	testDecodeAll(t, SymbologyPDF417, symbol, 3, "PDF417", 0, 90, 180, 270, 15)
+}
@@ -0,0 +1,533 @@
+package ar8t
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/mrg0lden/ar8t/reedsolomon"
+)
+
+var errRMQRFormatCorrupted = errors.New("rMQR format information corrupted")
+
+// RMQRDecoder decodes rMQR Codes (Rectangular Micro QR Codes), R7x43 to R17x139.
+// QRData.Version is 1 to 32, in the order of rmqrVersions, and QRData.Modules is as wide
+// and as high as the symbol. QRData.Side is left 0, rMQR Codes not being square.
+type RMQRDecoder struct{}
+
+// RMQRFormatInfo is the format information of an rMQR Code
+type RMQRFormatInfo struct {
+	// Version is 1 to 32, from R7x43 to R17x139
+	Version uint32
+
+	// ECLevel is either ECLevelMedium or ECLevelHigh
+	ECLevel ECLevel
+
+	// Errors is the number of bits in error in the format information
+	Errors int
+}
+
+// the 18 bit format information is XORed with a different mask next to the finder
+// and next to the sub-finder
+const (
+	rmqrFormatInfoMaskFinder    = 0b011111101010110010
+	rmqrFormatInfoMaskSubFinder = 0b100000101001111011
+)
+
+type rmqrVersion struct {
+	height, width int
+
+	// alignment are the columns of the centres of the alignment patterns
+	alignment []int
+
+	// lengthBits are the lengths of the character count indicators of numeric,
+	// alphanumeric and byte mode
+	lengthBits [3]uint8
+
+	// blocks for ECLevelMedium and ECLevelHigh. They are yet to be checked against
+	// Table 8 of ISO/IEC 23941 or symbols of another encoder; Test_rmqrVersions only
+	// checks them against the codewords of the module layout.
+	blocks [2][]BlockInfo
+}
+
+// rmqrVersions are the 32 sizes of rMQR Codes, version 1 being rmqrVersions[0]
+var rmqrVersions = [32]rmqrVersion{
+	// R7x43
+	{7, 43, []int{21}, [...]uint8{4, 3, 3}, [2][]BlockInfo{
+		{{1, 13, 6, 3}},
+		{{1, 13, 3, 5}},
+	}},
+	// R7x59
+	{7, 59, []int{19, 39}, [...]uint8{5, 5, 4}, [2][]BlockInfo{
+		{{1, 21, 12, 4}},
+		{{1, 21, 7, 7}},
+	}},
+	// R7x77
+	{7, 77, []int{25, 51}, [...]uint8{6, 5, 5}, [2][]BlockInfo{
+		{{1, 32, 20, 6}},
+		{{1, 32, 10, 11}},
+	}},
+	// R7x99
+	{7, 99, []int{23, 49, 75}, [...]uint8{7, 6, 5}, [2][]BlockInfo{
+		{{1, 44, 28, 8}},
+		{{1, 44, 14, 15}},
+	}},
+	// R7x139
+	{7, 139, []int{27, 55, 83, 111}, [...]uint8{7, 6, 6}, [2][]BlockInfo{
+		{{1, 68, 44, 12}},
+		{{2, 34, 12, 11}},
+	}},
+	// R9x43
+	{9, 43, []int{21}, [...]uint8{5, 5, 4}, [2][]BlockInfo{
+		{{1, 21, 12, 4}},
+		{{1, 21, 7, 7}},
+	}},
+	// R9x59
+	{9, 59, []int{19, 39}, [...]uint8{6, 5, 5}, [2][]BlockInfo{
+		{{1, 33, 21, 6}},
+		{{1, 33, 11, 11}},
+	}},
+	// R9x77
+	{9, 77, []int{25, 51}, [...]uint8{7, 6, 5}, [2][]BlockInfo{
+		{{1, 49, 31, 9}},
+		{{1, 24, 8, 8}, {1, 25, 9, 8}},
+	}},
+	// R9x99
+	{9, 99, []int{23, 49, 75}, [...]uint8{7, 6, 6}, [2][]BlockInfo{
+		{{1, 66, 42, 12}},
+		{{2, 33, 11, 11}},
+	}},
+	// R9x139
+	{9, 139, []int{27, 55, 83, 111}, [...]uint8{8, 7, 6}, [2][]BlockInfo{
+		{{1, 49, 31, 9}, {1, 50, 32, 9}},
+		{{3, 33, 11, 11}},
+	}},
+	// R11x27
+	{11, 27, []int{}, [...]uint8{4, 4, 3}, [2][]BlockInfo{
+		{{1, 15, 7, 4}},
+		{{1, 15, 5, 5}},
+	}},
+	// R11x43
+	{11, 43, []int{21}, [...]uint8{6, 5, 5}, [2][]BlockInfo{
+		{{1, 31, 19, 6}},
+		{{1, 31, 11, 10}},
+	}},
+	// R11x59
+	{11, 59, []int{19, 39}, [...]uint8{7, 6, 5}, [2][]BlockInfo{
+		{{1, 47, 31, 8}},
+		{{1, 23, 7, 8}, {1, 24, 8, 8}},
+	}},
+	// R11x77
+	{11, 77, []int{25, 51}, [...]uint8{7, 6, 6}, [2][]BlockInfo{
+		{{1, 67, 43, 12}},
+		{{1, 33, 11, 11}, {1, 34, 12, 11}},
+	}},
+	// R11x99
+	{11, 99, []int{23, 49, 75}, [...]uint8{8, 7, 6}, [2][]BlockInfo{
+		{{1, 44, 28, 8}, {1, 45, 29, 8}},
+		{{1, 44, 14, 15}, {1, 45, 15, 15}},
+	}},
+	// R11x139
+	{11, 139, []int{27, 55, 83, 111}, [...]uint8{8, 7, 7}, [2][]BlockInfo{
+		{{2, 66, 42, 12}},
+		{{3, 44, 14, 15}},
+	}},
+	// R13x27
+	{13, 27, []int{}, [...]uint8{5, 5, 4}, [2][]BlockInfo{
+		{{1, 21, 12, 4}},
+		{{1, 21, 7, 7}},
+	}},
+	// R13x43
+	{13, 43, []int{21}, [...]uint8{6, 6, 5}, [2][]BlockInfo{
+		{{1, 41, 27, 7}},
+		{{1, 20, 6, 7}, {1, 21, 7, 7}},
+	}},
+	// R13x59
+	{13, 59, []int{19, 39}, [...]uint8{7, 6, 6}, [2][]BlockInfo{
+		{{1, 60, 38, 11}},
+		{{2, 30, 10, 10}},
+	}},
+	// R13x77
+	{13, 77, []int{25, 51}, [...]uint8{7, 7, 6}, [2][]BlockInfo{
+		{{1, 42, 26, 8}, {1, 43, 27, 8}},
+		{{1, 42, 14, 14}, {1, 43, 15, 14}},
+	}},
+	// R13x99
+	{13, 99, []int{23, 49, 75}, [...]uint8{8, 7, 7}, [2][]BlockInfo{
+		{{1, 56, 36, 10}, {1, 57, 37, 10}},
+		{{1, 37, 11, 13}, {2, 38, 12, 13}},
+	}},
+	// R13x139
+	{13, 139, []int{27, 55, 83, 111}, [...]uint8{8, 8, 7}, [2][]BlockInfo{
+		{{2, 55, 35, 10}, {1, 56, 36, 10}},
+		{{2, 41, 13, 14}, {2, 42, 14, 14}},
+	}},
+	// R15x43
+	{15, 43, []int{21}, [...]uint8{7, 6, 6}, [2][]BlockInfo{
+		{{1, 51, 33, 9}},
+		{{1, 25, 7, 9}, {1, 26, 8, 9}},
+	}},
+	// R15x59
+	{15, 59, []int{19, 39}, [...]uint8{7, 7, 6}, [2][]BlockInfo{
+		{{1, 74, 48, 13}},
+		{{2, 37, 13, 12}},
+	}},
+	// R15x77
+	{15, 77, []int{25, 51}, [...]uint8{8, 7, 7}, [2][]BlockInfo{
+		{{1, 51, 33, 9}, {1, 52, 34, 9}},
+		{{2, 34, 10, 12}, {1, 35, 11, 12}},
+	}},
+	// R15x99
+	{15, 99, []int{23, 49, 75}, [...]uint8{8, 7, 7}, [2][]BlockInfo{
+		{{2, 68, 44, 12}},
+		{{4, 34, 12, 11}},
+	}},
+	// R15x139
+	{15, 139, []int{27, 55, 83, 111}, [...]uint8{9, 8, 7}, [2][]BlockInfo{
+		{{2, 66, 42, 12}, {1, 67, 43, 12}},
+		{{1, 39, 13, 13}, {4, 40, 14, 13}},
+	}},
+	// R17x43
+	{17, 43, []int{21}, [...]uint8{7, 6, 6}, [2][]BlockInfo{
+		{{1, 61, 39, 11}},
+		{{1, 30, 10, 10}, {1, 31, 11, 10}},
+	}},
+	// R17x59
+	{17, 59, []int{19, 39}, [...]uint8{8, 7, 6}, [2][]BlockInfo{
+		{{2, 44, 28, 8}},
+		{{2, 44, 14, 15}},
+	}},
+	// R17x77
+	{17, 77, []int{25, 51}, [...]uint8{8, 7, 7}, [2][]BlockInfo{
+		{{2, 61, 41, 10}},
+		{{1, 40, 12, 14}, {2, 41, 13, 14}},
+	}},
+	// R17x99
+	{17, 99, []int{23, 49, 75}, [...]uint8{8, 8, 7}, [2][]BlockInfo{
+		{{2, 80, 52, 14}},
+		{{4, 40, 14, 13}},
+	}},
+	// R17x139
+	{17, 139, []int{27, 55, 83, 111}, [...]uint8{9, 8, 8}, [2][]BlockInfo{
+		{{4, 58, 38, 10}},
+		{{2, 38, 12, 13}, {4, 39, 13, 13}},
+	}},
+}
+
+// rmqrFormatCodewords are indexed by their 6 data bits: the error correction level and 5 bits of version
+var rmqrFormatCodewords = func() (codewords [64]uint32) {
+	for data := range codewords {
+		codewords[data] = versionBCH(uint32(data))
+	}
+
+	return
+}()
+
+// rmqrPatterns are the mask and data module order of every version, rMQR Codes have a single mask
+var rmqrPatterns [32]struct {
+	once      sync.Once
+	mask      BitMatrix
+	dataOrder []uint32
+}
+
+// rmqrModes are the segment readers of the 3 bit mode indicators,
+// with the index of their character count indicator length in rmqrVersion.lengthBits
+var rmqrModes = map[byte]struct {
+	read       segmentReader
+	lengthBits int
+}{
+	0b001: {numeric, 0},
+	0b010: {alphanumeric, 1},
+	0b011: {eightBit, 2},
+}
+
+func (RMQRDecoder) Decode(qrData QRData) ([]byte, error) {
+	format, err := DecodeRMQRFormat(qrData)
+	if err != nil {
+		return nil, err
+	}
+
+	if format.Version != qrData.Version {
+		return nil, errVersionMismatch
+	}
+
+	blocks, confidences, err := rmqrBlocks(qrData, format.ECLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	blockInfo := rmqrBlockInfo(format.Version, format.ECLevel)
+
+	allBlocks := []byte{}
+
+	for i, block := range blocks {
+		var confidence []byte
+		if qrData.Confidence != nil {
+			confidence = confidences[i]
+		}
+
+		corrected, _, err := correctSoft(block, blockInfo[i], confidence)
+		if err != nil {
+			return nil, err
+		}
+
+		// odd numbers of error correction codewords leave one to detect errors
+		symbols := make([]int, len(corrected))
+		for i, c := range corrected {
+			symbols[i] = int(c)
+		}
+		if _, valid := reedsolomon.QRCode.Syndromes(symbols, int(blockInfo[i].TotalPer-blockInfo[i].DataPer)); !valid {
+			return nil, reedsolomon.ErrTooManyErrors
+		}
+
+		allBlocks = append(allBlocks, corrected[:blockInfo[i].DataPer]...)
+	}
+
+	return RMQRData(allBlocks, format.Version)
+}
+
+// DecodeRMQRFormat reads both copies of the format information of an rMQR Code,
+// next to the finder and next to the sub-finder. The closest valid codeword within 3 bits
+// of either copy wins, the version is then checked against the size of the modules.
+func DecodeRMQRFormat(data QRData) (RMQRFormatInfo, error) {
+	width, height := data.Modules.Width(), data.Modules.Height()
+
+	var finderSide, subFinderSide uint32
+
+	for n := 0; n < 18; n++ {
+		var x, y, subX, subY int
+		if n < 15 {
+			x, y = 8+n/5, 1+n%5
+			subX, subY = width-8+n/5, height-6+n%5
+		} else {
+			x, y = 11, n-14
+			subX, subY = width-20+n, height-6
+		}
+
+		finderSide |= uint32(data.Modules.bit(x, y)) << n
+		subFinderSide |= uint32(data.Modules.bit(subX, subY)) << n
+	}
+
+	finderSide ^= rmqrFormatInfoMaskFinder
+	subFinderSide ^= rmqrFormatInfoMaskSubFinder
+
+	const maxErrors = 3
+
+	best, bestDist := -1, maxErrors+1
+	for i, codeword := range rmqrFormatCodewords {
+		for _, format := range [...]uint32{finderSide, subFinderSide} {
+			if dist := bits.OnesCount32(format ^ codeword); dist < bestDist {
+				best, bestDist = i, dist
+			}
+		}
+	}
+
+	if best == -1 {
+		return RMQRFormatInfo{}, errRMQRFormatCorrupted
+	}
+
+	format := RMQRFormatInfo{
+		Version: uint32(best&0b11111) + 1,
+		ECLevel: ECLevelMedium,
+		Errors:  bestDist,
+	}
+
+	if best>>5 == 1 {
+		format.ECLevel = ECLevelHigh
+	}
+
+	if v := rmqrVersions[format.Version-1]; v.width != width || v.height != height {
+		return RMQRFormatInfo{}, errVersionMismatch
+	}
+
+	return format, nil
+}
+
+// RMQRSize is the width and height in modules of an rMQR version
+func RMQRSize(version uint32) (width, height int, err error) {
+	if version < 1 || version > uint32(len(rmqrVersions)) {
+		return 0, 0, errUnsupportedVersion
+	}
+
+	v := rmqrVersions[version-1]
+	return v.width, v.height, nil
+}
+
+// rmqrBlockInfo expands the blocks of a version the way GetBlockInfo does
+func rmqrBlockInfo(version uint32, level ECLevel) []BlockInfo {
+	infoExpanded := []BlockInfo{}
+
+	blockInfo := rmqrVersions[version-1].blocks[0]
+	if level == ECLevelHigh {
+		blockInfo = rmqrVersions[version-1].blocks[1]
+	}
+
+	for _, bi := range blockInfo {
+		for i := byte(0); i < bi.BlockCount; i++ {
+			infoExpanded = append(infoExpanded, bi)
+		}
+	}
+
+	return infoExpanded
+}
+
+// rmqrBlocks reads the codewords of an rMQR Code into its blocks, with their confidence,
+// as blocksWithConfidence does for QR Codes
+func rmqrBlocks(data QRData, level ECLevel) ([][]byte, [][]byte, error) {
+	if level != ECLevelMedium && level != ECLevelHigh {
+		return nil, nil, errInvalidECLevel
+	}
+
+	width, height, err := RMQRSize(data.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if data.Modules.Width() != width || data.Modules.Height() != height {
+		return nil, nil, fmt.Errorf("expected %dx%d modules for R%dx%d but found %dx%d",
+			width, height, height, width, data.Modules.Width(), data.Modules.Height())
+	}
+
+	blockInfo := rmqrBlockInfo(data.Version, level)
+	mask, dataOrder := getRMQRPatterns(data.Version)
+
+	codewords := codewords{blocks: newBlocks(blockInfo), confidences: newBlocks(blockInfo)}
+
+	unmasked := data.Modules.Xor(mask)
+
+	total := 0
+	for _, info := range blockInfo {
+		total += int(info.TotalPer)
+	}
+
+	// the remainder bits after the last codeword are ignored
+	for _, index := range dataOrder[:total*8] {
+		x, y := int(index)%width, int(index)/width
+		codewords.addBit(unmasked.bit(x, y), data.confidence(index))
+	}
+
+	blocks := codewords.blocks.blocks
+
+	for i := range blocks {
+		if int(blockInfo[i].TotalPer) != len(blocks[i]) {
+			return nil, nil, fmt.Errorf("expected %d codewords in block %d but found %d",
+				blockInfo[i].TotalPer, i, len(blocks[i]))
+		}
+	}
+
+	return blocks, codewords.confidences.blocks, nil
+}
+
+// getRMQRPatterns computes the mask and data module order of an rMQR version once
+func getRMQRPatterns(version uint32) (BitMatrix, []uint32) {
+	p := &rmqrPatterns[version-1]
+	p.once.Do(func() {
+		v := rmqrVersions[version-1]
+		function := rmqrFunctionModules(v)
+
+		p.mask = NewBitMatrix(v.width, v.height)
+		for y := 0; y < v.height; y++ {
+			for x := 0; x < v.width; x++ {
+				if !function.Get(x, y) && maskPredicates[4](uint32(y), uint32(x)) {
+					p.mask.Set(x, y, true)
+				}
+			}
+		}
+
+		// two module wide columns from the bottom right, upwards and downwards in turn,
+		// the timing pattern on the left edge leaves no column to skip
+		upwards := true
+		for x := v.width - 2; x >= 1; x -= 2 {
+			for i := 0; i < v.height; i++ {
+				y := i
+				if upwards {
+					y = v.height - 1 - i
+				}
+
+				for _, x := range [...]int{x, x - 1} {
+					if !function.Get(x, y) {
+						p.dataOrder = append(p.dataOrder, uint32(y*v.width+x))
+					}
+				}
+			}
+
+			upwards = !upwards
+		}
+	})
+
+	return p.mask, p.dataOrder
+}
+
+// rmqrFunctionModules sets every module of a version that isn't data:
+// the finder and its separator, the sub-finder, the corner patterns, the alignment patterns and
+// the timing patterns between them, the timing patterns on the edges and the format information.
+func rmqrFunctionModules(v rmqrVersion) BitMatrix {
+	m := NewBitMatrix(v.width, v.height)
+
+	rect := func(x0, y0, x1, y1 int) {
+		for y := max(y0, 0); y <= min(y1, v.height-1); y++ {
+			for x := max(x0, 0); x <= min(x1, v.width-1); x++ {
+				m.Set(x, y, true)
+			}
+		}
+	}
+
+	// the edges
+	rect(0, 0, v.width-1, 0)
+	rect(0, v.height-1, v.width-1, v.height-1)
+	rect(0, 0, 0, v.height-1)
+	rect(v.width-1, 0, v.width-1, v.height-1)
+
+	// finder, separator and format information
+	rect(0, 0, 7, 7)
+	rect(8, 1, 10, 5)
+	rect(11, 1, 11, 3)
+
+	// corner pattern
+	rect(v.width-2, 0, v.width-1, 1)
+	if v.height >= 11 {
+		rect(0, v.height-2, 1, v.height-1)
+	}
+
+	// sub-finder and format information
+	rect(v.width-5, v.height-5, v.width-1, v.height-1)
+	rect(v.width-8, v.height-6, v.width-6, v.height-2)
+	rect(v.width-5, v.height-6, v.width-3, v.height-6)
+
+	for _, x := range v.alignment {
+		rect(x-1, 0, x+1, 2)
+		rect(x-1, v.height-3, x+1, v.height-1)
+		rect(x, 0, x, v.height-1)
+	}
+
+	return m
+}
+
+// RMQRData decodes the data codewords of an rMQR Code.
+// Mode indicators are 3 bits long and the terminator is 000.
+func RMQRData(input []byte, version uint32) ([]byte, error) {
+	if version < 1 || version > uint32(len(rmqrVersions)) {
+		return nil, errUnsupportedVersion
+	}
+
+	chomp := NewChomp(input)
+	result := bytes.Buffer{}
+
+	for mode, ok := chomp.Chomp(3); ok && mode != 0b000; mode, ok = chomp.Chomp(3) {
+		m, ok := rmqrModes[mode]
+		if !ok {
+			return nil, fmt.Errorf("mode %.3b not yet implemented", mode)
+		}
+
+		data, err := m.read(chomp, rmqrVersions[version-1].lengthBits[m.lengthBits])
+		if err != nil {
+			return nil, err
+		}
+
+		result.Write(data)
+	}
+
+	return result.Bytes(), nil
+}
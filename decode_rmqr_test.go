@@ -0,0 +1,128 @@
+package ar8t
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rmqrTests are rMQR Codes of a few sizes, the damaged ones drawn undamaged by Test_RMQRScan
+var rmqrTests = []decoderTest[RMQRFormatInfo]{
+	{
+		name: "R7x43 alphanumeric",
+		symbol: `
+			#######.#.#.#.#.#.#.###.#.#.#.#.#.#.#.#.###
+			#.....#..#.#####...##.##.#..##.#.#.##...#.#
+			#.###.#.#.######.#.######....#..##.########
+			#.###.#..##.#######...##...######.#...#...#
+			#.###.#...##.######.#####.#..###..##..#.#.#
+			#.....#.####..###...#.#.####.####..##.#...#
+			#######.#.#.#.#.#.#.###.#.#.#.#.#.#.#.#####
+		`,
+		format: RMQRFormatInfo{Version: 1, ECLevel: ECLevelMedium},
+		want:   "AR8T",
+	},
+	{
+		name: "R11x27 byte",
+		symbol: `
+			#######.#.#.#.#.#.#.#.#.###
+			#.....#..##....#.#....##..#
+			#.###.#....###.##...####..#
+			#.###.#.#####...###.#.#..#.
+			#.###.#..#...#####.####...#
+			#.....#.###.##.#...####..#.
+			#######...###.....#.#.#####
+			........##.#..#....#..#...#
+			##.#.....###.###..#####.#.#
+			#.##.#.#.#.##.#....##.#...#
+			###.#.#.#.#.#.#.#.#.#.#####
+		`,
+		format: RMQRFormatInfo{Version: 11, ECLevel: ECLevelHigh},
+		want:   "rmqr",
+	},
+	{
+		name: "R9x77 byte and numeric in two blocks",
+		symbol: `
+			#######.#.#.#.#.#.#.#.#.###.#.#.#.#.#.#.#.#.#.#.#.###.#.#.#.#.#.#.#.#.#.#.###
+			#.....#.####.##..#..##.##.####...#...#.###.#......#.#.#.##..#.#........#.##.#
+			#.###.#.#####.###...##..###..#...###.#.....#.#.#..#####..#####..##....#.#.#.#
+			#.###.#..#.#.#.##.##...#...###.#...#.#.#.#..#...##..##....#....#......##...#.
+			#.###.#..#..####..#####..#..###....#.#..#.#.#......#.###..#.#.#.####.#.######
+			#.....#.##.##.##.#.####.#.###..#....#.#####...##......#.#.##....#.##...##...#
+			#######.#.##.#....#.#..####.#.#.###.###.#...#########.#..#####.##....####.#.#
+			.........####.#..##..#..#.#....#...#..#..#.###..#.#.#.###.#.#.#.##.######...#
+			#.#.#.#.#.#.#.#.#.#.#.#.###.#.#.#.#.#.#.#.#.#.#.#.###.#.#.#.#.#.#.#.#.#.#####
+		`,
+		format:  RMQRFormatInfo{Version: 8, ECLevel: ECLevelHigh},
+		flipped: [][2]int{{30, 3}, {45, 5}, {60, 7}},
+		want:    "label2026",
+	},
+}
+
+func Test_RMQRDecoder(t *testing.T) {
+	testDecoder(t, rmqrTests, func(t *testing.T, modules BitMatrix, want RMQRFormatInfo) ([]byte, error) {
+		data := QRData{Modules: modules, Version: want.Version}
+
+		format, err := DecodeRMQRFormat(data)
+		if assert.NoError(t, err) {
+			assert.Equal(t, want, format)
+		}
+
+		return RMQRDecoder{}.Decode(data)
+	})
+}
+
+func Test_RMQRScan(t *testing.T) {
+	for _, tt := range rmqrTests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDecodeAll(t, SymbologyRMQR, tt.symbol, 6, tt.want, 0, 90, 180, 270, 30)
+		})
+	}
+}
+
+// Test_rmqrVersions checks the block tables against the module layout, which is derived
+// from the function patterns alone: the blocks of both levels hold every codeword
+// that fits in the data modules, as ISO/IEC 23941 lays them out.
+func Test_rmqrVersions(t *testing.T) {
+	for i, v := range rmqrVersions {
+		name := fmt.Sprintf("R%dx%d", v.height, v.width)
+		_, dataOrder := getRMQRPatterns(uint32(i + 1))
+		codewords := len(dataOrder) / 8
+
+		for level, blocks := range v.blocks {
+			total, data := 0, 0
+			ec := int(blocks[0].TotalPer - blocks[0].DataPer)
+
+			for j, block := range blocks {
+				total += int(block.BlockCount) * int(block.TotalPer)
+				data += int(block.BlockCount) * int(block.DataPer)
+
+				// every block has the same number of error correction codewords,
+				// the longer blocks coming last and a codeword longer
+				assert.Equal(t, ec, int(block.TotalPer-block.DataPer), "%s level %d", name, level)
+				assert.Equal(t, ec/2, int(block.EC_Cap), "%s level %d", name, level)
+				if j > 0 {
+					assert.Equal(t, blocks[j-1].TotalPer+1, block.TotalPer, "%s level %d", name, level)
+				}
+			}
+
+			assert.Equal(t, codewords, total, "%s level %d", name, level)
+
+			// the character count indicators are just long enough for as many digits
+			// as fit in the data codewords of level M
+			if level == 0 {
+				bits := data*8 - 3 - int(v.lengthBits[0])
+				digits := bits / 10 * 3
+				switch {
+				case bits%10 >= 7:
+					digits += 2
+				case bits%10 >= 4:
+					digits++
+				}
+				assert.Less(t, digits, 1<<v.lengthBits[0], name)
+				assert.GreaterOrEqual(t, digits, 1<<(v.lengthBits[0]-1), name)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ar8t
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	errVersionCorrupted = errors.New("version information corrupted")
+	errVersionMismatch  = errors.New("version information does not match the symbol size")
+)
+
+// versionCodewords are the 18 bit version information codewords of versions 7 to 40,
+// indexed by version
+var versionCodewords = func() (codewords [41]uint32) {
+	for version := 7; version <= 40; version++ {
+		codewords[version] = versionBCH(uint32(version))
+	}
+
+	return
+}()
+
+// versionBCH appends the 12 bit BCH(18, 6) remainder to 6 data bits
+func versionBCH(data uint32) uint32 {
+	const generator = 0b1111100100101
+
+	remainder := data << 12
+	for i := 17; i >= 12; i-- {
+		if remainder&(1<<i) != 0 {
+			remainder ^= generator << (i - 12)
+		}
+	}
+
+	return data<<12 | remainder
+}
+
+// DecodeVersion reads the version from the version information of data.
+// Versions 1 to 6 have none, their version is derived from the size of the symbol.
+func DecodeVersion(data QRData) (uint32, error) {
+	return decodeVersion(data.Modules)
+}
+
+// decodeVersion compares both copies of the version information to every valid codeword.
+// Among the codewords within 3 bits of either copy, the one closest to both copies combined wins.
+func decodeVersion(modules BitMatrix) (uint32, error) {
+	side := modules.width
+	if side < 17+4*7 {
+		if side < 21 || (side-17)%4 != 0 {
+			return 0, errUnsupportedVersion
+		}
+		return uint32(side-17) / 4, nil
+	}
+
+	var topRight, bottomLeft uint32
+
+	for y := 5; y >= 0; y-- {
+		for x := side - 9; x >= side-11; x-- {
+			topRight = topRight<<1 | uint32(modules.bit(x, y))
+		}
+	}
+
+	for x := 5; x >= 0; x-- {
+		for y := side - 9; y >= side-11; y-- {
+			bottomLeft = bottomLeft<<1 | uint32(modules.bit(x, y))
+		}
+	}
+
+	const maxErrors = 3
+
+	best, bestDist := 0, 0
+	for version := 7; version <= 40; version++ {
+		dist1 := bits.OnesCount32(topRight ^ versionCodewords[version])
+		dist2 := bits.OnesCount32(bottomLeft ^ versionCodewords[version])
+
+		if dist1 > maxErrors && dist2 > maxErrors {
+			continue
+		}
+
+		if best == 0 || dist1+dist2 < bestDist {
+			best, bestDist = version, dist1+dist2
+		}
+	}
+
+	if best == 0 {
+		return 0, errVersionCorrupted
+	}
+
+	return uint32(best), nil
+}
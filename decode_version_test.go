@@ -0,0 +1,16 @@
+package ar8t
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_versionCodewords(t *testing.T) {
+	// from ISO/IEC 18004 annex D
+	want := map[int]uint32{7: 0x07C94, 8: 0x085BC, 21: 0x15683, 40: 0x28C69}
+
+	for version, codeword := range want {
+		assert.Equal(t, codeword, versionCodewords[version], "version %d", version)
+	}
+}
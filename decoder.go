@@ -5,45 +5,372 @@ import (
 	"image"
 )
 
-type DefaultDecoder struct{}
+type DefaultDecoder struct {
+	// Scales enables multi-scale detection, see MultiScale.
+	// When empty, detection only runs at the native resolution.
+	// Modules are still extracted from the image prepared at the native resolution,
+	// and Observer and Debug are only told about the finders found there.
+	Scales []float64
+
+	// SoftSampling samples modules from the grayscale image, which lets
+	// error correction treat the least confident codewords as erasures.
+	// This reads blurry symbols much better, at some cost in speed.
+	SoftSampling bool
+
+	// Symbologies are the only symbologies looked for when set, and all of them when empty.
+	// The detectors of the others are skipped, which makes decoding faster.
+	Symbologies []Symbology
+
+	// Linear configures the scan for linear barcodes
+	Linear LinearScan
+
+	// Debug receives an image of every stage of decoding QR Codes when set, to tell which one fails.
+	// Most are drawn over a copy of the source image, see DebugStage.
+	Debug func(stage DebugStage, img image.Image)
+
+	// Observer is told about every stage of decoding when set, see Observer
+	Observer Observer
+}
 
 var ErrNoSymbolsFound = errors.New("no symbols found")
 
-func (DefaultDecoder) Decode(src image.Image) ([][]byte, error) {
-	prepared := NewBlockedMean(3, 7).Prepare(src)
-	locations := LineScan{}.Detect(prepared)
+// Symbology is the kind of symbol a result was decoded from
+type Symbology int
+
+const (
+	SymbologyQR Symbology = iota
+	SymbologyMicroQR
+	SymbologyRMQR
+	SymbologyDataMatrix
+	SymbologyAztec
+	SymbologyEAN13
+	SymbologyEAN8
+	SymbologyUPCA
+	SymbologyUPCE
+	SymbologyCode128
+	SymbologyCode39
+	SymbologyITF
+	SymbologyPDF417
+)
+
+// linearSymbologies are the symbologies read by LinearScan
+var linearSymbologies = []Symbology{
+	SymbologyEAN13, SymbologyEAN8, SymbologyUPCA, SymbologyUPCE,
+	SymbologyCode128, SymbologyCode39, SymbologyITF,
+}
+
+var symbologyNames = [...]string{
+	SymbologyQR:         "QR Code",
+	SymbologyMicroQR:    "Micro QR Code",
+	SymbologyRMQR:       "rMQR Code",
+	SymbologyDataMatrix: "Data Matrix",
+	SymbologyAztec:      "Aztec Code",
+	SymbologyEAN13:      "EAN-13",
+	SymbologyEAN8:       "EAN-8",
+	SymbologyUPCA:       "UPC-A",
+	SymbologyUPCE:       "UPC-E",
+	SymbologyCode128:    "Code 128",
+	SymbologyCode39:     "Code 39",
+	SymbologyITF:        "ITF",
+	SymbologyPDF417:     "PDF417",
+}
+
+func (s Symbology) String() string {
+	if s < 0 || int(s) >= len(symbologyNames) {
+		return "unknown"
+	}
+	return symbologyNames[s]
+}
+
+// wants tells whether any of symbologies is looked for, see DefaultDecoder.Symbologies
+func (d DefaultDecoder) wants(symbologies ...Symbology) bool {
+	if len(d.Symbologies) == 0 {
+		return true
+	}
+
+	for _, symbology := range symbologies {
+		for _, wanted := range d.Symbologies {
+			if symbology == wanted {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Result is the content of a decoded symbol
+type Result struct {
+	Symbology Symbology
+	Data      []byte
+
+	// Corners are the outer corners of the symbol clockwise from its top left, estimated from the finder
+	// patterns for QR and Micro QR Codes. For linear barcodes they are the ends of the first line read across it.
+	Corners []Point
+
+	// QR is what decoding a QR Code tells about it, nil for other symbologies
+	QR *QRInfo
+
+	// Err is why the symbol was found but couldn't be decoded, Data being nil. Only DecodeAll returns those.
+	Err error
+}
+
+// parallelogram is the corners of a symbol of columns x rows modules, from its top left corner
+// and the vectors from one module to the next along its rows and columns
+func parallelogram(topLeft, dx, dy Point, columns, rows float64) []Point {
+	return []Point{
+		topLeft,
+		topLeft.Add(dx.Mul(columns)),
+		topLeft.Add(dx.Mul(columns)).Add(dy.Mul(rows)),
+		topLeft.Add(dy.Mul(rows)),
+	}
+}
+
+// Decode decodes every symbol found in src, see DecodeResults
+func (d DefaultDecoder) Decode(src image.Image) ([][]byte, error) {
+	results, err := d.DecodeResults(src)
+	if err != nil {
+		return nil, err
+	}
+
+	allDecoded := make([][]byte, len(results))
+	for i, result := range results {
+		allDecoded[i] = result.Data
+	}
+
+	return allDecoded, nil
+}
+
+// DecodeResults decodes every symbol found in src, along with the symbology of each
+func (d DefaultDecoder) DecodeResults(src image.Image) ([]Result, error) {
+	all, err := d.DecodeAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []Result{}
+	for _, result := range all {
+		if result.Err == nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// DecodeAll is DecodeResults, along with the symbols found that couldn't be decoded and why
+func (d DefaultDecoder) DecodeAll(src image.Image) ([]Result, error) {
+	observer := observerOr(d.Observer)
+
+	preparer := NewBlockedMean(3, 7)
+	prepared := preparer.Prepare(src)
+	observer.Prepared(prepared)
+
+	var (
+		finders             []QRFinderPosition
+		locations           []QRLocation
+		microLocations      []MicroQRLocation
+		rmqrLocations       []RMQRLocation
+		dataMatrixLocations []DataMatrixLocation
+		aztecLocations      []AztecLocation
+		pdf417Locations     []PDF417Location
+		linearBarcodes      []LinearBarcode
+	)
+
+	// QR, Micro QR and rMQR Codes share the finder patterns found by a single scan
+	if d.wants(SymbologyQR, SymbologyMicroQR, SymbologyRMQR) {
+		finders = LineScan{}.finders(prepared)
+		for _, finder := range finders {
+			observer.FinderCandidate(finder)
+		}
+	}
+
+	switch {
+	case !d.wants(SymbologyQR):
+	case len(d.Scales) > 0:
+		locations = MultiScale{Scales: d.Scales, Preparer: preparer}.detect(src, prepared)
+	default:
+		locations = LineScan{}.group(finders)
+	}
+
+	if d.wants(SymbologyMicroQR) {
+		microLocations = MicroScan{}.locate(prepared, finders)
+	}
+	if d.wants(SymbologyRMQR) {
+		rmqrLocations = RMQRScan{}.locate(prepared, finders)
+	}
+	if d.wants(SymbologyDataMatrix) {
+		dataMatrixLocations = DataMatrixScan{}.Detect(prepared)
+	}
+	if d.wants(SymbologyAztec) {
+		aztecLocations = AztecScan{}.Detect(prepared)
+	}
+	if d.wants(SymbologyPDF417) {
+		pdf417Locations = PDF417Scan{}.Detect(prepared)
+	}
+	if d.wants(linearSymbologies...) {
+		for _, barcode := range d.Linear.Scan(prepared) {
+			if d.wants(barcode.Symbology) {
+				linearBarcodes = append(linearBarcodes, barcode)
+			}
+		}
+	}
 
-	if len(locations) == 0 {
+	if len(locations) == 0 && len(microLocations) == 0 && len(rmqrLocations) == 0 &&
+		len(dataMatrixLocations) == 0 && len(aztecLocations) == 0 && len(pdf417Locations) == 0 &&
+		len(linearBarcodes) == 0 {
+		if d.Debug != nil {
+			debugQR(d.Debug, src, prepared, finders, nil)
+		}
 		return nil, ErrNoSymbolsFound
 	}
 
-	allDecoded := [][]byte{}
-	// errs := []error{}
+	extractor := QRExtract{}
+	if d.SoftSampling {
+		extractor.Gray = Grayscale(src)
+	}
+
+	results := []Result{}
+	debugged := []qrDebug{}
 
 	for _, location := range locations {
-		extracted, err := QRExtract{}.Extract(prepared, location)
+		qrCorners := func() []Point {
+			// the centres of the finder patterns are 3.5 modules into the symbol
+			side := float64(17 + 4*location.Version)
+			dx := location.TopRight.Sub(location.TopLeft).Div(side - 7)
+			dy := location.BottomLeft.Sub(location.TopLeft).Div(side - 7)
+			return parallelogram(location.TopLeft.Sub(dx.Add(dy).Mul(3.5)), dx, dy, side, side)
+		}
+
+		observer.Location(location)
+		extracted, sampling, err := extractor.extract(prepared, location)
+
+		// the version estimated from the finder distances can be off for large symbols
+		if err == nil {
+			if version, versionErr := DecodeVersion(extracted); versionErr == nil && version != location.Version {
+				location.Version = version
+				extracted, sampling, err = extractor.extract(prepared, location)
+			}
+		}
+
+		observer.Extracted(extracted, err)
+
+		if d.Debug != nil {
+			debug := qrDebug{location: location, sampling: sampling}
+			if err == nil {
+				debug.modules = &extracted.Modules
+			}
+			debugged = append(debugged, debug)
+		}
+
 		if err != nil {
+			results = append(results, Result{Symbology: SymbologyQR, Corners: qrCorners(), Err: err})
 			continue
 		}
-		decoded, err := QRDecoder{}.Decode(extracted)
+
+		decoded, info, err := QRDecoder{Observer: observer}.DecodeWithInfo(extracted)
+		results = append(results, Result{Symbology: SymbologyQR, Data: decoded, Corners: qrCorners(), QR: &info, Err: err})
+	}
+
+	if d.Debug != nil {
+		debugQR(d.Debug, src, prepared, finders, debugged)
+	}
+
+	microExtractor := MicroQRExtract{Gray: extractor.Gray}
+
+	for _, location := range microLocations {
+		side := float64(9 + 2*location.Version)
+		corners := parallelogram(location.Finder.Sub(location.DX.Add(location.DY).Mul(3.5)), location.DX, location.DY, side, side)
+
+		extracted, err := microExtractor.Extract(prepared, location)
 		if err != nil {
+			results = append(results, Result{Symbology: SymbologyMicroQR, Corners: corners, Err: err})
 			continue
-			// errs = append(errs, err)
 		}
 
-		allDecoded = append(allDecoded, decoded)
+		decoded, err := MicroQRDecoder{}.Decode(extracted)
+		results = append(results, Result{Symbology: SymbologyMicroQR, Data: decoded, Corners: corners, Err: err})
+	}
+
+	rmqrExtractor := RMQRExtract{Gray: extractor.Gray}
+
+	for _, location := range rmqrLocations {
+		corners := []Point{location.TopLeft, location.TopRight, location.BottomRight, location.BottomLeft}
 
+		extracted, err := rmqrExtractor.Extract(prepared, location)
+		if err != nil {
+			results = append(results, Result{Symbology: SymbologyRMQR, Corners: corners, Err: err})
+			continue
+		}
+
+		decoded, err := RMQRDecoder{}.Decode(extracted)
+		results = append(results, Result{Symbology: SymbologyRMQR, Data: decoded, Corners: corners, Err: err})
 	}
 
-	// for debug mode
+	dataMatrixExtractor := DataMatrixExtract{Gray: extractor.Gray}
 
-	// errStr := strings.Builder{}
+	for _, location := range dataMatrixLocations {
+		corners := []Point{location.TopLeft, location.TopRight, location.BottomRight, location.BottomLeft}
 
-	// for _, err := range errs {
-	// 	errStr.WriteString("err: ")
-	// 	errStr.WriteString(err.Error())
-	// 	errStr.WriteByte('\n')
-	// }
+		extracted, err := dataMatrixExtractor.Extract(prepared, location)
+		if err != nil {
+			results = append(results, Result{Symbology: SymbologyDataMatrix, Corners: corners, Err: err})
+			continue
+		}
 
-	return allDecoded, nil
+		decoded, err := DataMatrixDecoder{}.Decode(extracted)
+		results = append(results, Result{Symbology: SymbologyDataMatrix, Data: decoded, Corners: corners, Err: err})
+	}
+
+	aztecExtractor := AztecExtract{Gray: extractor.Gray}
+
+	for _, location := range aztecLocations {
+		corners := []Point{location.TopLeft, location.TopRight, location.BottomRight, location.BottomLeft}
+
+		extracted, err := aztecExtractor.Extract(prepared, location)
+		if err != nil {
+			results = append(results, Result{Symbology: SymbologyAztec, Corners: corners, Err: err})
+			continue
+		}
+
+		decoded, err := AztecDecoder{}.Decode(extracted)
+		results = append(results, Result{Symbology: SymbologyAztec, Data: decoded, Corners: corners, Err: err})
+	}
+
+	// the rows of PDF417 symbols are made of bars, and can be read as linear barcodes
+	pdf417Areas := [][]Point{}
+
+	for _, location := range pdf417Locations {
+		corners := []Point{location.TopLeft, location.TopRight, location.BottomRight, location.BottomLeft}
+
+		extracted, err := PDF417Extract{}.Extract(prepared, location)
+		if err != nil {
+			results = append(results, Result{Symbology: SymbologyPDF417, Corners: corners, Err: err})
+			continue
+		}
+
+		decoded, err := PDF417Decoder{}.Decode(extracted)
+		results = append(results, Result{Symbology: SymbologyPDF417, Data: decoded, Corners: corners, Err: err})
+		if err == nil {
+			pdf417Areas = append(pdf417Areas, corners)
+		}
+	}
+
+	// linear barcodes are decoded as they are read
+linear:
+	for _, barcode := range linearBarcodes {
+		for _, area := range pdf417Areas {
+			if insideHull(area, barcode.Start.Add(barcode.End).Div(2)) {
+				continue linear
+			}
+		}
+
+		results = append(results, Result{Symbology: barcode.Symbology, Data: barcode.Data, Corners: []Point{barcode.Start, barcode.End}})
+	}
+
+	for _, result := range results {
+		observer.Result(result)
+	}
+
+	return results, nil
 }
@@ -0,0 +1,132 @@
+package ar8t
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"testing"
+
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DefaultDecoder_Symbologies(t *testing.T) {
+	const ean8 = "#.#...#.##.#.####.####.#.##.###.#.#.#..###.###..#.#...#..#.###..#.#"
+
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// a page with a QR Code on the top left, an EAN-13 barcode below it and an EAN-8 one on the right
+	page := image.NewGray(image.Rect(0, 0, 1000, 700))
+	for i := range page.Pix {
+		page.Pix[i] = 255
+	}
+	draw.Draw(page, image.Rect(20, 20, 1000, 700), symbols[0].Render(6), image.Point{}, draw.Src)
+	draw.Draw(page, image.Rect(20, 400, 1000, 700), linearImage(streamEAN13, false), image.Point{}, draw.Src)
+	draw.Draw(page, image.Rect(800, 300, 1000, 700), linearImage(ean8, true), image.Point{}, draw.Src)
+
+	tests := []struct {
+		name        string
+		symbologies []Symbology
+		want        map[Symbology]string
+		wantErr     error
+	}{
+		{
+			name: "all",
+			want: map[Symbology]string{
+				SymbologyQR:    symbols[0].Text,
+				SymbologyEAN13: "4006381333931",
+				SymbologyEAN8:  "96385074",
+			},
+		},
+		{
+			name:        "QR Code",
+			symbologies: []Symbology{SymbologyQR},
+			want:        map[Symbology]string{SymbologyQR: symbols[0].Text},
+		},
+		{
+			name:        "EAN-8 and Aztec Code",
+			symbologies: []Symbology{SymbologyEAN8, SymbologyAztec},
+			want:        map[Symbology]string{SymbologyEAN8: "96385074"},
+		},
+		{
+			name:        "none on the page",
+			symbologies: []Symbology{SymbologyDataMatrix, SymbologyCode39},
+			wantErr:     ErrNoSymbolsFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := DefaultDecoder{Symbologies: tt.symbologies}.DecodeAll(page)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got := map[Symbology]string{}
+			for _, result := range results {
+				assert.NoError(t, result.Err, result.Symbology.String())
+				got[result.Symbology] = string(result.Data)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// decoderTest is a symbol drawn with # for its dark modules, decoded once the modules in flipped
+// are flipped. format is what else is expected to be read from it, such as its format information.
+type decoderTest[F any] struct {
+	name    string
+	symbol  string
+	format  F
+	flipped [][2]int
+	want    string
+}
+
+// testDecoder parses, damages and decodes the symbol of every test with decode,
+// which checks what else it reads against the format of the test
+func testDecoder[F any](t *testing.T, tests []decoderTest[F], decode func(t *testing.T, modules BitMatrix, format F) ([]byte, error)) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := ParseBitMatrix(tt.symbol)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, module := range tt.flipped {
+				modules.Flip(module[0], module[1])
+			}
+
+			got, err := decode(t, modules, tt.format)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+// testDecodeAll draws symbol with rotatedImage at every one of angles and checks that DefaultDecoder
+// decodes want from it, and nothing else
+func testDecodeAll(t *testing.T, symbology Symbology, symbol string, moduleSize float64, want string, angles ...float64) {
+	modules, err := ParseBitMatrix(symbol)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, angle := range angles {
+		t.Run(fmt.Sprintf("rotated %v", angle), func(t *testing.T) {
+			results, err := DefaultDecoder{}.DecodeResults(rotatedImage(modules, moduleSize, angle))
+			if assert.NoError(t, err) && assert.Len(t, results, 1) {
+				assert.Equal(t, symbology, results[0].Symbology)
+				assert.Equal(t, want, string(results[0].Data))
+			}
+		})
+	}
+}
@@ -13,7 +13,8 @@ type Detector interface {
 ///
 /// The general idea of this method is as follows:
 /// 1. Scan line by line horizontally for possible QR Finder patterns (the three squares)
-/// 2. If a possible pattern is found, check vertically and diagonally to confirm it is indeed a pattern
+/// 2. If a possible pattern is found, check vertically, horizontally and along both diagonals to confirm it is indeed a pattern,
+///    then find its centre and module size from its outline, which rotated patterns need
 /// 3. Try to find combinations of three patterns that are perpendicular and with similar distance that form a complete QR Code
 var _ Detector = LineScan{}
 
@@ -27,18 +28,57 @@ type QRFinderPosition struct {
 type refineFunc = func(*image.Gray, Point, float64) (QRFinderPosition, bool)
 
 func (s LineScan) Detect(prepared *image.Gray) []QRLocation {
-	// The order of refinement is important.
-	// The candidate is found in horizontal direction, so the first refinement is vertical
-	refineFuncs := []struct {
-		refineFunc
-		dx, dy     float64
-		isDiagonal bool
-	}{
-		{refineVertical, 0, 1, false},
-		{refineHorizontal, 1, 0, false},
-		{refineDiagonal, 1, 1, true},
+	return s.group(s.finders(prepared))
+}
+
+// group finds every combination of three finder patterns that forms a QR Code
+func (s LineScan) group(candidates []QRFinderPosition) []QRLocation {
+	locations := []QRLocation{}
+
+	maxCandidates := len(candidates)
+
+	for candidate1 := 0; candidate1 < maxCandidates; candidate1++ {
+		for candidate2 := candidate1 + 1; candidate2 < maxCandidates; candidate2++ {
+			diff1 := diff(
+				candidates[candidate1].ModuleSize,
+				candidates[candidate2].ModuleSize,
+			)
+
+			if diff1 > 0.1 {
+				continue
+			}
+
+			for candidate3 := candidate2 + 1; candidate3 < maxCandidates; candidate3++ {
+				diff2 := diff(
+					candidates[candidate1].ModuleSize,
+					candidates[candidate3].ModuleSize,
+				)
+
+				if diff2 > 0.1 {
+					continue
+				}
+
+				if loc, ok := findQR(
+					[...]Point{
+						candidates[candidate1].Location,
+						candidates[candidate2].Location,
+						candidates[candidate3].Location,
+					},
+					candidates[candidate1].ModuleSize,
+				); ok {
+					locations = append(locations, loc)
+				}
+			}
+
+		}
 	}
 
+	return locations
+}
+
+// finders scans prepared line by line for finder patterns, confirmed vertically, horizontally
+// and along both diagonals
+func (s LineScan) finders(prepared *image.Gray) []QRFinderPosition {
 	candidates := []QRFinderPosition{}
 	lastPixel := uint8(127)
 	pattern := QRFinderPattern{}
@@ -74,95 +114,101 @@ func (s LineScan) Detect(prepared *image.Gray) []QRLocation {
 				Y: float64(y),
 			}
 
+			// the rows crossing a finder are all within half a finder of its centre,
+			// other finders are further away, though not always a whole finder away from where it is crossed
 			for _, candidate := range candidates {
-				if distance(finder, candidate.Location) < 7*moduleSize {
+				if distance(finder, candidate.Location) < 3.5*moduleSize {
 					lastPixel = p
 					pattern.Slide()
 					continue pixels
 				}
 			}
 
-			for _, refineFunc := range refineFuncs {
-				vert, ok := refineFunc.refineFunc(prepared, finder, moduleSize)
-
-				if !ok {
-					lastPixel = p
-					pattern.Slide()
-
-					continue pixels
-				}
-
-				if !refineFunc.isDiagonal {
-					halfFinder := vert.LastModuleSize * 3.5
-					finder.X = vert.Location.X - refineFunc.dx*halfFinder
-					finder.Y = vert.Location.Y - refineFunc.dy*halfFinder
-					moduleSize = vert.ModuleSize
-				}
+			if candidate, ok := confirmFinder(prepared, finder, moduleSize); ok && !isFound(candidates, candidate) {
+				candidates = append(candidates, candidate)
 			}
 
-			candidates = append(candidates, QRFinderPosition{
-				Location:       finder,
-				ModuleSize:     moduleSize,
-				LastModuleSize: 0,
-			})
-
 			lastPixel = p
 			pattern.Slide()
 
 		}
 	}
 
-	locations := []QRLocation{}
+	return candidates
+}
 
-	maxCandidates := len(candidates)
+// isFound tells whether a finder pattern is one of the candidates, centred within half a finder of it
+func isFound(candidates []QRFinderPosition, finder QRFinderPosition) bool {
+	for _, candidate := range candidates {
+		if distance(finder.Location, candidate.Location) < 3.5*finder.ModuleSize {
+			return true
+		}
+	}
+	return false
+}
 
-	for candidate1 := 0; candidate1 < maxCandidates; candidate1++ {
-		for candidate2 := candidate1 + 1; candidate2 < maxCandidates; candidate2++ {
-			diff1 := diff(
-				candidates[candidate1].ModuleSize,
-				candidates[candidate2].ModuleSize,
-			)
+// confirmFinder confirms a finder pattern found along a row, vertically, horizontally and along
+// both diagonals. Rows and columns cross a rotated finder at an angle, making it look wider than it is,
+// so its centre and module size come from its outline.
+func confirmFinder(prepared *image.Gray, finder Point, moduleSize float64) (QRFinderPosition, bool) {
+	// The order of refinement is important.
+	// The candidate is found in horizontal direction, so the first refinement is vertical
+	refineFuncs := []struct {
+		refineFunc
+		dx, dy float64
+	}{
+		{refineVertical, 0, 1},
+		{refineHorizontal, 1, 0},
+	}
 
-			if diff1 > 0.1 {
-				continue
-			}
+	for _, refineFunc := range refineFuncs {
+		refined, ok := refineFunc.refineFunc(prepared, finder, moduleSize)
+		if !ok {
+			return QRFinderPosition{}, false
+		}
 
-			for candidate3 := candidate2 + 1; candidate3 < maxCandidates; candidate3++ {
-				diff2 := diff(
-					candidates[candidate1].ModuleSize,
-					candidates[candidate3].ModuleSize,
-				)
+		halfFinder := refined.LastModuleSize * 3.5
+		finder.X = refined.Location.X - refineFunc.dx*halfFinder
+		finder.Y = refined.Location.Y - refineFunc.dy*halfFinder
+		moduleSize = refined.ModuleSize
+	}
 
-				if diff2 > 0.1 {
-					continue
-				}
+	diagonal, ok := refineDiagonal(prepared, finder, moduleSize)
+	if !ok {
+		return QRFinderPosition{}, false
+	}
 
-				if loc, ok := findQR(
-					[...]Point{
-						candidates[candidate1].Location,
-						candidates[candidate2].Location,
-						candidates[candidate3].Location,
-					},
-					candidates[candidate1].ModuleSize,
-				); ok {
-					locations = append(locations, loc)
-				}
-			}
+	antiDiagonal, ok := refineAntiDiagonal(prepared, finder, moduleSize)
+	if !ok {
+		return QRFinderPosition{}, false
+	}
 
-		}
+	// the diagonals cross a finder between 1/sqrt(2) and sqrt(2) times as wide as the rows do,
+	// taking from as many steps as the rows take pixels to half as many
+	diagonals := (diagonal.LastModuleSize + antiDiagonal.LastModuleSize) / 2
+	if ratio := moduleSize / diagonals; ratio < 0.8 || ratio > 2.4 {
+		return QRFinderPosition{}, false
 	}
 
-	return locations
+	// the middles of the rows and the columns crossing a rotated finder aren't in line with its centre
+	centre, _, shapeModuleSize, ok := finderShape(prepared, finder, moduleSize)
+	if !ok || distance(centre, finder) > moduleSize {
+		return QRFinderPosition{}, false
+	}
 
+	return QRFinderPosition{
+		Location:   centre,
+		ModuleSize: shapeModuleSize,
+	}, true
 }
 
 func refineHorizontal(prepared *image.Gray, finder Point, moduleSize float64) (QRFinderPosition, bool) {
 	startX := refineCalcStart(finder.X, moduleSize)
 	endX := refineCalcEnd(finder.X, uint32(prepared.Rect.Dx()), moduleSize)
 
-	y := uint32(math.Round(finder.Y))
+	y := int(math.Round(finder.Y))
 
-	return refine(prepared, moduleSize, startX, endX, y, y, false)
+	return refine(prepared, moduleSize, int(startX), y, 1, 0, int(endX)-int(startX), false)
 
 }
 
@@ -170,109 +216,80 @@ func refineVertical(prepared *image.Gray, finder Point, moduleSize float64) (QRF
 	startY := refineCalcStart(finder.Y, moduleSize)
 	endY := refineCalcEnd(finder.Y, uint32(prepared.Rect.Dy()), moduleSize)
 
-	x := uint32(math.Round(finder.X))
+	x := int(math.Round(finder.X))
 
-	return refine(prepared, moduleSize, x, x, startY, endY, false)
+	return refine(prepared, moduleSize, x, int(startY), 0, 1, int(endY)-int(startY), false)
 }
 
+// refineDiagonal goes through the finder down to the right
 func refineDiagonal(prepared *image.Gray, finder Point, moduleSize float64) (QRFinderPosition, bool) {
-	side := 5 * moduleSize
-	var startX, startY float64
-
-	switch {
-	case finder.X < side && finder.Y < side:
-		if finder.X < finder.Y {
-			startY = finder.Y - finder.X
-			break
-		}
-		startX = finder.X - finder.Y
-	case finder.X < side:
-		startY = finder.Y - finder.X
-	case finder.Y < side:
-		startX = finder.X - finder.Y
-	default:
-		startX = finder.X - side
-		startY = finder.Y - side
-	}
+	return refineAcross(prepared, finder, moduleSize, 1)
+}
 
-	rangeEndCalc := func(v, d float64) uint32 {
-		return uint32(math.Round(min(v+side, d)))
-	}
+// refineAntiDiagonal goes through the finder up to the right
+func refineAntiDiagonal(prepared *image.Gray, finder Point, moduleSize float64) (QRFinderPosition, bool) {
+	return refineAcross(prepared, finder, moduleSize, -1)
+}
 
-	xMin, xMax := uint32(math.Round(startX)),
-		rangeEndCalc(finder.X, float64(prepared.Rect.Dx()))
+// refineAcross goes through the finder diagonally from 5 modules before it to 5 modules after it,
+// or to the edges of prepared
+func refineAcross(prepared *image.Gray, finder Point, moduleSize float64, dy int) (QRFinderPosition, bool) {
+	side := int(math.Round(5 * moduleSize))
+	x, y := int(math.Round(finder.X)), int(math.Round(finder.Y))
 
-	yMin, yMax := uint32(math.Round(startY)),
-		rangeEndCalc(finder.Y, float64(prepared.Rect.Dy()))
+	before, after := y, prepared.Rect.Dy()-1-y
+	if dy < 0 {
+		before, after = after, before
+	}
 
-	return refine(prepared, moduleSize, xMin, xMax, yMin, yMax, true)
+	back := min(side, min(x, before))
+	forward := min(side, min(prepared.Rect.Dx()-1-x, after))
 
+	return refine(prepared, moduleSize, x-back, y-dy*back, 1, dy, back+forward+1, true)
 }
 
-func refine(prepared *image.Gray, moduleSize float64, xMin, xMax, yMin, yMax uint32, isDiagonal bool) (QRFinderPosition, bool) {
+// refine looks for a finder pattern along n pixels from x, y, going by dx, dy.
+// The module size found diagonally isn't checked, as it depends on the rotation.
+func refine(prepared *image.Gray, moduleSize float64, x, y, dx, dy, n int, isDiagonal bool) (QRFinderPosition, bool) {
 	var (
-		lastPixel    uint8 = 127
-		pattern            = QRFinderPattern{}
-		lastX, lastY uint32
+		lastPixel uint8 = 127
+		pattern         = QRFinderPattern{}
 	)
 
-	x, y := xMin, yMin
-
-	for {
-
-		p := prepared.GrayAt(int(x), int(y)).Y
-
-		switch {
-		case p == lastPixel:
-			pattern[6]++
-		case pattern.LooksLikeFinder() &&
-			(diff(moduleSize, pattern.EstimateModuleSize()) < 0.2 || isDiagonal):
-			newEstModSize := (moduleSize + pattern.EstimateModuleSize()) / 2
-			return QRFinderPosition{
-				Location: Point{
-					X: float64(x),
-					Y: float64(y),
-				},
-				ModuleSize:     newEstModSize,
-				LastModuleSize: pattern.EstimateModuleSize(),
-			}, true
-		default:
-			lastPixel = p
-			pattern.Slide()
-		}
-
-		lastX, lastY = x, y
-
-		switch {
-		case isDiagonal && x < xMax-1 && y < yMax-1:
-			x++
-			y++
-			continue
-		case !isDiagonal && xMin == xMax && y < yMax-1:
-			y++
-			continue
-		case !isDiagonal && yMin == yMax && x < xMax-1:
-			x++
-			continue
+	found := func(x, y int) (QRFinderPosition, bool) {
+		if !pattern.LooksLikeFinder() ||
+			diff(moduleSize, pattern.EstimateModuleSize()) >= 0.2 && !isDiagonal {
+			return QRFinderPosition{}, false
 		}
 
-		break
-	}
-
-	if pattern.LooksLikeFinder() &&
-		(diff(moduleSize, pattern.EstimateModuleSize()) < 0.2 || isDiagonal) {
 		newEstModSize := (moduleSize + pattern.EstimateModuleSize()) / 2
 		return QRFinderPosition{
 			Location: Point{
-				X: float64(lastX),
-				Y: float64(lastY),
+				X: float64(x),
+				Y: float64(y),
 			},
 			ModuleSize:     newEstModSize,
 			LastModuleSize: pattern.EstimateModuleSize(),
 		}, true
 	}
 
-	return QRFinderPosition{}, false
+	for i := 0; i < max(n, 1); i++ {
+		p := prepared.GrayAt(x, y).Y
+
+		if p == lastPixel {
+			pattern[6]++
+		} else if position, ok := found(x, y); ok {
+			return position, true
+		} else {
+			lastPixel = p
+			pattern.Slide()
+		}
+
+		x, y = x+dx, y+dy
+	}
+
+	// the pattern can end at the end of the line
+	return found(x-dx, y-dy)
 }
 
 type QRFinderPattern [7]uint
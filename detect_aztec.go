@@ -0,0 +1,499 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+)
+
+// AztecLocation is where an Aztec Code is in an image
+type AztecLocation struct {
+	// the outer corners of the symbol, upright as its orientation marks put it
+	TopLeft, TopRight, BottomRight, BottomLeft Point
+
+	ModuleSize float64 //in pixels
+	Compact    bool
+	Layers     int //1 .. 4 for compact symbols, 1 .. 32 for full-range ones
+}
+
+// AztecScan scans a prepared image for Aztec Codes
+//
+// The general idea of this method is as follows:
+// 1. Scan the rows of the image for bullseyes, nine runs as long as each other through their centre
+// 2. The light ring 3 modules from the centre is enclosed by dark rings, the outer edge of its area
+// is a square 7 modules wide, which maps modules around the bullseye into the image
+// 3. Full-range symbols have two more rings, their light ring 5 modules from the centre is used instead
+// 4. The orientation marks in the corners of the ring of the mode message tell which side is up,
+// the mode message gives the size of the symbol
+type AztecScan struct{}
+
+func (loc AztecLocation) center() Point {
+	return middle([...]Point{loc.TopLeft, loc.TopRight, loc.BottomRight, loc.BottomLeft})
+}
+
+// bullseye is a candidate centre of an Aztec Code bullseye
+type bullseye struct {
+	center Point
+
+	// ring is in the light ring 3 modules from the centre, along the row the bullseye was found on
+	ring Point
+
+	// pitch is the length of the runs along that row
+	pitch float64
+}
+
+func (AztecScan) Detect(prepared *image.Gray) []AztecLocation {
+	var (
+		locations = []AztecLocation{}
+
+		// scratch space for filling the rings of a bullseye, every fill unmarks its pixels
+		visited = make([]bool, prepared.Rect.Dx()*prepared.Rect.Dy())
+	)
+
+	// whether a symbol was already found around p, rows next to each other find the same bullseye
+	found := func(p Point) bool {
+		for _, loc := range locations {
+			if distance(p, loc.center()) < 3*loc.ModuleSize {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, candidate := range bullseyes(prepared) {
+		if found(candidate.center) {
+			continue
+		}
+
+		if loc, ok := findAztec(prepared, candidate, visited); ok && !found(loc.center()) {
+			locations = append(locations, loc)
+		}
+	}
+
+	return locations
+}
+
+// bullseyes scans the rows of prepared for nine runs of about the same length starting with a dark one,
+// then checks that the column through the middle of the fifth run crosses the same rings.
+// The outermost dark ring runs into the dark modules next to it, it is only as long or longer.
+func bullseyes(prepared *image.Gray) []bullseye {
+	candidates := []bullseye{}
+	rect := prepared.Rect
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		// the starts of the runs of the row, and where the last one ends
+		starts := []int{rect.Min.X}
+		for x := rect.Min.X + 1; x < rect.Max.X; x++ {
+			if prepared.GrayAt(x, y).Y != prepared.GrayAt(x-1, y).Y {
+				starts = append(starts, x)
+			}
+		}
+		starts = append(starts, rect.Max.X)
+
+		for i := 0; i+9 < len(starts); i++ {
+			if prepared.GrayAt(starts[i], y).Y != 0 {
+				continue
+			}
+
+			pitch := float64(starts[i+8]-starts[i+1]) / 7
+			if !evenRuns(starts[i:i+10], pitch) {
+				continue
+			}
+
+			center := Point{float64(starts[i+4]+starts[i+5]-1) / 2, float64(y)}
+
+			center, ok := bullseyeColumn(prepared, center, pitch)
+			if !ok {
+				continue
+			}
+
+			candidates = append(candidates, bullseye{
+				center: center,
+				ring:   Point{float64(starts[i+7]+starts[i+8]-1) / 2, float64(y)},
+				pitch:  pitch,
+			})
+		}
+	}
+
+	return candidates
+}
+
+// evenRuns reports whether the runs between starts are all about pitch long,
+// the first and the last ones at least as long
+func evenRuns(starts []int, pitch float64) bool {
+	for j := 1; j < len(starts); j++ {
+		run := float64(starts[j] - starts[j-1])
+		if run < pitch/2 || (run > pitch*3/2 && j != 1 && j != len(starts)-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// bullseyeColumn checks the runs of the column through center, and moves center
+// to the middle of the dark run it is in
+func bullseyeColumn(prepared *image.Gray, center Point, pitch float64) (Point, bool) {
+	x, y := int(center.X), int(center.Y)
+	limit := int(pitch*3/2) + 1
+
+	// the ends of the four runs above and below the one center is in,
+	// then of as much of the outermost dark ring as there needs to be
+	run := func(dir int) ([]int, bool) {
+		ends := []int{}
+		for v, n := y, 0; len(ends) < 5; v += dir {
+			if !(image.Point{x, v + dir}.In(prepared.Rect)) {
+				return nil, false
+			}
+
+			n++
+			switch {
+			case len(ends) == 4 && float64(n) >= pitch/2:
+				ends = append(ends, v+dir)
+			case n > limit:
+				return nil, false
+			case prepared.GrayAt(x, v).Y != prepared.GrayAt(x, v+dir).Y:
+				ends, n = append(ends, v+dir), 0
+			}
+		}
+		return ends, true
+	}
+
+	up, ok := run(-1)
+	if !ok {
+		return Point{}, false
+	}
+
+	down, ok := run(1)
+	if !ok {
+		return Point{}, false
+	}
+
+	starts := []int{}
+	for i := len(up) - 1; i >= 0; i-- {
+		starts = append(starts, up[i]+1)
+	}
+	starts = append(starts, down...)
+
+	if !evenRuns(starts, pitch) {
+		return Point{}, false
+	}
+
+	return Point{center.X, float64(up[0]+down[0]) / 2}, true
+}
+
+// findAztec fits a square to the rings of a bullseye, reads which side is up from the orientation
+// marks then the size of the symbol from the mode message
+func findAztec(prepared *image.Gray, candidate bullseye, visited []bool) (AztecLocation, bool) {
+	// the light ring 3 modules from the centre is the outermost one of compact bullseyes
+	t, moduleSize, ok := bullseyeSquare(prepared, candidate.ring, visited, 3, candidate.pitch)
+	if !ok {
+		return AztecLocation{}, false
+	}
+
+	compact := true
+	if bullseyeRings(prepared, t, 5, 6) {
+		compact = false
+
+		if t, moduleSize, ok = bullseyeSquare(prepared, t.Apply(Point{5, 0}), visited, 5, moduleSize); !ok {
+			return AztecLocation{}, false
+		}
+	}
+
+	// quarter turns of the modules around the bullseye, until the orientation marks match
+	turn := -1
+	for q := 0; q < 4 && turn == -1; q++ {
+		matching := 0
+		for _, mark := range aztecOrientation(compact) {
+			if isDark(prepared, t.Apply(quarterTurns(Point{float64(mark.x), float64(mark.y)}, q))) == mark.dark {
+				matching++
+			}
+		}
+
+		if matching >= 10 {
+			turn = q
+		}
+	}
+
+	if turn == -1 {
+		return AztecLocation{}, false
+	}
+
+	bits := []bool{}
+	for _, module := range aztecModeMessageModules(compact) {
+		bits = append(bits, isDark(prepared, t.Apply(quarterTurns(Point{float64(module[0]), float64(module[1])}, turn))))
+	}
+
+	mode, err := aztecModeMessage(bits, compact)
+	if err != nil {
+		return AztecLocation{}, false
+	}
+
+	size, err := AztecSize(compact, mode.Layers)
+	if err != nil {
+		return AztecLocation{}, false
+	}
+
+	if !compact {
+		t = referenceGrid(prepared, t, size)
+	}
+
+	// the outer corners of the corner modules
+	half := float64(size) / 2
+	corner := func(x, y float64) Point {
+		return t.Apply(quarterTurns(Point{x, y}, turn))
+	}
+
+	return AztecLocation{
+		TopLeft:     corner(-half, -half),
+		TopRight:    corner(half, -half),
+		BottomRight: corner(half, half),
+		BottomLeft:  corner(-half, half),
+		ModuleSize:  moduleSize,
+		Compact:     compact,
+		Layers:      mode.Layers,
+	}, true
+}
+
+// bullseyeSquare fits squares to the light ring r modules from the centre of a bullseye,
+// filled from the pixel at start, and to the dark ring inside it. Thresholding moves
+// the edges between dark and light modules the same way on both, the middle of their outer
+// edges is r modules from the centre. It returns the map from modules, the centre module
+// being at 0, 0, into the image, and the size of a module.
+func bullseyeSquare(prepared *image.Gray, start Point, visited []bool, r, pitch float64) (transform, float64, bool) {
+	square := func(half float64) [4]Point {
+		return [...]Point{{-half, -half}, {half, -half}, {half, half}, {-half, half}}
+	}
+
+	light, ok := areaSquare(prepared, start, visited, r+0.5, pitch)
+	if !ok {
+		return transform{}, 0, false
+	}
+
+	dark, ok := areaSquare(prepared, newTransform(square(r+0.5), light).Apply(Point{r - 1, 0}), visited, r-0.5, pitch)
+	if !ok {
+		return transform{}, 0, false
+	}
+
+	var (
+		corners    [4]Point
+		moduleSize float64
+	)
+
+	for i, c := range light {
+		nearest := dark[0]
+		for _, d := range dark {
+			if distance(c, d) < distance(c, nearest) {
+				nearest = d
+			}
+		}
+
+		corners[i] = c.Add(nearest).Div(2)
+	}
+
+	// the bullseye is too small to tell perspective from the errors of its edges,
+	// the corners are kept as far from its centre as they are but made a parallelogram
+	center := middle(corners)
+	for i := 0; i < 2; i++ {
+		diagonal := corners[i+2].Sub(corners[i]).Div(2)
+		corners[i], corners[i+2] = center.Sub(diagonal), center.Add(diagonal)
+	}
+
+	for i, c := range corners {
+		moduleSize += distance(c, corners[(i+1)%4]) / (8 * r)
+	}
+
+	// both squares are around the same centre, with every ring of the bullseye inside them
+	t := newTransform(square(r), corners)
+	if distance(middle(light), middle(dark)) > moduleSize/2 || !bullseyeRings(prepared, t, 0, int(r)) {
+		return transform{}, 0, false
+	}
+
+	return t, moduleSize, true
+}
+
+// areaSquare fills the area of the pixel at start, a ring of a bullseye half modules
+// wide on each side of its centre, and fits a square to the outer edge of the area.
+// The corners go round the square clockwise as seen in the image.
+func areaSquare(prepared *image.Gray, start Point, visited []bool, half, pitch float64) ([4]Point, bool) {
+	// rows are up to √2 modules long across the ring, as long as pitch when it is rotated by 45°
+	side := 2 * half * pitch
+	limit := int(side * side)
+
+	i, ok := pixelIndex(prepared, start)
+	if !ok {
+		return [4]Point{}, false
+	}
+
+	a, ok := fillArea(prepared, i, visited, limit)
+	a.unmark(visited, prepared.Rect.Dx())
+	if !ok {
+		return [4]Point{}, false
+	}
+
+	sides := hullSides(a.hull(prepared.Rect), 0.3)
+
+	longest := 0.0
+	for _, side := range sides {
+		longest = math.Max(longest, side.length())
+	}
+
+	square := []hullSide{}
+	for _, side := range sides {
+		if side.length() > longest/2 {
+			square = append(square, side)
+		}
+	}
+
+	if len(square) != 4 || longest < side/2 || longest > side*3/2 {
+		return [4]Point{}, false
+	}
+
+	// the hull goes round the corners of pixels, those of pixels along a slanted edge
+	// reach further out than half a pixel
+	inside := middle([...]Point{square[0].from, square[1].from, square[2].from, square[3].from})
+	lines := [4]line{}
+	for i, side := range square {
+		d := side.dir()
+		normal := Point{-d.Y, d.X}
+		if projection(normal, inside.Sub(side.from)) < 0 {
+			normal = normal.Mul(-1)
+		}
+
+		lines[i] = line{side.from.Add(normal.Mul((math.Abs(d.X)+math.Abs(d.Y))/2 - 0.5)), d}
+	}
+
+	var corners [4]Point
+	for i := range lines {
+		corner, ok := lines[i].intersect(lines[(i+1)%4])
+		if !ok {
+			return [4]Point{}, false
+		}
+		corners[(i+1)%4] = corner
+	}
+
+	da, db := corners[1].Sub(corners[0]), corners[2].Sub(corners[1])
+	if da.X*db.Y-da.Y*db.X < 0 {
+		corners[1], corners[3] = corners[3], corners[1]
+	}
+
+	return corners, true
+}
+
+// middle is the mean of the corners of a quadrilateral
+func middle(corners [4]Point) Point {
+	var m Point
+	for _, c := range corners {
+		m = m.Add(c.Div(4))
+	}
+	return m
+}
+
+// pixelIndex is the index of the pixel nearest to p, x + y*width from the corner of the image
+func pixelIndex(prepared *image.Gray, p Point) (int, bool) {
+	x, y := int(math.Round(p.X)), int(math.Round(p.Y))
+	if !(image.Point{x, y}.In(prepared.Rect)) {
+		return 0, false
+	}
+
+	return (y-prepared.Rect.Min.Y)*prepared.Rect.Dx() + x - prepared.Rect.Min.X, true
+}
+
+// bullseyeRings reports whether the rings from and to modules from the centre of a bullseye
+// are dark for even distances and light for odd ones, allowing for a few wrong modules
+func bullseyeRings(prepared *image.Gray, t transform, from, to int) bool {
+	for r := from; r <= to; r++ {
+		modules := ringModules(r)
+		if r == 0 {
+			modules = [][2]int{{0, 0}}
+		}
+
+		wrong := 0
+		for _, m := range modules {
+			if isDark(prepared, t.Apply(Point{float64(m[0]), float64(m[1])})) != (r%2 == 0) {
+				wrong++
+			}
+		}
+
+		if wrong > len(modules)/10 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// quarterTurns turns p around the centre of the bullseye by n quarter turns clockwise
+func quarterTurns(p Point, n int) Point {
+	for i := 0; i < n; i++ {
+		p = Point{-p.Y, p.X}
+	}
+	return p
+}
+
+// referenceGrid refines t, fitted to the bullseye of a full-range symbol, outwards along its
+// reference grid. The lines of the grid are every 16 modules from the centre, dark and light
+// modules taking turns along them. Where the lines cross around each ring of the grid,
+// the one at each corner is searched for around where t puts it.
+func referenceGrid(prepared *image.Gray, t transform, size int) transform {
+	type module struct {
+		p    Point
+		dark bool
+	}
+
+	edge := float64(size-1) / 2
+	corners := [...]Point{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}}
+
+	for k := 16; k < size/2; k += 16 {
+		var from, to [4]Point
+
+		for i, c := range corners {
+			from[i] = c.Mul(float64(k))
+
+			// the modules of the lines crossing there, 4 modules each way as far as the symbol goes
+			cross := []module{}
+			for d := -4; d <= 4; d++ {
+				for _, p := range []Point{{float64(d), 0}, {0, float64(d)}} {
+					p = from[i].Add(p)
+					if (d != 0 || p.X == from[i].X) && math.Abs(p.X) <= edge && math.Abs(p.Y) <= edge {
+						cross = append(cross, module{p, d%2 == 0})
+					}
+				}
+			}
+
+			// the offsets, in steps of a quarter of a module, where most of the modules match
+			best, found := 0, []Point{}
+			for oy := -6; oy <= 6; oy++ {
+				for ox := -6; ox <= 6; ox++ {
+					o := Point{float64(ox) / 4, float64(oy) / 4}
+
+					matching := 0
+					for _, m := range cross {
+						if isDark(prepared, t.Apply(m.p.Add(o))) == m.dark {
+							matching++
+						}
+					}
+
+					switch {
+					case matching > best:
+						best, found = matching, []Point{o}
+					case matching == best:
+						found = append(found, o)
+					}
+				}
+			}
+
+			if best < len(cross)-1 {
+				return t
+			}
+
+			var o Point
+			for _, f := range found {
+				o = o.Add(f.Div(float64(len(found))))
+			}
+			to[i] = t.Apply(from[i].Add(o))
+		}
+
+		t = newTransform(from, to)
+	}
+
+	return t
+}
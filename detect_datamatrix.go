@@ -0,0 +1,436 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// DataMatrixLocation is where a Data Matrix symbol is in an image
+type DataMatrixLocation struct {
+	// the outer corners of the symbol, the L shaped finder running along the left and bottom edges
+	// and the clock tracks along the top and right ones
+	TopLeft, TopRight, BottomRight, BottomLeft Point
+
+	ModuleSize float64 //in pixels
+	Version    uint32  //1 .. 30, see DataMatrixDecoder
+}
+
+// DataMatrixScan scans a prepared image for Data Matrix ECC200 symbols
+//
+// The general idea of this method is as follows:
+// 1. Find the connected dark areas of the image, the L shaped finder of a symbol being one of them
+// 2. Merge the edges of the convex hull of every area into sides, the legs of the L being two of them
+// 3. Two sides meeting at a corner, solid for the width of a module, are the finder
+// 4. Follow the clock tracks from the ends of the L, their alternating modules give the size of the symbol
+type DataMatrixScan struct{}
+
+func (DataMatrixScan) Detect(prepared *image.Gray) []DataMatrixLocation {
+	locations := []DataMatrixLocation{}
+
+	for _, hull := range darkAreas(prepared) {
+		if loc, ok := findDataMatrix(prepared, hull); ok {
+			locations = append(locations, loc)
+		}
+	}
+
+	return locations
+}
+
+// darkAreas labels the 4-connected dark areas of prepared, and returns the convex hull
+// of every one that could hold a finder, from the outer corners of its pixels
+func darkAreas(prepared *image.Gray) [][]Point {
+	const minSide, minPixels = 12, 48
+
+	var (
+		rect    = prepared.Rect
+		visited = make([]bool, rect.Dx()*rect.Dy())
+		hulls   = [][]Point{}
+	)
+
+	for start := range visited {
+		if visited[start] || prepared.Pix[(start/rect.Dx())*prepared.Stride+start%rect.Dx()] != 0 {
+			continue
+		}
+
+		a, _ := fillArea(prepared, start, visited, len(visited))
+		if a.pixels < minPixels || len(a.rows) < minSide || a.width() < minSide {
+			continue
+		}
+
+		hulls = append(hulls, a.hull(rect))
+	}
+
+	return hulls
+}
+
+// area is a 4-connected area of pixels of the same colour
+type area struct {
+	// the leftmost and rightmost pixels of every row of the area
+	rows   map[int][2]int
+	pixels int
+}
+
+// fillArea labels the area of the pixel at index start, x + y*width from the corner of the image,
+// marking its pixels as visited. It gives up once the area is larger than limit pixels,
+// returning what it labelled so far.
+func fillArea(prepared *image.Gray, start int, visited []bool, limit int) (area, bool) {
+	var (
+		width = prepared.Rect.Dx()
+		a     = area{rows: map[int][2]int{}}
+		stack = []int{start}
+	)
+
+	color := func(i int) uint8 {
+		return prepared.Pix[(i/width)*prepared.Stride+i%width]
+	}
+	c := color(start)
+
+	visited[start] = true
+
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if a.pixels++; a.pixels > limit {
+			return a, false
+		}
+
+		x, y := i%width, i/width
+		if r, ok := a.rows[y]; ok {
+			a.rows[y] = [2]int{min(r[0], x), max(r[1], x)}
+		} else {
+			a.rows[y] = [2]int{x, x}
+		}
+
+		for _, n := range [...]int{i - 1, i + 1, i - width, i + width} {
+			switch {
+			case n < 0 || n >= len(visited) || visited[n]:
+			case (n == i-1 && x == 0) || (n == i+1 && x == width-1):
+			case color(n) == c:
+				visited[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	return a, true
+}
+
+// unmark clears the pixels of every row of the area from visited, from its leftmost to its rightmost pixel
+func (a area) unmark(visited []bool, width int) {
+	for y, r := range a.rows {
+		for x := r[0]; x <= r[1]; x++ {
+			visited[y*width+x] = false
+		}
+	}
+}
+
+// width is the number of columns the area spans
+func (a area) width() int {
+	left, right := math.MaxInt, math.MinInt
+	for _, r := range a.rows {
+		left, right = min(left, r[0]), max(right, r[1])
+	}
+	return right - left + 1
+}
+
+// hull is the convex hull of the outer corners of the pixels of the area, in the image rect
+func (a area) hull(rect image.Rectangle) []Point {
+	points := make([]Point, 0, len(a.rows)*4)
+	for y, r := range a.rows {
+		top, bottom := float64(y+rect.Min.Y)-0.5, float64(y+rect.Min.Y)+0.5
+		l, r := float64(r[0]+rect.Min.X)-0.5, float64(r[1]+rect.Min.X)+0.5
+		points = append(points, Point{l, top}, Point{l, bottom}, Point{r, top}, Point{r, bottom})
+	}
+
+	return convexHull(points)
+}
+
+// convexHull is the convex hull of points by the monotone chain algorithm
+func convexHull(points []Point) []Point {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].X != points[j].X {
+			return points[i].X < points[j].X
+		}
+		return points[i].Y < points[j].Y
+	})
+
+	cross := func(o, a, b Point) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+
+	hull := make([]Point, 0, len(points)+1)
+
+	// the lower chain, then the upper one
+	for _, p := range points {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], points[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, points[i])
+	}
+
+	return hull[:len(hull)-1]
+}
+
+// hullSide is a straight side of a convex hull, from one corner to the next
+type hullSide struct {
+	from, to Point
+}
+
+func (s hullSide) length() float64 {
+	return distance(s.from, s.to)
+}
+
+func (s hullSide) dir() Point {
+	return s.to.Sub(s.from).Div(s.length())
+}
+
+// hullSides merges the edges of a convex hull into sides, every edge of a side turning
+// less than maxTurn radians away from the side so far. Corners rounded by a few edges
+// are short sides of their own.
+func hullSides(hull []Point, maxTurn float64) []hullSide {
+	n := len(hull)
+	edge := func(i int) hullSide {
+		return hullSide{hull[(i%n+n)%n], hull[((i+1)%n+n)%n]}
+	}
+
+	turn := func(a, b Point) float64 {
+		return math.Acos(math.Max(-1, math.Min(1, projection(a, b))))
+	}
+
+	// starting from the sharpest turn, a corner of the hull
+	start := 0
+	for i := range hull {
+		if turn(edge(i-1).dir(), edge(i).dir()) > turn(edge(start-1).dir(), edge(start).dir()) {
+			start = i
+		}
+	}
+
+	sides := []hullSide{edge(start)}
+	for i := start + 1; i < start+n; i++ {
+		e, last := edge(i), &sides[len(sides)-1]
+		if turn(last.dir(), e.dir()) < maxTurn {
+			last.to = e.to
+		} else {
+			sides = append(sides, e)
+		}
+	}
+
+	return sides
+}
+
+// findDataMatrix looks for the finder of a Data Matrix symbol among the corners of a hull,
+// then follows the clock tracks from the ends of its legs
+func findDataMatrix(prepared *image.Gray, hull []Point) (DataMatrixLocation, bool) {
+	var centroid Point
+	for _, p := range hull {
+		centroid = centroid.Add(p.Div(float64(len(hull))))
+	}
+
+	const minLeg = 10
+
+	sides := hullSides(hull, 0.3)
+	n := len(sides)
+
+	for i, a := range sides {
+		if a.length() < minLeg {
+			continue
+		}
+
+		// the next long side, past the short ones of a rounded corner
+		j, rounded := (i+1)%n, 0.0
+		for ; sides[j].length() < minLeg && j != i; j = (j + 1) % n {
+			rounded += sides[j].length()
+		}
+		b := sides[j]
+
+		if j == i || rounded > math.Min(a.length(), b.length())/4 {
+			continue
+		}
+
+		if cos := projection(a.dir(), b.dir()); math.Abs(cos) > 0.7 {
+			continue
+		}
+
+		corner, ok := line{a.from, a.dir()}.intersect(line{b.from, b.dir()})
+		if !ok {
+			continue
+		}
+
+		top, right := a.from, b.to
+
+		// as seen in the image, the left leg is a quarter turn anticlockwise from the bottom one
+		da, db := top.Sub(corner), right.Sub(corner)
+		if da.X*db.Y-da.Y*db.X < 0 {
+			top, right = right, top
+		}
+
+		if loc, ok := dataMatrixFromFinder(prepared, corner, top, right, centroid); ok {
+			return loc, true
+		}
+	}
+
+	return DataMatrixLocation{}, false
+}
+
+// dataMatrixFromFinder checks that the legs of an L, from corner to the ends top and right,
+// are solid lines a module wide, then fits their outer edges and follows the clock tracks
+// along the top and right edges
+func dataMatrixFromFinder(prepared *image.Gray, corner, top, right, centroid Point) (DataMatrixLocation, bool) {
+	// the width of a leg, the shortest dark run inwards from its outer edge
+	legWidth := func(from, to Point) (float64, Point, bool) {
+		d := to.Sub(from).Div(distance(from, to))
+		in := Point{-d.Y, d.X}
+		if projection(centroid.Sub(from), in) < 0 {
+			in = in.Mul(-1)
+		}
+
+		// the edge of the hull is up to a pixel away from the leg, dark modules next to it
+		// make some runs longer
+		runs := []float64{}
+		for t := 0.15; t < 0.86; t += 0.05 {
+			p := from.Add(to.Sub(from).Mul(t))
+			for i := 0; i < 3 && !isDark(prepared, p); i++ {
+				p = p.Add(in.Mul(0.5))
+			}
+
+			run := 0.0
+			for ; isDark(prepared, p.Add(in.Mul(run))) && run < distance(from, to)/4; run += 0.5 {
+			}
+			runs = append(runs, run)
+		}
+
+		// the shortest run that isn't a sliver at the edge of the leg
+		width := math.Inf(1)
+		for _, run := range runs {
+			if run >= 1.5 {
+				width = math.Min(width, run)
+			}
+		}
+
+		return width, in, width >= 1.5 && width < distance(from, to)/6
+	}
+
+	leftWidth, leftIn, ok := legWidth(corner, top)
+	if !ok {
+		return DataMatrixLocation{}, false
+	}
+
+	bottomWidth, bottomIn, ok := legWidth(corner, right)
+	if !ok || diff(leftWidth, bottomWidth) > 0.5 {
+		return DataMatrixLocation{}, false
+	}
+
+	moduleSize := (leftWidth + bottomWidth) / 2
+
+	// the outer edges of the legs, fitted a second time from the first fit
+	// as the sides of the hull can be a pixel or two away from the legs at their ends
+	edge := func(from, to, in Point) (line, bool) {
+		fit := line{from, to.Sub(from).Div(distance(from, to))}
+		for pass := 0; pass < 2; pass++ {
+			starts := []Point{}
+			for t := 0.05; t < 0.95; t += 0.5 * moduleSize / distance(from, to) {
+				p := from.Add(to.Sub(from).Mul(t))
+				p = fit.p.Add(fit.d.Mul(projection(p.Sub(fit.p), fit.d)))
+				starts = append(starts, p.Add(in.Mul(moduleSize/2)))
+			}
+
+			var ok bool
+			if fit, ok = fitEdge(prepared, starts, in.Mul(-moduleSize), 5); !ok {
+				return line{}, false
+			}
+		}
+		return fit, true
+	}
+
+	leftEdge, ok := edge(corner, top, leftIn)
+	if !ok {
+		return DataMatrixLocation{}, false
+	}
+
+	bottomEdge, ok := edge(corner, right, bottomIn)
+	if !ok {
+		return DataMatrixLocation{}, false
+	}
+
+	bottomLeft, ok := leftEdge.intersect(bottomEdge)
+	if !ok {
+		return DataMatrixLocation{}, false
+	}
+
+	// the ends of the legs, on their outer edges
+	topLeft := leftEdge.p.Add(leftEdge.d.Mul(projection(top.Sub(leftEdge.p), leftEdge.d)))
+	bottomRight := bottomEdge.p.Add(bottomEdge.d.Mul(projection(right.Sub(bottomEdge.p), bottomEdge.d)))
+
+	// both legs are solid, through the middle of their modules
+	solid := func(from, to, in Point) bool {
+		dark, total := 0, 0
+		for t := 0.03; t < 0.97; t += 1 / distance(from, to) {
+			total++
+			if isDark(prepared, from.Add(to.Sub(from).Mul(t)).Add(in.Mul(moduleSize/2))) {
+				dark++
+			}
+		}
+		return float64(dark) >= 0.9*float64(total)
+	}
+
+	if !solid(bottomLeft, topLeft, leftIn) || !solid(bottomLeft, bottomRight, bottomIn) {
+		return DataMatrixLocation{}, false
+	}
+
+	var (
+		dx = bottomRight.Sub(bottomLeft).Div(distance(bottomLeft, bottomRight)).Mul(moduleSize)
+		dy = bottomLeft.Sub(topLeft).Div(distance(topLeft, bottomLeft)).Mul(moduleSize)
+	)
+
+	// the top clock track starts with the dark corner module, the one along the right edge
+	// with a light module and ends in the bottom leg of the L
+	topClock, ok := followTiming(prepared, topLeft, line{topLeft, dx.Div(moduleSize)}, dx, dy, 1)
+	if !ok || len(topClock.dark) == 0 {
+		return DataMatrixLocation{}, false
+	}
+
+	// the last dark module of a clock track can run into the next module of the other track,
+	// its start is where the clock track is known to be
+	topClock = topClock.until(topClock.dark[len(topClock.dark)-1])
+	width := topClock.modules + 2
+	columns := topClock.positions(width)
+	topRight := topClock.origin.Add(topClock.dir.Mul(columns[width]))
+
+	rightClock, ok := followTiming(prepared, topRight, line{topRight, dy.Div(moduleSize)}, dy, dx.Mul(-1), 0)
+	if !ok || len(rightClock.dark) == 0 {
+		return DataMatrixLocation{}, false
+	}
+
+	rightClock = rightClock.until(rightClock.dark[len(rightClock.dark)-1])
+	height := rightClock.modules + 1
+	rows := rightClock.positions(height)
+
+	version, err := dataMatrixVersion(width, height)
+	if err != nil {
+		return DataMatrixLocation{}, false
+	}
+
+	// the legs of the L are as long as the clock tracks
+	if diff(distance(topLeft, bottomLeft)/float64(height), rows[height]/float64(height)) > 0.2 {
+		return DataMatrixLocation{}, false
+	}
+
+	return DataMatrixLocation{
+		TopLeft:     topLeft,
+		TopRight:    topRight,
+		BottomRight: rightClock.origin.Add(rightClock.dir.Mul(rows[height])),
+		BottomLeft:  bottomLeft,
+		ModuleSize:  (columns[width]/float64(width) + rows[height]/float64(height)) / 2,
+		Version:     version,
+	}, true
+}
@@ -0,0 +1,315 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+)
+
+// LinearScan scans a prepared image for linear barcodes: EAN-13, EAN-8, UPC-A, UPC-E,
+// Code 128, Code 39 and ITF.
+//
+// The general idea of this method is as follows:
+// 1. Measure the runs of dark and light pixels along every row, column and diagonal, the same way
+// LineScan measures the runs of finder patterns
+// 2. Read the runs both ways from every dark run after a wide enough light one, matching the
+// widths of the runs to the patterns of each symbology once they are scaled to the same width
+// 3. Keep the barcodes read along more than one line, which rules out most of the runs
+// of other symbols and text that happen to match a pattern, and drop those read inside
+// the area of a barcode read along more lines
+type LinearScan struct {
+	// Code39CheckDigit requires Code 39 barcodes to end with a check character,
+	// which is left out of their content
+	Code39CheckDigit bool
+
+	// ITFCheckDigit requires ITF barcodes to end with a check digit, as ITF-14 ones do
+	ITFCheckDigit bool
+}
+
+// LinearBarcode is a linear barcode read along the lines of an image
+type LinearBarcode struct {
+	Symbology Symbology
+	Data      []byte
+
+	// where the first line it was read along crosses its first and last bars
+	Start, End Point
+
+	// Lines is the number of lines it was read along
+	Lines int
+}
+
+// the fewest lines a barcode has to be read along
+const minLinearLines = 2
+
+// minLines is the fewest lines barcode has to be read along. ITF barcodes have no check character
+// and runs of other symbols too often look like them, so their bars have to be at least a tenth
+// as long as the barcode, which about as many lines cross.
+func minLines(barcode LinearBarcode) int {
+	if barcode.Symbology == SymbologyITF {
+		return max(minLinearLines, int(distance(barcode.Start, barcode.End)/10))
+	}
+	return minLinearLines
+}
+
+func (s LinearScan) Scan(prepared *image.Gray) []LinearBarcode {
+	var (
+		readers  = []linearReader{readEAN, readCode128, s.readCode39, s.readITF}
+		barcodes = []LinearBarcode{}
+
+		// the index in barcodes of each symbology and content
+		found = map[string]int{}
+
+		// where the lines each barcode was read along cross its first and last bars
+		ends = [][]Point{}
+	)
+
+	line := func(origin, step image.Point, n int) {
+		at := func(i int) image.Point {
+			return origin.Add(step.Mul(i))
+		}
+
+		runs, edges := lineRuns(prepared, origin, step, n)
+
+		// a barcode read both ways along the same line counts once
+		read := map[string]bool{}
+
+		for _, reversed := range []bool{false, true} {
+			if reversed {
+				runs, edges = reverseRuns(runs, edges, n)
+			}
+
+			for i := 1; i < len(runs); i += 2 {
+				for _, reader := range readers {
+					symbology, data, end, ok := reader(runs, i)
+					if !ok {
+						continue
+					}
+
+					// the pixels of the first and last bars along the line, and the way into the barcode
+					first, last, into := edges[i], edges[end]-1, step
+					if reversed {
+						first, last, into = n-1-first, n-1-last, image.Point{-step.X, -step.Y}
+					}
+					start, stop := at(first), at(last)
+
+					if !barSide(prepared, start, into) || !barSide(prepared, stop, image.Point{-into.X, -into.Y}) {
+						continue
+					}
+
+					key := symbology.String() + "\x00" + string(data)
+					if !read[key] {
+						read[key] = true
+
+						j, ok := found[key]
+						if !ok {
+							j = len(barcodes)
+							found[key] = j
+							barcodes = append(barcodes, LinearBarcode{
+								Symbology: symbology,
+								Data:      data,
+								Start:     pointOf(start),
+								End:       pointOf(stop),
+							})
+							ends = append(ends, nil)
+						}
+
+						barcodes[j].Lines++
+						ends[j] = append(ends[j], pointOf(start), pointOf(stop))
+					}
+
+					// the light run after the barcode may be before another one
+					i = end - 1
+					break
+				}
+			}
+		}
+	}
+
+	scanLines(prepared.Rect, line)
+
+	hulls := make([][]Point, len(ends))
+	for i := range ends {
+		hulls[i] = convexHull(ends[i])
+	}
+
+	read := []LinearBarcode{}
+	for _, barcode := range barcodes {
+		if barcode.Lines >= minLines(barcode) && !misread(barcode, barcodes, hulls) {
+			read = append(read, barcode)
+		}
+	}
+
+	return read
+}
+
+// misread reports whether barcode was read inside the area of another barcode read along more lines.
+// Lines crossing a barcode slantwise, or leaving it through the ends of its bars, can read it
+// as a shorter barcode or as one of another symbology.
+func misread(barcode LinearBarcode, barcodes []LinearBarcode, hulls [][]Point) bool {
+	middle := barcode.Start.Add(barcode.End).Div(2)
+	for i, other := range barcodes {
+		if other.Lines > barcode.Lines && insideHull(hulls[i], middle) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insideHull reports whether p is inside a convex hull, going round it either way
+func insideHull(hull []Point, p Point) bool {
+	if len(hull) < 3 {
+		return false
+	}
+
+	left, right := false, false
+	for i := range hull {
+		a, b := hull[i], hull[(i+1)%len(hull)]
+		cross := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+		left, right = left || cross > 0, right || cross < 0
+	}
+
+	return !(left && right)
+}
+
+// barSide reports whether a line going along step into the bar at p crosses the side of the bar:
+// along the lines next to it the edge of the bar is straight, and within 35° of square to the line.
+// Lines entering or leaving a barcode through the ends of its bars cross the ends of the bars
+// instead, along the barcode, and the lines past the ends don't cross the bar at all.
+func barSide(prepared *image.Gray, p, step image.Point) bool {
+	dark := func(q image.Point) bool {
+		return q.In(prepared.Rect) && prepared.GrayAt(q.X, q.Y).Y == 0
+	}
+
+	// the light to dark edges nearest to p along the lines up to 4 steps away on each side
+	const (
+		lines  = 4
+		search = 2 * lines
+	)
+
+	normal := image.Point{-step.Y, step.X}
+	edges := []Point{}
+
+	for k := -lines; k <= lines; k++ {
+		q := p.Add(normal.Mul(k))
+
+		found := false
+		for t := 0; t <= search && !found; t++ {
+			for _, u := range [...]int{t, -t} {
+				if dark(q.Add(step.Mul(u))) && !dark(q.Add(step.Mul(u-1))) {
+					edges = append(edges, Point{float64(k), float64(u)})
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	// the edge as steps along the lines for each line, fitted by least squares
+	var sumK, sumT, sumKK, sumKT float64
+	for _, e := range edges {
+		sumK, sumT, sumKK, sumKT = sumK+e.X, sumT+e.Y, sumKK+e.X*e.X, sumKT+e.X*e.Y
+	}
+
+	n := float64(len(edges))
+	slope := (n*sumKT - sumK*sumT) / (n*sumKK - sumK*sumK)
+	offset := (sumT - slope*sumK) / n
+
+	if math.Abs(slope) > math.Tan(35*math.Pi/180) {
+		return false
+	}
+
+	for _, e := range edges {
+		if math.Abs(e.Y-offset-slope*e.X) > 1.5 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// scanLines calls line with every row, column and diagonal of rect, going along them from
+// their origin by step for n pixels. The diagonals go down to the right and down to the left.
+func scanLines(rect image.Rectangle, line func(origin, step image.Point, n int)) {
+	w, h := rect.Dx(), rect.Dy()
+	for y := 0; y < h; y++ {
+		line(rect.Min.Add(image.Point{0, y}), image.Point{1, 0}, w)
+	}
+
+	for x := 0; x < w; x++ {
+		line(rect.Min.Add(image.Point{x, 0}), image.Point{0, 1}, h)
+	}
+
+	// the diagonals down to the right, from the left and top edges,
+	// then those down to the left, from the top and right edges
+	for d := 1 - h; d < w; d++ {
+		x, y := max(d, 0), max(-d, 0)
+		line(rect.Min.Add(image.Point{x, y}), image.Point{1, 1}, min(w-x, h-y))
+	}
+
+	for d := 0; d < w+h-1; d++ {
+		x := min(d, w-1)
+		y := d - x
+		line(rect.Min.Add(image.Point{x, y}), image.Point{-1, 1}, min(x+1, h-y))
+	}
+}
+
+// lineRuns measures the runs of the n pixels along a line from origin, step apart, see runsOf
+func lineRuns(prepared *image.Gray, origin, step image.Point, n int) ([]int, []int) {
+	offset, stride := prepared.PixOffset(origin.X, origin.Y), step.Y*prepared.Stride+step.X
+	return runsOf(n, func(i int) bool {
+		return prepared.Pix[offset+i*stride] == 0
+	})
+}
+
+// runsOf measures the runs of n samples, dark telling which ones are dark.
+// The runs start and end with light ones, which are empty when the samples start or end
+// with a dark one, so light runs are at even indices. edges are where each run starts,
+// and where the last one ends.
+func runsOf(n int, dark func(i int) bool) ([]int, []int) {
+	edges := []int{0}
+	if n > 0 && dark(0) {
+		// an empty light run
+		edges = append(edges, 0)
+	}
+
+	for i := 1; i < n; i++ {
+		if dark(i) != dark(i-1) {
+			edges = append(edges, i)
+		}
+	}
+
+	if n > 0 && dark(n-1) {
+		edges = append(edges, n)
+	}
+	edges = append(edges, n)
+
+	runs := make([]int, len(edges)-1)
+	for i := range runs {
+		runs[i] = edges[i+1] - edges[i]
+	}
+
+	return runs, edges
+}
+
+// reverseRuns turns runs and their edges along a line of n pixels the other way round
+func reverseRuns(runs, edges []int, n int) ([]int, []int) {
+	reversedRuns := make([]int, len(runs))
+	for i, run := range runs {
+		reversedRuns[len(runs)-1-i] = run
+	}
+
+	reversedEdges := make([]int, len(edges))
+	for i, edge := range edges {
+		reversedEdges[len(edges)-1-i] = n - edge
+	}
+
+	return reversedRuns, reversedEdges
+}
+
+func pointOf(p image.Point) Point {
+	return Point{float64(p.X), float64(p.Y)}
+}
@@ -0,0 +1,106 @@
+package ar8t
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// linearImage draws the modules of a barcode, # being a bar, 3 pixels wide and 60 pixels high
+// with a quiet zone of 12 modules around it
+func linearImage(modules string, vertical bool) *image.Gray {
+	const (
+		moduleSize = 3
+		quiet      = 12 * moduleSize
+		height     = 60
+	)
+
+	width := len(modules)*moduleSize + 2*quiet
+	img := image.NewGray(image.Rect(0, 0, width, height+2*quiet))
+	if vertical {
+		img = image.NewGray(image.Rect(0, 0, height+2*quiet, width))
+	}
+
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	for i, m := range modules {
+		if m != '#' {
+			continue
+		}
+
+		for x := quiet + i*moduleSize; x < quiet+(i+1)*moduleSize; x++ {
+			for y := quiet; y < quiet+height; y++ {
+				if vertical {
+					img.SetGray(y, x, color.Gray{})
+				} else {
+					img.SetGray(x, y, color.Gray{})
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+func Test_LinearScan(t *testing.T) {
+	tests := []struct {
+		name      string
+		modules   string
+		vertical  bool
+		symbology Symbology
+		want      string
+	}{
+		{
+			name:      "EAN-13",
+			modules:   "#.#...##.#.#..###.#.####.####.#...#..#.##..##.#.#.#....#.#....#.#....#.###.#..#....#.##..##.#.#",
+			symbology: SymbologyEAN13,
+			want:      "4006381333931",
+		},
+		{
+			name:      "EAN-8",
+			modules:   "#.#...#.##.#.####.####.#.##.###.#.#.#..###.###..#.#...#..#.###..#.#",
+			vertical:  true,
+			symbology: SymbologyEAN8,
+			want:      "96385074",
+		},
+		{
+			name:      "UPC-E",
+			modules:   "#.#.##..##..#..##.####.#..###.#.###..#.#.####.#.#.#",
+			symbology: SymbologyUPCE,
+			want:      "01234565",
+		},
+		{
+			name:      "Code 128",
+			modules:   "##.#..#....#.#...##...##...#.###.###.#..##..##.###...#.#..##.###..#..###..##.##..###..#.###.#..##..##....#..#.##...###.#.##",
+			vertical:  true,
+			symbology: SymbologyCode128,
+			want:      "AR8T-128",
+		},
+		{
+			name:      "Code 39",
+			modules:   "#..#.##.##.#.##.#.#..#.##.##.#.#.##..#.##.#..#.##.#.#.#.##.##..#.#..#.##.##.#",
+			symbology: SymbologyCode39,
+			want:      "AR8T",
+		},
+		{
+			name:      "ITF",
+			modules:   "#.#.###.#...#.#.###...###.###.#...#.#...###.#...###...#.#.#.#.#...###...###.###.#",
+			symbology: SymbologyITF,
+			want:      "12345670",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LinearScan{ITFCheckDigit: true}.Scan(linearImage(tt.modules, tt.vertical))
+			if assert.Len(t, got, 1) {
+				assert.Equal(t, tt.symbology, got[0].Symbology)
+				assert.Equal(t, tt.want, string(got[0].Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+)
+
+// MicroQRLocation is where a Micro QR Code is in an image
+type MicroQRLocation struct {
+	// Finder is the centre of the finder pattern
+	Finder Point
+
+	// DX and DY are the vectors from one module to the next along the rows and columns of the symbol
+	DX, DY Point
+
+	ModuleSize float64 //in pixels
+	Version    uint32  //1 .. 4, M1 to M4
+}
+
+// MicroScan scans a prepared image for Micro QR Codes
+//
+// Micro QR Codes have a single finder pattern, found the same way LineScan finds them.
+// Its edges give the orientation of the symbol up to a quarter turn, the side with timing patterns
+// running along its top and left edges then tells which way is up, and their length the version.
+type MicroScan struct{}
+
+func (s MicroScan) Detect(prepared *image.Gray) []MicroQRLocation {
+	return s.locate(prepared, LineScan{}.finders(prepared))
+}
+
+// locate looks for a Micro QR Code around every finder pattern candidate
+func (MicroScan) locate(prepared *image.Gray, candidates []QRFinderPosition) []MicroQRLocation {
+	locations := []MicroQRLocation{}
+
+	for _, candidate := range candidates {
+		if loc, ok := findMicroQR(prepared, candidate); ok {
+			locations = append(locations, loc)
+		}
+	}
+
+	return locations
+}
+
+func findMicroQR(prepared *image.Gray, finder QRFinderPosition) (MicroQRLocation, bool) {
+	frame, ok := locateFinder(prepared, finder)
+	if !ok {
+		return MicroQRLocation{}, false
+	}
+
+	for turn := 0; turn < 4; turn++ {
+		// the largest version until the timing patterns tell otherwise
+		loc := MicroQRLocation{
+			Finder:     frame.centre,
+			DX:         frame.dx,
+			DY:         frame.dy,
+			ModuleSize: (length(frame.dx) + length(frame.dy)) / 2,
+			Version:    4,
+		}
+
+		if refined, ok := refineMicroQR(prepared, loc); ok {
+			loc = refined
+		}
+
+		if version, ok := microTimingVersion(prepared, loc.Finder, loc.DX, loc.DY); ok {
+			loc.Version = version
+			return loc, true
+		}
+
+		frame = frame.turn()
+	}
+
+	return MicroQRLocation{}, false
+}
+
+// finderFrame is a finder pattern located from its edges, as the single finder of
+// Micro QR and rMQR Codes is. Which way is up is left to the symbol around it.
+type finderFrame struct {
+	centre Point
+
+	// dx and dy are the vectors from one module to the next along the rows and columns of the finder
+	dx, dy Point
+
+	// edges are the top, right, bottom and left edges,
+	// corners the top left, top right, bottom right and bottom left corners
+	edges   [4]line
+	corners [4]Point
+}
+
+func locateFinder(prepared *image.Gray, finder QRFinderPosition) (finderFrame, bool) {
+	centre, angle, moduleSize, ok := finderShape(prepared, finder.Location, finder.ModuleSize)
+	if !ok {
+		return finderFrame{}, false
+	}
+
+	dx := Point{math.Cos(angle), math.Sin(angle)}.Mul(moduleSize)
+	dy := Point{-dx.Y, dx.X}
+
+	var (
+		frame     finderFrame
+		outwards  = [...]Point{dy.Mul(-1), dx, dy, dx.Mul(-1)}
+		alongside = [...]Point{dx, dy, dx, dy}
+	)
+
+	for i := range frame.edges {
+		frame.edges[i], ok = fitFinderEdge(prepared, centre, outwards[i], alongside[i])
+		if !ok {
+			return finderFrame{}, false
+		}
+	}
+
+	for i := range frame.corners {
+		frame.corners[i], ok = frame.edges[i].intersect(frame.edges[(i+3)%4])
+		if !ok {
+			return finderFrame{}, false
+		}
+	}
+
+	corners := frame.corners
+	frame.centre = corners[0].Add(corners[1]).Add(corners[2]).Add(corners[3]).Div(4)
+	frame.dx = corners[1].Sub(corners[0]).Add(corners[2].Sub(corners[3])).Div(14)
+	frame.dy = corners[3].Sub(corners[0]).Add(corners[2].Sub(corners[1])).Div(14)
+
+	if diff(length(frame.dx), moduleSize) > 0.5 || diff(length(frame.dy), moduleSize) > 0.5 {
+		return finderFrame{}, false
+	}
+
+	return frame, true
+}
+
+// turn is the same finder a quarter turn clockwise, what was its right edge being its top edge
+func (f finderFrame) turn() finderFrame {
+	turned := finderFrame{centre: f.centre, dx: f.dy, dy: f.dx.Mul(-1)}
+
+	for i := range f.edges {
+		turned.edges[i] = f.edges[(i+1)%4]
+		turned.corners[i] = f.corners[(i+1)%4]
+	}
+
+	return turned
+}
+
+// refineMicroQR fits the top and left edges of the symbol along the finder and the timing patterns.
+// These are over twice as long as the edges of the finder, which makes the rotation of the rows
+// and columns far more accurate towards the far side of the symbol.
+// Past the end of the timing patterns the quiet zone adds nothing to the edges.
+func refineMicroQR(prepared *image.Gray, loc MicroQRLocation) (MicroQRLocation, bool) {
+	side := int(9 + 2*loc.Version)
+
+	// the outer ring of the finder and the dark modules of the timing patterns, in modules from the centre of the finder
+	offsets := []float64{}
+	for i := 0; i < side; i++ {
+		if i == 7 || (i > 7 && i%2 == 1) {
+			continue
+		}
+		offsets = append(offsets, float64(i)-3.25, float64(i)-2.75)
+	}
+
+	var topStarts, leftStarts []Point
+	for _, t := range offsets {
+		topStarts = append(topStarts, loc.Finder.Add(loc.DX.Mul(t)).Sub(loc.DY.Mul(3)))
+		leftStarts = append(leftStarts, loc.Finder.Add(loc.DY.Mul(t)).Sub(loc.DX.Mul(3)))
+	}
+
+	top, ok := fitEdge(prepared, topStarts, loc.DY.Mul(-1), len(offsets)/2)
+	if !ok {
+		return MicroQRLocation{}, false
+	}
+
+	left, ok := fitEdge(prepared, leftStarts, loc.DX.Mul(-1), len(offsets)/2)
+	if !ok {
+		return MicroQRLocation{}, false
+	}
+
+	corner, ok := top.intersect(left)
+	if !ok {
+		return MicroQRLocation{}, false
+	}
+
+	// keep the module size, only the direction of the edges is taken
+	align := func(d, v Point) Point {
+		if d.X*v.X+d.Y*v.Y < 0 {
+			d = d.Mul(-1)
+		}
+		return d.Mul(length(v))
+	}
+
+	refined := loc
+	refined.DX = align(top.d, loc.DX)
+	refined.DY = align(left.d, loc.DY)
+	refined.Finder = corner.Add(refined.DX.Mul(3.5)).Add(refined.DY.Mul(3.5))
+
+	// the fitted edges can't be far from the edges of the finder
+	if distance(refined.Finder, loc.Finder) > loc.ModuleSize {
+		return MicroQRLocation{}, false
+	}
+
+	return refined, true
+}
+
+// finderShape estimates the centre, rotation and module size of a finder pattern,
+// the rotation being between -45 and 45 degrees.
+//
+// Rays are cast from the centre of the finder to its outer edge. Opposite rays differ in length
+// as much as the centre is off along them, so the centre is moved a few times.
+// The edge is furthest away towards the corners, the phase of the fourth harmonic
+// of the distances to the edge is where the corners are.
+func finderShape(prepared *image.Gray, centre Point, moduleSize float64) (Point, float64, float64, bool) {
+	const rays = 72
+
+	var radii [rays]float64
+
+	cast := func() int {
+		found := 0
+		for i := range radii {
+			theta := 2 * math.Pi * float64(i) / rays
+			step := Point{math.Cos(theta), math.Sin(theta)}.Mul(0.5)
+
+			// dark centre, light ring, dark ring, light outside
+			p, dark, changes := centre, true, 0
+			for t := 0.0; t < 6*moduleSize && changes < 3; t += 0.5 {
+				p = p.Add(step)
+				if isDark(prepared, p) != dark {
+					dark = !dark
+					changes++
+				}
+			}
+
+			radii[i] = -1
+			if changes == 3 {
+				radii[i] = distance(centre, p)
+				found++
+			}
+		}
+
+		return found
+	}
+
+	for iteration := 0; iteration < 3; iteration++ {
+		if cast() < rays*3/4 {
+			return Point{}, 0, 0, false
+		}
+
+		shift, pairs := Point{}, 0
+		for i := 0; i < rays/2; i++ {
+			if radii[i] < 0 || radii[i+rays/2] < 0 {
+				continue
+			}
+
+			theta := 2 * math.Pi * float64(i) / rays
+			shift = shift.Add(Point{math.Cos(theta), math.Sin(theta)}.Mul((radii[i] - radii[i+rays/2]) / 2))
+			pairs++
+		}
+
+		centre = centre.Add(shift.Mul(2 / float64(pairs)))
+	}
+
+	if cast() < rays*3/4 {
+		return Point{}, 0, 0, false
+	}
+
+	var cos, sin, sum float64
+	found := 0
+	for i, r := range radii {
+		if r < 0 {
+			continue
+		}
+
+		theta := 2 * math.Pi * float64(i) / rays
+		cos += r * math.Cos(4*theta)
+		sin += r * math.Sin(4*theta)
+		sum += r
+		found++
+	}
+
+	// the mean distance from the centre of a square to its edge is 4/pi * ln(tan(3pi/8)) half sides,
+	// the finder is 3.5 modules from its centre to its edge
+	const meanRadius = 3.5 * 1.1222
+
+	return centre, math.Atan2(sin, cos)/4 - math.Pi/4, sum / float64(found) / meanRadius, true
+}
+
+// microTimingVersion follows the timing patterns along the top and left edges of a Micro QR Code,
+// from the separator to the quiet zone. Both must have the same length, that of a Micro QR version.
+func microTimingVersion(prepared *image.Gray, centre, dx, dy Point) (uint32, bool) {
+	module := func(x, y int) bool {
+		return isDark(prepared, centre.Add(dx.Mul(float64(x)-3)).Add(dy.Mul(float64(y)-3)))
+	}
+
+	side := func(timing func(i int) bool) int {
+		// past the symbol the quiet zone breaks the pattern
+		for i := 7; i < 20; i++ {
+			if timing(i) != (i%2 == 0) {
+				return i - 1
+			}
+		}
+		return 0
+	}
+
+	top := side(func(i int) bool { return module(i, 0) })
+	left := side(func(i int) bool { return module(0, i) })
+
+	if top != left || top < 11 || top > 17 || top%2 == 0 {
+		return 0, false
+	}
+
+	return uint32(top-9) / 2, true
+}
+
+func length(p Point) float64 {
+	return math.Sqrt(p.X*p.X + p.Y*p.Y)
+}
@@ -0,0 +1,362 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// PDF417Location is where a PDF417 symbol is in an image
+type PDF417Location struct {
+	// the outer corners of the start pattern on the left and of the stop pattern on the right,
+	// upright as the start pattern puts it
+	TopLeft, TopRight, BottomRight, BottomLeft Point
+
+	ModuleSize float64 //in pixels
+}
+
+// PDF417Scan scans a prepared image for PDF417 symbols
+//
+// The general idea of this method is as follows:
+// 1. Measure the runs along every row, column and diagonal both ways, as LinearScan does, for the start
+// pattern, 81111113, and the stop pattern read from its end, 121113117, after a quiet zone
+// 2. Every line crossing the start or the stop pattern finds a point of its outer edge,
+// the points next to each other found from the same side make up the edge
+// 3. Fit a line through each edge, its ends being the corners of the symbol on that side,
+// and pair every start edge with the nearest stop edge facing it
+type PDF417Scan struct{}
+
+var (
+	pdf417Start = []int{8, 1, 1, 1, 1, 1, 1, 3}
+	pdf417Stop  = []int{7, 1, 1, 3, 1, 1, 1, 2, 1}
+
+	// the stop pattern from its end
+	pdf417StopReversed = []int{1, 2, 1, 1, 1, 3, 1, 1, 7}
+)
+
+const (
+	pdf417MaxVariance = 0.35
+
+	// the narrowest symbols are 86 modules wide, with a single column of data
+	pdf417MinModules = 69 + 17
+)
+
+// pdf417Hit is a point of the outer edge of a start or stop pattern, found along a line
+type pdf417Hit struct {
+	p    Point
+	into Point //the way along the line into the symbol
+	stop bool
+
+	module float64
+}
+
+// pdf417Edge is the outer edge of a start or stop pattern
+type pdf417Edge struct {
+	top, bottom Point
+	stop        bool
+
+	// down goes from top to bottom, right goes across the symbol from the start pattern to the stop pattern
+	down, right Point
+
+	module float64
+}
+
+func (PDF417Scan) Detect(prepared *image.Gray) []PDF417Location {
+	hits := []pdf417Hit{}
+
+	scanLines(prepared.Rect, func(origin, step image.Point, n int) {
+		runs, edges := lineRuns(prepared, origin, step, n)
+
+		for _, reversed := range []bool{false, true} {
+			if reversed {
+				runs, edges = reverseRuns(runs, edges, n)
+			}
+
+			for i := 1; i+9 < len(runs); i += 2 {
+				for _, stop := range []bool{false, true} {
+					pattern := pdf417Start
+					if stop {
+						pattern = pdf417StopReversed
+					}
+
+					// the quiet zone is at least 2 modules wide
+					width := sum(runs[i : i+len(pattern)])
+					if runs[i-1]*17 < 2*width || runVariance(runs[i:], pattern) > pdf417MaxVariance {
+						continue
+					}
+
+					first, into := edges[i], step
+					if reversed {
+						first, into = n-1-first, image.Point{-step.X, -step.Y}
+					}
+
+					// the outer edge of the pixel is half a step back from its middle
+					p := pointOf(origin.Add(step.Mul(first))).Sub(pointOf(into).Div(2))
+					length := math.Hypot(float64(step.X), float64(step.Y))
+
+					hits = append(hits, pdf417Hit{
+						p:      p,
+						into:   pointOf(into).Div(length),
+						stop:   stop,
+						module: float64(width) * length / 17,
+					})
+				}
+			}
+		}
+	})
+
+	edges := []pdf417Edge{}
+	for _, group := range pdf417Groups(hits) {
+		if edge, ok := fitPDF417Edge(prepared, hits, group); ok {
+			edges = append(edges, edge)
+		}
+	}
+
+	return pairPDF417Edges(edges)
+}
+
+// pdf417Groups groups the hits next to each other found on the same side of the same pattern
+func pdf417Groups(hits []pdf417Hit) [][]int {
+	parent := make([]int, len(hits))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	// neighbouring lines find hits a pixel or two apart, lines missing the pattern leave gaps
+	// up to a few pixels wide. Hits are compared with those in the cells around them,
+	// cells being as wide as the largest gap.
+	const gap = 4
+	cells := map[image.Point][]int{}
+	cellOf := func(p Point) image.Point {
+		return image.Point{int(math.Floor(p.X / gap)), int(math.Floor(p.Y / gap))}
+	}
+
+	for i, hit := range hits {
+		cell := cellOf(hit.p)
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				for _, j := range cells[cell.Add(image.Point{dx, dy})] {
+					other := hits[j]
+					if other.stop == hit.stop && projection(other.into, hit.into) > 0 && distance(other.p, hit.p) <= gap {
+						parent[root(j)] = root(i)
+					}
+				}
+			}
+		}
+
+		cells[cell] = append(cells[cell], i)
+	}
+
+	groups := map[int][]int{}
+	for i := range hits {
+		groups[root(i)] = append(groups[root(i)], i)
+	}
+
+	res := [][]int{}
+	for _, group := range groups {
+		res = append(res, group)
+	}
+
+	// map order is random, the symbols are found in the order of their first hits
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][0] < res[j][0]
+	})
+
+	return res
+}
+
+// fitPDF417Edge fits a line through the hits of a group. Lines entering a pattern through the end
+// of its bars can find hits off its edge, those more than a module away from the line are left out.
+func fitPDF417Edge(prepared *image.Gray, hits []pdf417Hit, group []int) (pdf417Edge, bool) {
+	const minPoints = 6
+	if len(group) < minPoints {
+		return pdf417Edge{}, false
+	}
+
+	points, into, module := []Point{}, Point{}, 0.0
+	for _, i := range group {
+		points, into = append(points, hits[i].p), into.Add(hits[i].into)
+		module += hits[i].module
+	}
+	module /= float64(len(group))
+
+	edge, kept, ok := fitNearPoints(points, module, minPoints)
+	if !ok {
+		return pdf417Edge{}, false
+	}
+
+	mean, down := edge.p, edge.d
+
+	// the symbol is to the right of the start pattern going down, and to the left of the stop pattern
+	stop := hits[group[0]].stop
+	across := into
+	if stop {
+		across = Point{-into.X, -into.Y}
+	}
+
+	if across.X*down.Y-across.Y*down.X < 0 {
+		down = Point{-down.X, -down.Y}
+	}
+
+	inside := Point{down.Y, -down.X}
+	if stop {
+		inside = Point{-inside.X, -inside.Y}
+	}
+
+	from, to := math.Inf(1), math.Inf(-1)
+	for _, p := range kept {
+		along := projection(p.Sub(mean), down)
+		from, to = math.Min(from, along), math.Max(to, along)
+	}
+
+	// lines slanted to the rows of the symbol leave the bar through its ends before crossing
+	// the whole pattern, so the ends of the bar are followed along its middle from the hits
+	bar := func(along float64) bool {
+		return isDark(prepared, mean.Add(inside.Mul(module/2)).Add(down.Mul(along)))
+	}
+
+	const step = 0.5
+	for bar(from - step) {
+		from -= step
+	}
+	for bar(to + step) {
+		to += step
+	}
+
+	// the hits are whole pixels, and only along part of the edge when the lines are slanted,
+	// so the edge is measured again from outside at every pixel along it, a quarter of a pixel at a time
+	measured := []Point{}
+	for along := from; along <= to; along++ {
+		base := mean.Add(down.Mul(along))
+		for t := -module; t <= module; t += 0.25 {
+			if isDark(prepared, base.Add(inside.Mul(t))) {
+				measured = append(measured, base.Add(inside.Mul(t-0.125)))
+				break
+			}
+		}
+	}
+
+	if refined, _, ok := fitNearPoints(measured, module/2, minPoints); ok {
+		top, bottom := mean.Add(down.Mul(from)), mean.Add(down.Mul(to))
+
+		mean, down = refined.p, refined.d
+		if projection(down, bottom.Sub(top)) < 0 {
+			down = Point{-down.X, -down.Y}
+		}
+		from, to = projection(top.Sub(mean), down), projection(bottom.Sub(mean), down)
+	}
+
+	// the ends are half a pixel further than the last dark pixels, and a symbol is at least
+	// 3 rows high, each at least 2 modules
+	from, to = from-0.5, to+0.5
+	if to-from < 6*module {
+		return pdf417Edge{}, false
+	}
+
+	return pdf417Edge{
+		top:    mean.Add(down.Mul(from)),
+		bottom: mean.Add(down.Mul(to)),
+		stop:   stop,
+		down:   down,
+		right:  Point{down.Y, -down.X},
+		module: module,
+	}, true
+}
+
+// fitNearPoints fits a line through points, then again through those near enough to it
+// a few times over. It fails when fewer than minPoints, or fewer than half the points, are left.
+func fitNearPoints(points []Point, near float64, minPoints int) (line, []Point, bool) {
+	if len(points) < minPoints {
+		return line{}, nil, false
+	}
+
+	kept := points
+	var fitted line
+	for round := 0; round < 3; round++ {
+		fitted = fitLine(kept)
+
+		kept = []Point{}
+		for _, p := range points {
+			d := p.Sub(fitted.p)
+			if math.Abs(d.X*fitted.d.Y-d.Y*fitted.d.X) <= near {
+				kept = append(kept, p)
+			}
+		}
+
+		if len(kept) < minPoints || 2*len(kept) < len(points) {
+			return line{}, nil, false
+		}
+	}
+
+	return fitted, kept, true
+}
+
+// pairPDF417Edges pairs start edges with the stop edges facing them, nearest first
+func pairPDF417Edges(edges []pdf417Edge) []PDF417Location {
+	type pair struct {
+		start, stop int
+		distance    float64
+	}
+
+	pairs := []pair{}
+	for i, start := range edges {
+		if start.stop {
+			continue
+		}
+
+		for j, stop := range edges {
+			if !stop.stop || projection(start.down, stop.down) < 0.8 {
+				continue
+			}
+
+			module := (start.module + stop.module) / 2
+			if start.module > 2*stop.module || stop.module > 2*start.module {
+				continue
+			}
+
+			// the stop edge is far enough to the right, and level with the start edge
+			startMiddle, stopMiddle := start.top.Add(start.bottom).Div(2), stop.top.Add(stop.bottom).Div(2)
+			d := stopMiddle.Sub(startMiddle)
+			across, along := projection(d, start.right), projection(d, start.down)
+
+			height := distance(start.top, start.bottom)
+			if across < 0.8*pdf417MinModules*module || math.Abs(along) > height/2 {
+				continue
+			}
+
+			pairs = append(pairs, pair{i, j, across})
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].distance < pairs[j].distance
+	})
+
+	used := make([]bool, len(edges))
+	locations := []PDF417Location{}
+	for _, p := range pairs {
+		if used[p.start] || used[p.stop] {
+			continue
+		}
+		used[p.start], used[p.stop] = true, true
+
+		start, stop := edges[p.start], edges[p.stop]
+		locations = append(locations, PDF417Location{
+			TopLeft:     start.top,
+			TopRight:    stop.top,
+			BottomRight: stop.bottom,
+			BottomLeft:  start.bottom,
+			ModuleSize:  (start.module + stop.module) / 2,
+		})
+	}
+
+	return locations
+}
@@ -0,0 +1,402 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+)
+
+// RMQRLocation is where an rMQR Code is in an image
+type RMQRLocation struct {
+	// the outer corners of the symbol, the finder pattern being in the top left corner
+	// and the sub-finder pattern in the bottom right one
+	TopLeft, TopRight, BottomRight, BottomLeft Point
+
+	// Columns are the distances in pixels from TopLeft along the top edge to where every column
+	// starts, and to where the last one ends. Perspective spaces them unevenly along long symbols,
+	// evenly spaced columns are assumed when it is nil.
+	Columns []float64
+
+	ModuleSize float64 //in pixels
+	Version    uint32  //1 .. 32, R7x43 to R17x139
+}
+
+// RMQRScan scans a prepared image for rMQR Codes
+//
+// rMQR Codes have a finder pattern in the top left corner, found the same way LineScan finds them,
+// and a smaller sub-finder pattern in the bottom right corner. The timing patterns along the top
+// and left edges of the symbol, from the finder to the quiet zone, give its width and height.
+// The top edge is long enough that it is refitted at every dark module on the way.
+type RMQRScan struct{}
+
+func (s RMQRScan) Detect(prepared *image.Gray) []RMQRLocation {
+	return s.locate(prepared, LineScan{}.finders(prepared))
+}
+
+// locate looks for an rMQR Code around every finder pattern candidate
+func (RMQRScan) locate(prepared *image.Gray, candidates []QRFinderPosition) []RMQRLocation {
+	locations := []RMQRLocation{}
+
+	for _, candidate := range candidates {
+		if loc, ok := findRMQR(prepared, candidate); ok {
+			locations = append(locations, loc)
+		}
+	}
+
+	return locations
+}
+
+func findRMQR(prepared *image.Gray, finder QRFinderPosition) (RMQRLocation, bool) {
+	frame, ok := locateFinder(prepared, finder)
+	if !ok {
+		return RMQRLocation{}, false
+	}
+
+	for turn := 0; turn < 4; turn++ {
+		if loc, ok := rmqrFromFinder(prepared, frame); ok {
+			return loc, true
+		}
+
+		frame = frame.turn()
+	}
+
+	return RMQRLocation{}, false
+}
+
+// rmqrFromFinder follows the timing patterns along the top and left edges from a finder
+// with its top edge up, then looks for the sub-finder where the size of the symbol puts it
+func rmqrFromFinder(prepared *image.Gray, frame finderFrame) (RMQRLocation, bool) {
+	corner := frame.corners[0]
+
+	top, ok := followTiming(prepared, corner, frame.edges[0], frame.dx, frame.dy, 7)
+	if !ok {
+		return RMQRLocation{}, false
+	}
+
+	left, ok := followTiming(prepared, corner, frame.edges[3], frame.dy, frame.dx, 7)
+	if !ok {
+		return RMQRLocation{}, false
+	}
+
+	width, height, alignment := top.modules, left.modules, top.alignment
+
+	version := 0
+	for i, v := range rmqrVersions {
+		if v.width == width && v.height == height {
+			version = i + 1
+		}
+	}
+
+	if version == 0 || len(alignment) != len(rmqrVersions[version-1].alignment) {
+		return RMQRLocation{}, false
+	}
+
+	// the top of every alignment pattern breaks the timing pattern with three dark modules
+	for i, x := range rmqrVersions[version-1].alignment {
+		if alignment[i] != x-1 {
+			return RMQRLocation{}, false
+		}
+	}
+
+	columns, rows := top.positions(width), left.positions(height)
+	topRight := top.origin.Add(top.dir.Mul(columns[width]))
+	bottomLeft := left.origin.Add(left.dir.Mul(rows[height]))
+
+	// perspective makes the columns narrower or wider along the top edge, with the square
+	// of how much the symbol is shorter or taller, which says how large the sub-finder is
+	var (
+		leftPitch  = (columns[7] - columns[0]) / 7
+		rightPitch = (columns[width] - columns[width-7]) / 7
+
+		dx = top.dir.Mul(rightPitch)
+		dy = bottomLeft.Sub(corner).Div(float64(height)).Mul(math.Sqrt(rightPitch / leftPitch))
+	)
+
+	estimate := topRight.Sub(dx.Mul(2.5)).Add(dy.Mul(float64(height) - 2.5))
+
+	subFinder, ok := findSubFinder(prepared, estimate, dx, dy)
+	if !ok {
+		return RMQRLocation{}, false
+	}
+
+	return RMQRLocation{
+		TopLeft:     corner,
+		TopRight:    topRight,
+		BottomRight: subFinder.Add(dx.Add(dy).Mul(2.5)),
+		BottomLeft:  bottomLeft,
+		Columns:     columns,
+		ModuleSize:  (columns[width]/float64(width) + distance(corner, bottomLeft)/float64(height)) / 2,
+		Version:     uint32(version),
+	}, true
+}
+
+// timing is a timing pattern followed along an edge of an rMQR Code or a Data Matrix symbol
+type timing struct {
+	// modules is the length of the edge in modules
+	modules int
+
+	// the edge starts at origin, in the direction dir
+	origin, dir Point
+
+	// starts are where runs of modules of the same colour start, in pixels from origin,
+	// dark are the starts of the dark runs
+	starts, dark []moduleStart
+
+	// alignment are the first modules of every run of three dark modules that isn't at the end,
+	// as the tops of alignment patterns are
+	alignment []int
+}
+
+type moduleStart struct {
+	module int
+	s      float64
+}
+
+// followTiming follows the timing pattern along an edge of an rMQR Code, from the separator
+// of the finder to the quiet zone, through the middle of the outermost modules.
+//
+// edge is the edge of the finder, corner the corner of the symbol on it, along and inward
+// are the vectors from one module to the next along the edge and into the symbol.
+// The finder is start modules long, the timing pattern starting with a light module after it.
+// The edge is refitted at every dark run once there are enough points on it, and the length
+// of a module is measured between the starts of dark runs a few modules apart,
+// which keeps up with a slight perspective.
+func followTiming(prepared *image.Gray, corner Point, edge line, along, inward Point, start int) (timing, bool) {
+	const maxModules = 144
+
+	moduleSize := length(along)
+
+	if projection(along, edge.d) < 0 {
+		edge.d = edge.d.Mul(-1)
+	}
+
+	// the finder edge, and the edge of every dark module after it
+	points := []Point{}
+	for t := 0.5; t < float64(start); t += 0.5 {
+		points = append(points, edge.p.Add(edge.d.Mul(projection(corner.Sub(edge.p), edge.d)+t*moduleSize)))
+	}
+
+	var (
+		t      = timing{starts: []moduleStart{{0, 0}}}
+		normal Point
+	)
+
+	refit := func() {
+		if len(points) >= 3 {
+			edge = fitLine(points)
+		}
+
+		t.dir = edge.d
+		if projection(along, t.dir) < 0 {
+			t.dir = t.dir.Mul(-1)
+		}
+
+		normal = Point{-t.dir.Y, t.dir.X}
+		if projection(inward, normal) < 0 {
+			normal = normal.Mul(-1)
+		}
+
+		t.origin = edge.p.Add(t.dir.Mul(projection(corner.Sub(edge.p), t.dir)))
+	}
+	refit()
+
+	// middle of the outermost modules, at distance s along the edge
+	at := func(s float64) Point {
+		return t.origin.Add(t.dir.Mul(s)).Add(normal.Mul(moduleSize / 2))
+	}
+
+	var (
+		pitch  = moduleSize
+		module = start
+		s      = float64(start) * moduleSize
+		dark   = false
+	)
+
+	// the separator starts where the finder ends
+	for isDark(prepared, at(s)) && s < (float64(start)+0.5)*moduleSize {
+		s += 0.5
+	}
+
+	for module <= maxModules {
+		// refitting the edge moves the middle of the modules a little, past the end of the last run
+		for i := 0.0; isDark(prepared, at(s)) != dark && i < pitch/2; i += 0.5 {
+			s += 0.5
+		}
+
+		// the run starting at s
+		runStart := s
+		for isDark(prepared, at(s)) == dark {
+			s += 0.5
+			if s-runStart > 4*pitch {
+				break
+			}
+		}
+
+		modules := int(math.Round((s - runStart) / pitch))
+		if modules < 1 {
+			modules = 1
+		}
+
+		t.starts = append(t.starts, moduleStart{module, runStart})
+
+		if !dark && modules >= 2 {
+			// the quiet zone, after the corner of the symbol
+			if n := len(t.alignment); n > 0 && t.alignment[n-1]+3 == module {
+				t.alignment = t.alignment[:n-1]
+			}
+
+			t.modules = module
+			return t, true
+		}
+
+		if dark {
+			if modules > 3 {
+				return timing{}, false
+			}
+
+			if modules == 3 {
+				t.alignment = append(t.alignment, module)
+			}
+
+			// the outer edge of the middle of the run
+			p, step := at((runStart+s)/2), normal.Mul(-0.5)
+			for i := 0; i < int(moduleSize*2) && isDark(prepared, p); i++ {
+				p = p.Add(step)
+			}
+			points = append(points, p.Sub(step.Div(2)))
+			refit()
+
+			t.dark = append(t.dark, moduleStart{module, runStart})
+			for _, first := range t.dark {
+				if module-first.module <= 8 && module-first.module >= 4 {
+					pitch = (runStart - first.s) / float64(module-first.module)
+					break
+				}
+			}
+		}
+
+		module += modules
+		dark = !dark
+	}
+
+	return timing{}, false
+}
+
+// until drops the runs after the one starting at last, which becomes the end of the edge
+func (t timing) until(last moduleStart) timing {
+	starts := []moduleStart{}
+	for _, start := range t.starts {
+		if start.module <= last.module {
+			starts = append(starts, start)
+		}
+	}
+
+	t.starts, t.modules = starts, last.module
+	return t
+}
+
+// positions fits the perspective along the edge, a projective map from modules to pixels
+// s = (a*m + b) / (c*m + 1), to the starts of the runs. It returns where every module starts,
+// and where the last one ends, in pixels from the origin, for an edge modules long.
+func (t timing) positions(modules int) []float64 {
+	// least squares of a*m + b - c*m*s = s
+	var normal [3][4]float64
+	for _, start := range t.starts {
+		m := float64(start.module)
+		row := [...]float64{m, 1, -m * start.s, start.s}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 4; j++ {
+				normal[i][j] += row[i] * row[j]
+			}
+		}
+	}
+
+	a, b, c, ok := solve3(normal)
+	if !ok {
+		last := t.starts[len(t.starts)-1]
+		a, b, c = last.s/float64(last.module), 0, 0
+	}
+
+	columns := make([]float64, modules+1)
+	for i := range columns {
+		m := float64(i)
+		columns[i] = (a*m + b) / (c*m + 1)
+	}
+
+	return columns
+}
+
+// solve3 solves three linear equations by Cramer's rule, m being their augmented matrix
+func solve3(m [3][4]float64) (float64, float64, float64, bool) {
+	det := func(col int, with int) float64 {
+		var d [3][3]float64
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				d[i][j] = m[i][j]
+				if j == col {
+					d[i][j] = m[i][with]
+				}
+			}
+		}
+
+		return d[0][0]*(d[1][1]*d[2][2]-d[1][2]*d[2][1]) -
+			d[0][1]*(d[1][0]*d[2][2]-d[1][2]*d[2][0]) +
+			d[0][2]*(d[1][0]*d[2][1]-d[1][1]*d[2][0])
+	}
+
+	d := det(0, 0)
+	if math.Abs(d) < 1e-9 {
+		return 0, 0, 0, false
+	}
+
+	return det(0, 3) / d, det(1, 3) / d, det(2, 3) / d, true
+}
+
+// findSubFinder looks for the sub-finder around its estimated centre:
+// a dark module surrounded by a ring of light modules and a ring of dark ones.
+// The centre is the mean of every position the pattern is seen from.
+func findSubFinder(prepared *image.Gray, estimate, dx, dy Point) (Point, bool) {
+	matches := func(p Point) bool {
+		for y := -2; y <= 2; y++ {
+			for x := -2; x <= 2; x++ {
+				ring := max(abs(x), abs(y))
+				module := p.Add(dx.Mul(float64(x))).Add(dy.Mul(float64(y)))
+				if isDark(prepared, module) != (ring != 1) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	var (
+		sum   Point
+		found int
+	)
+
+	for y := -1.5; y <= 1.5; y += 0.25 {
+		for x := -1.5; x <= 1.5; x += 0.25 {
+			if p := estimate.Add(dx.Mul(x)).Add(dy.Mul(y)); matches(p) {
+				sum = sum.Add(p)
+				found++
+			}
+		}
+	}
+
+	if found == 0 {
+		return Point{}, false
+	}
+
+	return sum.Div(float64(found)), true
+}
+
+// projection is the length of p along the unit vector d
+func projection(p, d Point) float64 {
+	return p.X*d.X + p.Y*d.Y
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,124 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// MultiScale scans an image pyramid for QR Codes.
+//
+// LineScan only works at the resolution it is given: modules smaller than ~2 pixels
+// never look like a finder and huge symbols make the scan needlessly slow.
+// MultiScale resizes the source image by every scale, prepares and scans it,
+// then maps the found locations back to the coordinates of the source image,
+// so the locations can be extracted from the source image as usual.
+// Modules are always sampled at full resolution: scaling only helps finding symbols,
+// not reading modules too small to sample.
+type MultiScale struct {
+	// Scales to run the detection at, in order. 1 is the native resolution,
+	// 0.5 halves the image and 2 doubles it.
+	Scales []float64
+
+	// Preparer defaults to the same BlockedMean used by DefaultDecoder
+	Preparer Preparer
+
+	// Detector defaults to LineScan
+	Detector Detector
+}
+
+func (m MultiScale) Detect(src image.Image) []QRLocation {
+	return m.detect(src, nil)
+}
+
+// detect is Detect, reusing native as the source image prepared for scale 1 unless it is nil
+func (m MultiScale) detect(src image.Image, native *image.Gray) []QRLocation {
+	preparer, detector := m.Preparer, m.Detector
+	if preparer == nil {
+		preparer = NewBlockedMean(3, 7)
+	}
+	if detector == nil {
+		detector = LineScan{}
+	}
+
+	bounds := src.Bounds()
+	locations := []QRLocation{}
+	// the scale each location was found at, the same symbol found at a larger scale
+	// replaces the earlier one, since its finder positions are more precise
+	foundAt := []float64{}
+
+	for _, scale := range m.Scales {
+		width := int(math.Round(float64(bounds.Dx()) * scale))
+		height := int(math.Round(float64(bounds.Dy()) * scale))
+		if scale <= 0 || width < 21 || height < 21 {
+			continue
+		}
+
+		var prepared *image.Gray
+		switch {
+		case scale != 1:
+			filter := imaging.Linear
+			if scale < 1 {
+				filter = imaging.Box
+			}
+			prepared = preparer.Prepare(imaging.Resize(src, width, height, filter))
+		case native != nil:
+			prepared = native
+		default:
+			prepared = preparer.Prepare(src)
+		}
+
+		scaleX := float64(width) / float64(bounds.Dx())
+		scaleY := float64(height) / float64(bounds.Dy())
+
+	found:
+		for _, loc := range detector.Detect(prepared) {
+			loc = unscaleLocation(loc, scaleX, scaleY)
+
+			for i, other := range locations {
+				if sameLocation(loc, other) {
+					if scale > foundAt[i] {
+						locations[i], foundAt[i] = loc, scale
+					}
+					continue found
+				}
+			}
+
+			locations = append(locations, loc)
+			foundAt = append(foundAt, scale)
+		}
+	}
+
+	return locations
+}
+
+// unscaleLocation maps a location found in an image resized by scaleX, scaleY
+// back to the original image. Pixel centres are aligned, not pixel corners.
+func unscaleLocation(loc QRLocation, scaleX, scaleY float64) QRLocation {
+	unscale := func(p Point) Point {
+		return Point{
+			X: (p.X+0.5)/scaleX - 0.5,
+			Y: (p.Y+0.5)/scaleY - 0.5,
+		}
+	}
+
+	return QRLocation{
+		TopLeft:    unscale(loc.TopLeft),
+		TopRight:   unscale(loc.TopRight),
+		BottomLeft: unscale(loc.BottomLeft),
+		ModuleSize: loc.ModuleSize * 2 / (scaleX + scaleY),
+		Version:    loc.Version,
+	}
+}
+
+// sameLocation reports whether both locations describe the same symbol,
+// as found at two different scales. Versions are estimated from the distances
+// between finders, so they can be off by one between scales and aren't compared.
+func sameLocation(a, b QRLocation) bool {
+	tolerance := 3.5 * max(a.ModuleSize, b.ModuleSize)
+
+	return distance(a.TopLeft, b.TopLeft) < tolerance &&
+		distance(a.TopRight, b.TopRight) < tolerance &&
+		distance(a.BottomLeft, b.BottomLeft) < tolerance
+}
@@ -0,0 +1,145 @@
+package ar8t
+
+import (
+	"image"
+	"testing"
+
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MultiScale(t *testing.T) {
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// version 1 with modules of a single pixel, too small for LineScan on its own
+	symbol := symbols[0]
+	img := symbol.Render(1)
+
+	assert.Empty(t, LineScan{}.Detect(NewBlockedMean(3, 7).Prepare(img)))
+	_, err = DefaultDecoder{}.DecodeResults(img)
+	assert.ErrorIs(t, err, ErrNoSymbolsFound)
+
+	locations := MultiScale{Scales: []float64{1, 2}}.Detect(img)
+	if assert.Len(t, locations, 1) {
+		// the centres of the finders are 3.5 modules into the symbol, in the coordinates of img
+		const topLeft, bottomRight = corpus.QuietZone + 3.5 - 0.5, corpus.QuietZone + 21 - 3.5 - 0.5
+
+		loc := locations[0]
+		assert.Equal(t, uint32(1), loc.Version)
+		assert.InDelta(t, 1, loc.ModuleSize, 0.25)
+		for _, p := range []struct{ got, want Point }{
+			{loc.TopLeft, Point{topLeft, topLeft}},
+			{loc.TopRight, Point{bottomRight, topLeft}},
+			{loc.BottomLeft, Point{topLeft, bottomRight}},
+		} {
+			assert.InDelta(t, p.want.X, p.got.X, 0.5)
+			assert.InDelta(t, p.want.Y, p.got.Y, 0.5)
+		}
+	}
+
+	results, err := DefaultDecoder{Scales: []float64{1, 2}}.DecodeResults(img)
+	if assert.NoError(t, err) && assert.Len(t, results, 1) {
+		assert.Equal(t, symbol.Text, string(results[0].Data))
+	}
+}
+
+// countingPreparer counts the images it prepares
+type countingPreparer struct {
+	Preparer
+	count *int
+}
+
+func (p countingPreparer) Prepare(img image.Image) *image.Gray {
+	*p.count++
+	return p.Preparer.Prepare(img)
+}
+
+func Test_MultiScale_native(t *testing.T) {
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+	img := symbols[0].Render(1)
+
+	prepared := 0
+	preparer := countingPreparer{NewBlockedMean(3, 7), &prepared}
+	native := preparer.Prepare(img)
+
+	locations := MultiScale{Scales: []float64{1, 2}, Preparer: preparer}.detect(img, native)
+	assert.Len(t, locations, 1)
+	assert.Equal(t, 2, prepared, "the native image is prepared once, then the doubled one")
+}
+
+func Test_unscaleLocation(t *testing.T) {
+	tests := []struct {
+		name           string
+		loc            QRLocation
+		scaleX, scaleY float64
+		want           QRLocation
+	}{
+		{
+			name:   "native resolution",
+			loc:    QRLocation{TopLeft: Point{10, 10}, TopRight: Point{50, 10}, BottomLeft: Point{10, 50}, ModuleSize: 3, Version: 2},
+			scaleX: 1, scaleY: 1,
+			want: QRLocation{TopLeft: Point{10, 10}, TopRight: Point{50, 10}, BottomLeft: Point{10, 50}, ModuleSize: 3, Version: 2},
+		},
+		{
+			name:   "doubled",
+			loc:    QRLocation{TopLeft: Point{9.5, 9.5}, TopRight: Point{49.5, 9.5}, BottomLeft: Point{9.5, 49.5}, ModuleSize: 4, Version: 2},
+			scaleX: 2, scaleY: 2,
+			want: QRLocation{TopLeft: Point{4.5, 4.5}, TopRight: Point{24.5, 4.5}, BottomLeft: Point{4.5, 24.5}, ModuleSize: 2, Version: 2},
+		},
+		{
+			name:   "halved unevenly",
+			loc:    QRLocation{TopLeft: Point{4.5, 9.5}, TopRight: Point{24.5, 9.5}, BottomLeft: Point{4.5, 29.5}, ModuleSize: 2, Version: 1},
+			scaleX: 0.5, scaleY: 0.5 * 4 / 3,
+			want: QRLocation{TopLeft: Point{9.5, 14.5}, TopRight: Point{49.5, 14.5}, BottomLeft: Point{9.5, 44.5}, ModuleSize: 2 * 2 / (0.5 + 0.5*4/3), Version: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unscaleLocation(tt.loc, tt.scaleX, tt.scaleY)
+			assert.InDelta(t, tt.want.ModuleSize, got.ModuleSize, 1e-9)
+			assert.Equal(t, tt.want.Version, got.Version)
+			for _, p := range [][2]Point{{tt.want.TopLeft, got.TopLeft}, {tt.want.TopRight, got.TopRight}, {tt.want.BottomLeft, got.BottomLeft}} {
+				assert.InDelta(t, p[0].X, p[1].X, 1e-9)
+				assert.InDelta(t, p[0].Y, p[1].Y, 1e-9)
+			}
+		})
+	}
+}
+
+func Test_sameLocation(t *testing.T) {
+	loc := QRLocation{TopLeft: Point{20, 20}, TopRight: Point{120, 20}, BottomLeft: Point{20, 120}, ModuleSize: 4, Version: 4}
+	moved := func(dx, dy float64) QRLocation {
+		return QRLocation{
+			TopLeft:    Point{loc.TopLeft.X + dx, loc.TopLeft.Y + dy},
+			TopRight:   Point{loc.TopRight.X + dx, loc.TopRight.Y + dy},
+			BottomLeft: Point{loc.BottomLeft.X + dx, loc.BottomLeft.Y + dy},
+			ModuleSize: loc.ModuleSize,
+			Version:    loc.Version,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		other QRLocation
+		want  bool
+	}{
+		{"same", loc, true},
+		{"within a finder", moved(6, -6), true},
+		{"a finder away", moved(15, 0), false},
+		{"another symbol", moved(200, 0), false},
+		{"version estimated differently", func() QRLocation { other := moved(1, 1); other.Version = 5; return other }(), true},
+		{"turned around", QRLocation{TopLeft: loc.TopLeft, TopRight: loc.BottomLeft, BottomLeft: loc.TopRight, ModuleSize: 4, Version: 4}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sameLocation(loc, tt.other))
+			assert.Equal(t, tt.want, sameLocation(tt.other, loc))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package ar8t
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rotatedImage draws modules rotated clockwise by angle degrees around the centre of a square image,
+// with modules of moduleSize pixels and a quiet zone of 4 modules. Every pixel is the mean of 4 x 4 samples.
+func rotatedImage(modules BitMatrix, moduleSize, angle float64) *image.Gray {
+	const samples = 4
+
+	width, height := float64(modules.Width()), float64(modules.Height())
+	side := int(math.Ceil(math.Hypot(width+8, height+8) * moduleSize))
+	centre := float64(side) / 2
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			light := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					px := float64(x) + (float64(sx)+0.5)/samples - centre
+					py := float64(y) + (float64(sy)+0.5)/samples - centre
+					mx := int(math.Floor((cos*px+sin*py)/moduleSize + width/2))
+					my := int(math.Floor((cos*py-sin*px)/moduleSize + height/2))
+					if !modules.in(mx, my) || !modules.Get(mx, my) {
+						light++
+					}
+				}
+			}
+			img.SetGray(x, y, color.Gray{uint8(255 * light / (samples * samples))})
+		}
+	}
+
+	return img
+}
+
+func Test_LineScan_rotations(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		modules string
+	}{
+		{
+			name: "version 1",
+			want: "ar8t rotation",
+			modules: `
+				#######..###..#######
+				#.....#....##.#.....#
+				#.###.#.##.#..#.###.#
+				#.###.#.##.##.#.###.#
+				#.###.#.#.#.#.#.###.#
+				#.....#.#.##..#.....#
+				#######.#.#.#.#######
+				........#............
+				#.#####...##..#####..
+				.##.#...###.##..#.###
+				#..#..#...#.#......#.
+				#.#..#.##....#..#.##.
+				.###.##.####..#.##...
+				........#......##...#
+				#######....###....##.
+				#.....#.####.#.#####.
+				#.###.#.#..#.###.#.##
+				#.###.#.#....#..###..
+				#.###.#.#.###.....#..
+				#.....#...####.#..#..
+				#######.#.....##...#.
+			`,
+		},
+		{
+			name: "version 4",
+			want: "https://github.com/mrg0lden/ar8t rotated in steps of 5 degrees",
+			modules: `
+				#######.#..##.#.#.####.#..#######
+				#.....#.#.#.....##..##.##.#.....#
+				#.###.#...#####.#.......#.#.###.#
+				#.###.#.#..#.###.##...#...#.###.#
+				#.###.#...#.#..######.#.#.#.###.#
+				#.....#..#.......###.##...#.....#
+				#######.#.#.#.#.#.#.#.#.#.#######
+				........####..#.####...#.........
+				#.##.###..#.....##.####.#.#..#.##
+				######.###.##.#.#..###.#..##.####
+				..#...###.#####.###...##.#####.##
+				#....#..#.#..###..###.#....#.#.#.
+				#.#..##.#.#......###..####.###...
+				####.#..#..##.####.#.#.....#.#.#.
+				.#..#.#...#..##.#..###....#.#.#..
+				#.#..#.....####...####.#.##..##..
+				##...##..###.#....#####..##.###..
+				#..##..#....#.###...#.#####.##.##
+				.##...###...#..#..#.###.##.##.#..
+				#.#.#..#..#.#.####.#..###.#.#...#
+				#.##.##.#...####.##..#...#.#.##..
+				###.##.#####.##.########..#..#..#
+				..##.##..#.#.#..###.##.#####..###
+				.#..#..#.##...##...#......#.##.##
+				#.#.#.#...######.#....########.##
+				........#####.####.##...#...##.#.
+				#######.#.........###.###.#.#....
+				#.....#.##.#...##.#.##.##...###..
+				#.###.#......#....###########.#..
+				#.###.#.###.##.#.....#.#...#.#..#
+				#.###.#.#..###.##...#.#...##..#..
+				#.....#.....###.##.##.###.###...#
+				#######.#.##.#..####.#.#.#..#.#..
+			`,
+		},
+	}
+
+	for _, tt := range tests {
+		modules, err := ParseBitMatrix(tt.modules)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		for angle := 0; angle < 360; angle += 5 {
+			t.Run(fmt.Sprint(tt.name, " ", angle), func(t *testing.T) {
+				results, err := DefaultDecoder{}.DecodeResults(rotatedImage(modules, 4, float64(angle)))
+				if assert.NoError(t, err) && assert.Len(t, results, 1) {
+					assert.Equal(t, tt.want, string(results[0].Data))
+				}
+			})
+		}
+	}
+}
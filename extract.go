@@ -9,21 +9,77 @@ import (
 var errUnableToFindPattern = errors.New("unable to find alignment pattern")
 
 type QRExtractor interface {
-	Extract(*image.Gray, QRLocation)
+	Extract(*image.Gray, QRLocation) (QRData, error)
 }
 
-type QRExtract struct{}
+var _ QRExtractor = QRExtract{}
 
-func (QRExtract) Extract(prepared *image.Gray, loc QRLocation) (QRData, error) {
+type QRExtract struct {
+	// Gray enables soft-decision sampling. Every module is then sampled from a small
+	// neighbourhood in this grayscale version of the source image, as made by Grayscale,
+	// instead of a single pixel in the prepared image, which also fills QRData.Confidence.
+	Gray *image.Gray
+}
+
+func (e QRExtract) Extract(prepared *image.Gray, loc QRLocation) (QRData, error) {
+	data, _, err := e.extract(prepared, loc)
+	return data, err
+}
+
+// qrSampling is where a QR Code was sampled
+type qrSampling struct {
+	// grid is the centre of every module, see perspective.grid
+	grid []Point
+
+	// alignment is the centre of the alignment pattern found, nil when none was
+	alignment *Point
+}
+
+// extract is Extract, along with where it sampled the symbol
+func (e QRExtract) extract(prepared *image.Gray, loc QRLocation) (QRData, qrSampling, error) {
 	size := 17 + loc.Version*4
 	p, err := determinePerspective(prepared, loc.Version, size, loc)
 	if err != nil {
-		return QRData{}, err
+		return QRData{}, qrSampling{}, err
 	}
 
-	start := loc.TopLeft.Sub(p.dy.Mul(3)).Sub(p.ddy.Mul(3))
+	grid := p.grid(loc, size)
+	sampling := qrSampling{grid: grid, alignment: p.alignment}
+
+	if e.Gray != nil {
+		modules, confidence := softSample(e.Gray, grid, int(size), int(size), loc.ModuleSize)
+		return QRData{Modules: modules, Version: loc.Version, Side: size, Confidence: confidence}, sampling, nil
+	}
+
+	return QRData{Modules: sampleGrid(prepared, grid, int(size), int(size)), Version: loc.Version, Side: size}, sampling, nil
+}
+
+// sampleGrid reads every module in grid, width x height in row major order, from the pixel at its centre
+func sampleGrid(prepared *image.Gray, grid []Point, width, height int) BitMatrix {
+	modules := NewBitMatrix(width, height)
+	for i, module := range grid {
+		pixel := prepared.GrayAt(int(math.Round(module.X)), int(math.Round(module.Y))).Y
+		modules.Set(i%width, i/width, pixel == 0)
+	}
+
+	return modules
+}
+
+// grid returns the centre of every module in pixels, in row major order
+func (p perspective) grid(loc QRLocation, size uint32) []Point {
+	grid := make([]Point, 0, size*size)
+
+	if p.corners != nil {
+		for y := uint32(0); y < size; y++ {
+			for x := uint32(0); x < size; x++ {
+				grid = append(grid, p.corners.Apply(Point{float64(x) + 0.5, float64(y) + 0.5}))
+			}
+		}
 
-	data := make([]byte, 0, size*size)
+		return grid
+	}
+
+	start := loc.TopLeft.Sub(p.dy.Mul(3)).Sub(p.ddy.Mul(3))
 
 	dx := p.dx.Sub(p.ddx.Mul(3))
 	dy := p.dy.Sub(p.ddy.Mul(3))
@@ -32,9 +88,7 @@ func (QRExtract) Extract(prepared *image.Gray, loc QRLocation) (QRData, error) {
 		line := start.Sub(dx.Mul(3))
 
 		for _i := uint32(0); _i < size; _i++ {
-			x, y := line.X, line.Y
-			pixel := prepared.GrayAt(int(math.Round(x)), int(math.Round(y))).Y
-			data = append(data, pixel)
+			grid = append(grid, line)
 			line = line.Add(dx)
 		}
 		dx = dx.Add(p.ddx)
@@ -42,13 +96,20 @@ func (QRExtract) Extract(prepared *image.Gray, loc QRLocation) (QRData, error) {
 		dy = dy.Add(p.ddy)
 	}
 
-	return QRData{Data: data, Version: loc.Version, Side: 4*loc.Version + 17}, nil
+	return grid
 }
 
 // size := 17 + loc.Version*4
 
 type perspective struct {
 	dx, ddx, dy, ddy Point
+
+	// corners maps module coordinates to pixels when the perspective is determined
+	// from the estimated bottom right corner instead of dx, ddx, dy and ddy
+	corners *transform
+
+	// alignment is the centre of the alignment pattern the perspective is determined from
+	alignment *Point
 }
 
 func determinePerspective(
@@ -63,7 +124,16 @@ func determinePerspective(
 	dy := loc.BottomLeft.Sub(loc.TopLeft)
 	dy = dy.Div(float64(size) - 7)
 
+	// version 1 has no alignment pattern, the corners estimated from the finders only replace
+	// the affine grid when the symbol is seen in perspective, half a module or more off it
 	if version == 1 {
+		if corners, ok := estimateCorners(prepared, size, loc, dx, dy); ok {
+			bottomRight := corners.Apply(Point{float64(size), float64(size)})
+			if distance(bottomRight, affineCorner(loc, dx, dy)) >= distance(Point{}, dx)/2 {
+				return perspective{dx: dx, dy: dy, corners: &corners}, nil
+			}
+		}
+
 		return perspective{
 			dx: dx,
 			dy: dy,
@@ -126,6 +196,10 @@ distLoop:
 	}
 
 	if !found {
+		if corners, ok := estimateCorners(prepared, size, loc, dx, dy); ok {
+			return perspective{dx: dx, dy: dy, corners: &corners}, nil
+		}
+
 		return perspective{}, errUnableToFindPattern
 	}
 
@@ -188,7 +262,7 @@ distLoop:
 
 	delta = delta.Div(float64((size - 10) * (size - 10)))
 
-	return perspective{dx, delta, dy, Point{}}, nil
+	return perspective{dx: dx, ddx: delta, dy: dy, alignment: &estAlignment}, nil
 }
 
 func isAlignment(prepared *image.Gray, p, dx, dy Point, scale float64) bool {
@@ -256,3 +330,138 @@ func isAlignment(prepared *image.Gray, p, dx, dy Point, scale float64) bool {
 
 	return prepared.GrayAt(int(math.Round(p.X)), int(math.Round(p.Y))).Y == 0
 }
+
+// estimateCorners determines the perspective from the finder patterns alone.
+// The fourth corner is where the line along the right edge of the top right finder
+// crosses the line along the bottom edge of the bottom left finder.
+func estimateCorners(prepared *image.Gray, size uint32, loc QRLocation, dx, dy Point) (transform, bool) {
+	rightEdge, ok := fitFinderEdge(prepared, loc.TopRight, dx, dy)
+	if !ok {
+		return transform{}, false
+	}
+
+	bottomEdge, ok := fitFinderEdge(prepared, loc.BottomLeft, dy, dx)
+	if !ok {
+		return transform{}, false
+	}
+
+	bottomRight, ok := rightEdge.intersect(bottomEdge)
+	if !ok {
+		return transform{}, false
+	}
+
+	// the affine estimate is never far off, unless the edges are not edges at all
+	if distance(bottomRight, affineCorner(loc, dx, dy)) > float64(size)/4*distance(Point{}, dx) {
+		return transform{}, false
+	}
+
+	var (
+		side   = float64(size)
+		finder = side - 3.5
+	)
+
+	return newTransform(
+		[...]Point{{3.5, 3.5}, {finder, 3.5}, {side, side}, {3.5, finder}},
+		[...]Point{loc.TopLeft, loc.TopRight, bottomRight, loc.BottomLeft},
+	), true
+}
+
+// affineCorner is the bottom right corner of the symbol as if it were a parallelogram
+func affineCorner(loc QRLocation, dx, dy Point) Point {
+	return loc.TopRight.Add(loc.BottomLeft).Sub(loc.TopLeft).Add(dx.Mul(3.5)).Add(dy.Mul(3.5))
+}
+
+type line struct {
+	// a point on the line and its direction
+	p, d Point
+}
+
+func (l line) intersect(other line) (Point, bool) {
+	cross := l.d.X*other.d.Y - l.d.Y*other.d.X
+	if math.Abs(cross) < 1e-9 {
+		return Point{}, false
+	}
+
+	diff := other.p.Sub(l.p)
+	t := (diff.X*other.d.Y - diff.Y*other.d.X) / cross
+
+	return l.p.Add(l.d.Mul(t)), true
+}
+
+// fitFinderEdge fits a line to the outer edge of a finder pattern.
+// The edge is found by walking outwards (along out) from the outer dark ring,
+// once for every half module along the edge (along along).
+func fitFinderEdge(prepared *image.Gray, finder, out, along Point) (line, bool) {
+	starts := []Point{}
+	for t := -3.0; t <= 3; t += 0.5 {
+		starts = append(starts, finder.Add(out.Mul(3)).Add(along.Mul(t)))
+	}
+
+	return fitEdge(prepared, starts, out, 5)
+}
+
+// fitEdge fits a line to an edge, found by walking outwards (along out, a module long)
+// from every dark start to the first light pixel. At least minPoints must reach the edge.
+func fitEdge(prepared *image.Gray, starts []Point, out Point, minPoints int) (line, bool) {
+	outLen := math.Sqrt(out.X*out.X + out.Y*out.Y)
+	if outLen < 1 {
+		outLen = 1
+	}
+
+	step := out.Div(outLen * 2) // half a pixel
+	maxSteps := int(math.Ceil(outLen * 4))
+
+	points := []Point{}
+
+	for _, p := range starts {
+		if !isDark(prepared, p) {
+			continue
+		}
+
+		for i := 0; i < maxSteps; i++ {
+			next := p.Add(step)
+			if !isDark(prepared, next) {
+				points = append(points, p.Add(next).Div(2))
+				break
+			}
+			p = next
+		}
+	}
+
+	if len(points) < minPoints {
+		return line{}, false
+	}
+
+	return fitLine(points), true
+}
+
+// isDark reports whether the pixel nearest to p is dark, pixels outside the image are light
+func isDark(prepared *image.Gray, p Point) bool {
+	x, y := int(math.Round(p.X)), int(math.Round(p.Y))
+	if !(image.Point{x, y}.In(prepared.Rect)) {
+		return false
+	}
+	return prepared.GrayAt(x, y).Y == 0
+}
+
+// fitLine is a total least squares fit, the line goes through the centroid of points
+// along their principal direction
+func fitLine(points []Point) line {
+	centroid := Point{}
+	for _, p := range points {
+		centroid = centroid.Add(p)
+	}
+	centroid = centroid.Div(float64(len(points)))
+
+	var sxx, sxy, syy float64
+	for _, p := range points {
+		d := p.Sub(centroid)
+		sxx += d.X * d.X
+		sxy += d.X * d.Y
+		syy += d.Y * d.Y
+	}
+
+	angle := math.Atan2(2*sxy, sxx-syy) / 2
+
+	return line{centroid, Point{math.Cos(angle), math.Sin(angle)}}
+}
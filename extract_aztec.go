@@ -0,0 +1,35 @@
+package ar8t
+
+import "image"
+
+// AztecExtract samples the modules of an Aztec Code, see QRExtract
+type AztecExtract struct {
+	// Gray enables soft-decision sampling, as in QRExtract
+	Gray *image.Gray
+}
+
+func (e AztecExtract) Extract(prepared *image.Gray, loc AztecLocation) (QRData, error) {
+	size, err := AztecSize(loc.Compact, loc.Layers)
+	if err != nil {
+		return QRData{}, err
+	}
+
+	t := newTransform(
+		[...]Point{{0, 0}, {float64(size), 0}, {float64(size), float64(size)}, {0, float64(size)}},
+		[...]Point{loc.TopLeft, loc.TopRight, loc.BottomRight, loc.BottomLeft},
+	)
+
+	grid := make([]Point, size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			grid[y*size+x] = t.Apply(Point{float64(x) + 0.5, float64(y) + 0.5})
+		}
+	}
+
+	if e.Gray != nil {
+		modules, confidence := softSample(e.Gray, grid, size, size, loc.ModuleSize)
+		return QRData{Modules: modules, Confidence: confidence}, nil
+	}
+
+	return QRData{Modules: sampleGrid(prepared, grid, size, size)}, nil
+}
@@ -0,0 +1,35 @@
+package ar8t
+
+import "image"
+
+// DataMatrixExtract samples the modules of a Data Matrix symbol, see QRExtract
+type DataMatrixExtract struct {
+	// Gray enables soft-decision sampling, as in QRExtract
+	Gray *image.Gray
+}
+
+func (e DataMatrixExtract) Extract(prepared *image.Gray, loc DataMatrixLocation) (QRData, error) {
+	width, height, err := DataMatrixSize(loc.Version)
+	if err != nil {
+		return QRData{}, err
+	}
+
+	t := newTransform(
+		[...]Point{{0, 0}, {float64(width), 0}, {float64(width), float64(height)}, {0, float64(height)}},
+		[...]Point{loc.TopLeft, loc.TopRight, loc.BottomRight, loc.BottomLeft},
+	)
+
+	grid := make([]Point, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			grid[y*width+x] = t.Apply(Point{float64(x) + 0.5, float64(y) + 0.5})
+		}
+	}
+
+	if e.Gray != nil {
+		modules, confidence := softSample(e.Gray, grid, width, height, loc.ModuleSize)
+		return QRData{Modules: modules, Version: loc.Version, Confidence: confidence}, nil
+	}
+
+	return QRData{Modules: sampleGrid(prepared, grid, width, height), Version: loc.Version}, nil
+}
@@ -0,0 +1,32 @@
+package ar8t
+
+import "image"
+
+// MicroQRExtract samples the modules of a Micro QR Code, see QRExtract
+type MicroQRExtract struct {
+	// Gray enables soft-decision sampling, as in QRExtract
+	Gray *image.Gray
+}
+
+func (e MicroQRExtract) Extract(prepared *image.Gray, loc MicroQRLocation) (QRData, error) {
+	if loc.Version < 1 || loc.Version > 4 {
+		return QRData{}, errUnsupportedVersion
+	}
+
+	size := 9 + 2*loc.Version
+
+	// the centre of the finder is the centre of module 3, 3
+	grid := make([]Point, 0, size*size)
+	for y := uint32(0); y < size; y++ {
+		for x := uint32(0); x < size; x++ {
+			grid = append(grid, loc.Finder.Add(loc.DX.Mul(float64(x)-3)).Add(loc.DY.Mul(float64(y)-3)))
+		}
+	}
+
+	if e.Gray != nil {
+		modules, confidence := softSample(e.Gray, grid, int(size), int(size), loc.ModuleSize)
+		return QRData{Modules: modules, Version: loc.Version, Side: size, Confidence: confidence}, nil
+	}
+
+	return QRData{Modules: sampleGrid(prepared, grid, int(size), int(size)), Version: loc.Version, Side: size}, nil
+}
@@ -0,0 +1,249 @@
+package ar8t
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+var (
+	errPDF417Rows = errors.New("unable to read the row indicators of a PDF417 symbol")
+	errPDF417Size = errors.New("not the size of a PDF417 symbol")
+)
+
+// PDF417Extract reads the codewords of a PDF417 symbol
+//
+// Lines are sampled across the symbol from the start pattern to the stop pattern, a pixel apart.
+// Along each line the codewords follow the start pattern 17 modules apart, their bars and spaces
+// telling their cluster, which is the row modulo 3. The row indicators on both sides of a row
+// give its number, along with the number of rows and columns and the error correction level.
+// Every codeword is the one read along most of the lines through its row.
+type PDF417Extract struct{}
+
+// PDF417Codewords are the codewords read from a PDF417 symbol
+type PDF417Codewords struct {
+	Rows, Columns int
+	ECLevel       int //0 .. 8, the symbol has 2^(ECLevel+1) error correction codewords
+
+	// Codewords are the codewords of every row, without the row indicators
+	Codewords []int
+
+	// Erasures are the indices of the codewords not read along any line
+	Erasures []int
+}
+
+// pdf417Read is a codeword read along a line, its column counting the left row indicator
+type pdf417Read struct {
+	column, cluster, value int
+}
+
+func (PDF417Extract) Extract(prepared *image.Gray, loc PDF417Location) (PDF417Codewords, error) {
+	t := newTransform(
+		[...]Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		[...]Point{loc.TopLeft, loc.TopRight, loc.BottomRight, loc.BottomLeft},
+	)
+
+	width := (distance(loc.TopLeft, loc.TopRight) + distance(loc.BottomLeft, loc.BottomRight)) / 2
+	height := (distance(loc.TopLeft, loc.BottomLeft) + distance(loc.TopRight, loc.BottomRight)) / 2
+
+	// samples a quarter of a pixel apart along the lines, reaching into the quiet zones,
+	// so that runs of whole pixels are measured the same wherever they are
+	const step = 0.25
+	margin := 2 * loc.ModuleSize / width
+	samples := int((1 + 2*margin) * width / step)
+
+	var (
+		// the votes for the codewords at each row and column
+		votes = map[[2]int]map[int]int{}
+
+		// the votes for the values the row indicators hold: the number of rows divided by 3,
+		// the error correction level and the rest of the number of rows, and the number of columns
+		indicators = [3]map[int]int{{}, {}, {}}
+
+		previous = -1
+	)
+
+	for i, lines := 0, max(int(height), 1); i < lines; i++ {
+		y := (float64(i) + 0.5) / float64(lines)
+		runs, edges := runsOf(samples, func(j int) bool {
+			x := -margin + (float64(j)+0.5)*(1+2*margin)/float64(samples)
+			return isDark(prepared, t.Apply(Point{x, y}))
+		})
+
+		reads, stop := readPDF417Line(runs, edges, loc.ModuleSize/step)
+		cluster, ok := pdf417Cluster(reads)
+		if !ok {
+			continue
+		}
+
+		// the row is the same on both sides, the left and right row indicators
+		// hold different values in the same cluster
+		row := -1
+		for _, read := range reads {
+			if read.cluster != cluster || (read.column != 0 && read.column != stop-1) {
+				continue
+			}
+
+			r := 3*(read.value/30) + cluster
+			if row != -1 && r != row {
+				row = -2
+				break
+			}
+			row = r
+
+			kind := cluster
+			if read.column != 0 {
+				kind = (cluster + 2) % 3
+			}
+			indicators[kind][read.value%30]++
+		}
+
+		// lines crossing a row without reading its row indicators are in the row of the line before
+		if row == -1 && previous != -1 && previous%3 == cluster {
+			row = previous
+		}
+		if row < 0 {
+			continue
+		}
+		previous = row
+
+		for _, read := range reads {
+			if read.cluster == cluster && read.column != 0 {
+				key := [2]int{row, read.column}
+				if votes[key] == nil {
+					votes[key] = map[int]int{}
+				}
+				votes[key][read.value]++
+			}
+		}
+	}
+
+	rowsHigh, ok1 := mostVoted(indicators[0])
+	rowsLow, ok2 := mostVoted(indicators[1])
+	columns, ok3 := mostVoted(indicators[2])
+	if !ok1 || !ok2 || !ok3 {
+		return PDF417Codewords{}, errPDF417Rows
+	}
+
+	res := PDF417Codewords{
+		Rows:    3*rowsHigh + rowsLow%3 + 1,
+		Columns: columns + 1,
+		ECLevel: rowsLow / 3,
+	}
+
+	if res.Rows < 3 || res.Rows > 90 || res.ECLevel > 8 || res.Rows*res.Columns > 928 ||
+		2<<res.ECLevel >= res.Rows*res.Columns {
+		return PDF417Codewords{}, errPDF417Size
+	}
+
+	for row := 0; row < res.Rows; row++ {
+		for column := 1; column <= res.Columns; column++ {
+			value, ok := mostVoted(votes[[2]int{row, column}])
+			if !ok {
+				res.Erasures = append(res.Erasures, len(res.Codewords))
+			}
+			res.Codewords = append(res.Codewords, value)
+		}
+	}
+
+	return res, nil
+}
+
+// readPDF417Line reads the codewords along a line from the start pattern, module being
+// the width of a module in samples. Every codeword starts 17 modules after the one before,
+// at the nearest dark run, so codewords that can't be read don't throw the others out.
+// It also returns the column of the stop pattern, or -1 when it isn't found.
+func readPDF417Line(runs, edges []int, module float64) ([]pdf417Read, int) {
+	start := -1
+	for i := 1; i+8 < len(runs) && float64(edges[i]) < 5*module; i += 2 {
+		if runVariance(runs[i:], pdf417Start) <= pdf417MaxVariance {
+			start = i
+			break
+		}
+	}
+
+	if start == -1 {
+		return nil, -1
+	}
+
+	reads := []pdf417Read{}
+	module = float64(edges[start+8]-edges[start]) / 17
+	next, i := float64(edges[start+8]), start+8
+
+	// at most 30 columns between the row indicators, then the stop pattern
+	for column := 0; column < 33; column++ {
+		for i+2 < len(runs) && math.Abs(float64(edges[i+2])-next) < math.Abs(float64(edges[i])-next) {
+			i += 2
+		}
+
+		// every codeword is followed by at least the 9 runs of the stop pattern
+		if i+9 > len(runs) {
+			break
+		}
+
+		if runVariance(runs[i:], pdf417Stop) <= pdf417MaxVariance {
+			return reads, column
+		}
+
+		width := float64(edges[i+8] - edges[i])
+		if math.Abs(float64(edges[i])-next) <= 2*module && math.Abs(width-17*module) <= 2*module {
+			if codeword, ok := pdf417Codewords[pdf417Bits(runs[i:i+8], width)]; ok {
+				reads = append(reads, pdf417Read{column, codeword / 929, codeword % 929})
+				module, next = width/17, float64(edges[i+8])
+				continue
+			}
+		}
+
+		next += 17 * module
+	}
+
+	return reads, -1
+}
+
+// pdf417Bits samples the 17 modules of the runs of a codeword, width samples wide
+func pdf417Bits(runs []int, width float64) uint32 {
+	bits, run, end := uint32(0), 0, float64(runs[0])
+	for k := 0; k < 17; k++ {
+		x := (float64(k) + 0.5) * width / 17
+		for x >= end && run+1 < len(runs) {
+			run++
+			end += float64(runs[run])
+		}
+
+		bits <<= 1
+		if run%2 == 0 {
+			bits |= 1
+		}
+	}
+
+	return bits
+}
+
+// pdf417Cluster is the cluster most codewords read along a line are in, at least two of them
+func pdf417Cluster(reads []pdf417Read) (int, bool) {
+	var counts [3]int
+	for _, read := range reads {
+		counts[read.cluster]++
+	}
+
+	best := 0
+	for cluster, count := range counts {
+		if count > counts[best] {
+			best = cluster
+		}
+	}
+
+	return best, counts[best] >= 2 && 2*counts[best] > len(reads)
+}
+
+// mostVoted is the value with the most votes, the lowest one of those tied
+func mostVoted(votes map[int]int) (int, bool) {
+	best, bestVotes := 0, 0
+	for value, count := range votes {
+		if count > bestVotes || (count == bestVotes && value < best) {
+			best, bestVotes = value, count
+		}
+	}
+
+	return best, bestVotes > 0
+}
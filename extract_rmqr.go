@@ -0,0 +1,52 @@
+package ar8t
+
+import "image"
+
+// RMQRExtract samples the modules of an rMQR Code, see QRExtract
+type RMQRExtract struct {
+	// Gray enables soft-decision sampling, as in QRExtract
+	Gray *image.Gray
+}
+
+func (e RMQRExtract) Extract(prepared *image.Gray, loc RMQRLocation) (QRData, error) {
+	width, height, err := RMQRSize(loc.Version)
+	if err != nil {
+		return QRData{}, err
+	}
+
+	columns := loc.Columns
+	if len(columns) != width+1 {
+		columns = make([]float64, width+1)
+		for x := range columns {
+			columns[x] = float64(x) / float64(width) * distance(loc.TopLeft, loc.TopRight)
+		}
+	}
+
+	var (
+		along = loc.TopRight.Sub(loc.TopLeft).Div(distance(loc.TopLeft, loc.TopRight))
+		left  = loc.BottomLeft.Sub(loc.TopLeft)
+		right = loc.BottomRight.Sub(loc.TopRight)
+	)
+
+	// every column runs from the top edge towards the bottom edge,
+	// turning from the left edge to the right edge as it goes
+	grid := make([]Point, width*height)
+	for x := 0; x < width; x++ {
+		s := (columns[x] + columns[x+1]) / 2
+		f := s / columns[width]
+
+		top := loc.TopLeft.Add(along.Mul(s))
+		down := left.Mul(1 - f).Add(right.Mul(f))
+
+		for y := 0; y < height; y++ {
+			grid[y*width+x] = top.Add(down.Mul((float64(y) + 0.5) / float64(height)))
+		}
+	}
+
+	if e.Gray != nil {
+		modules, confidence := softSample(e.Gray, grid, width, height, loc.ModuleSize)
+		return QRData{Modules: modules, Version: loc.Version, Confidence: confidence}, nil
+	}
+
+	return QRData{Modules: sampleGrid(prepared, grid, width, height), Version: loc.Version}, nil
+}
@@ -0,0 +1,83 @@
+package ar8t
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/exp/slices"
+)
+
+// softSample reads every module in grid, width x height in row major order, from a grayscale image.
+//
+// Each module is the mean of a 3x3 neighbourhood around its centre, compared against
+// a threshold halfway between the darkest and lightest modules around it.
+// The further a module is from its threshold, the higher its confidence.
+func softSample(gray *image.Gray, grid []Point, width, height int, moduleSize float64) (BitMatrix, []float64) {
+	r := moduleSize / 4
+
+	values := make([]float64, len(grid))
+	for i, module := range grid {
+		sum := 0.0
+		for dy := -1.0; dy <= 1; dy++ {
+			for dx := -1.0; dx <= 1; dx++ {
+				sum += bilinear(gray, Point{module.X + dx*r, module.Y + dy*r})
+			}
+		}
+		values[i] = sum / 9
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	lo, hi := sorted[len(sorted)/20], sorted[len(sorted)-1-len(sorted)/20]
+	contrast := max(hi-lo, 1)
+
+	modules := NewBitMatrix(width, height)
+	confidence := make([]float64, len(values))
+
+	const window = 3
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			localMin, localMax := math.Inf(1), math.Inf(-1)
+			for wy := max(0, y-window); wy <= min(height-1, y+window); wy++ {
+				for wx := max(0, x-window); wx <= min(width-1, x+window); wx++ {
+					localMin = min(localMin, values[wy*width+wx])
+					localMax = max(localMax, values[wy*width+wx])
+				}
+			}
+
+			// a window of a single colour says nothing about the threshold
+			threshold := (lo + hi) / 2
+			if localMax-localMin >= contrast/2 {
+				threshold = (localMin + localMax) / 2
+			}
+
+			i := y*width + x
+			modules.Set(x, y, values[i] < threshold)
+
+			confidence[i] = min(1, math.Abs(values[i]-threshold)/(contrast/2))
+		}
+	}
+
+	return modules, confidence
+}
+
+// bilinear interpolates the gray value at p, pixels outside the image are white
+func bilinear(gray *image.Gray, p Point) float64 {
+	x0, y0 := math.Floor(p.X), math.Floor(p.Y)
+	fx, fy := p.X-x0, p.Y-y0
+
+	at := func(x, y int) float64 {
+		if !(image.Point{x, y}.In(gray.Rect)) {
+			return 255
+		}
+		return float64(gray.GrayAt(x, y).Y)
+	}
+
+	x, y := int(x0), int(y0)
+
+	return at(x, y)*(1-fx)*(1-fy) +
+		at(x+1, y)*fx*(1-fy) +
+		at(x, y+1)*(1-fx)*fy +
+		at(x+1, y+1)*fx*fy
+}
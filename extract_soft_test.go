@@ -0,0 +1,49 @@
+package ar8t
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_softSample(t *testing.T) {
+	const side, moduleSize = 9, 6
+
+	// a checkerboard with a grey module in the middle, as a blurry module looks
+	gray := image.NewGray(image.Rect(0, 0, side*moduleSize, side*moduleSize))
+	want := NewBitMatrix(side, side)
+	grid := []Point{}
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			dark := (x+y)%2 == 0
+			want.Set(x, y, dark)
+			grid = append(grid, Point{(float64(x) + 0.5) * moduleSize, (float64(y) + 0.5) * moduleSize})
+
+			value := uint8(230)
+			switch {
+			case x == side/2 && y == side/2:
+				value = 120
+			case dark:
+				value = 30
+			}
+			for py := y * moduleSize; py < (y+1)*moduleSize; py++ {
+				for px := x * moduleSize; px < (x+1)*moduleSize; px++ {
+					gray.SetGray(px, py, color.Gray{value})
+				}
+			}
+		}
+	}
+
+	modules, confidence := softSample(gray, grid, side, side, moduleSize)
+	assert.True(t, modules.Equal(want), "\n%v", modules)
+
+	for i, c := range confidence {
+		if i == side/2*side+side/2 {
+			assert.Less(t, c, 0.2, "grey module")
+			continue
+		}
+		assert.Greater(t, c, 0.9, "module %d", i)
+	}
+}
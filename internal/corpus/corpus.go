@@ -0,0 +1,148 @@
+// Package corpus renders QR Codes and distorts them the ways photos of them are, to test how well
+// they are decoded. It also draws an EAN-13 barcode, for the tests of the commands.
+//
+// The symbols are stored as their modules, made by an encoder other than ar8t,
+// along with the text they hold. Every distortion is deterministic for a given seed.
+package corpus
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"path"
+	"strings"
+)
+
+//go:embed symbols/*.txt
+var symbolFiles embed.FS
+
+var errInvalidSymbol = errors.New("invalid stored symbol")
+
+// QuietZone is the width of the light margin around rendered symbols, in modules
+const QuietZone = 4
+
+// Symbol is a stored QR Code
+type Symbol struct {
+	// Name is the version and error correction level of the symbol, such as version4-Q
+	Name string
+
+	// Text is what the symbol holds
+	Text string
+
+	// Modules are the rows of the symbol, true where they are dark
+	Modules [][]bool
+}
+
+// Symbols are the stored symbols, ordered by name. A stored symbol is a text file of the text
+// it holds on the first line, then a line of # and . for every row of modules.
+func Symbols() ([]Symbol, error) {
+	files, err := symbolFiles.ReadDir("symbols")
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := []Symbol{}
+	for _, file := range files {
+		data, err := symbolFiles.ReadFile(path.Join("symbols", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		symbol, err := parseSymbol(strings.TrimSuffix(file.Name(), ".txt"), string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, nil
+}
+
+func parseSymbol(name, data string) (Symbol, error) {
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
+	if len(lines) < 2 {
+		return Symbol{}, errInvalidSymbol
+	}
+
+	symbol := Symbol{Name: name, Text: lines[0]}
+	for _, line := range lines[1:] {
+		if len(line) != len(lines)-1 {
+			return Symbol{}, errInvalidSymbol
+		}
+
+		row := make([]bool, len(line))
+		for i, c := range line {
+			switch c {
+			case '#':
+				row[i] = true
+			case '.':
+			default:
+				return Symbol{}, errInvalidSymbol
+			}
+		}
+		symbol.Modules = append(symbol.Modules, row)
+	}
+
+	return symbol, nil
+}
+
+// Render draws the symbol black on white with modules of moduleSize pixels, in a quiet zone
+func (s Symbol) Render(moduleSize int) *image.Gray {
+	side := (len(s.Modules) + 2*QuietZone) * moduleSize
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+
+	for y, row := range s.Modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+
+			module := image.Rect(x+QuietZone, y+QuietZone, x+QuietZone+1, y+QuietZone+1)
+			module.Min, module.Max = module.Min.Mul(moduleSize), module.Max.Mul(moduleSize)
+			draw.Draw(img, module, image.Black, image.Point{}, draw.Src)
+		}
+	}
+
+	return img
+}
+
+// Distortion changes an image, drawing on rng for anything random
+type Distortion func(img *image.Gray, rng *rand.Rand) *image.Gray
+
+// Apply applies distortions to img in order, with a source of randomness seeded with seed.
+// img is left as it is.
+func Apply(img *image.Gray, seed int64, distortions ...Distortion) *image.Gray {
+	rng := rand.New(rand.NewSource(seed))
+
+	distorted := toGray(img)
+	for _, distortion := range distortions {
+		distorted = distortion(distorted, rng)
+	}
+
+	return distorted
+}
+
+// toGray copies img into a new gray image with its origin at 0, 0
+func toGray(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(gray, gray.Rect, img, bounds.Min, draw.Src)
+	return gray
+}
+
+func clamp(v float64) uint8 {
+	switch {
+	case v < 0:
+		return 0
+	case v > 255:
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+var white = color.Gray{255}
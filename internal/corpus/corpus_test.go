@@ -0,0 +1,74 @@
+package corpus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Symbols(t *testing.T) {
+	symbols, err := Symbols()
+	if !assert.NoError(t, err) || !assert.NotEmpty(t, symbols) {
+		return
+	}
+
+	for _, symbol := range symbols {
+		side := len(symbol.Modules)
+		assert.NotEmpty(t, symbol.Text, symbol.Name)
+		assert.True(t, strings.HasPrefix(symbol.Name, fmt.Sprintf("version%d-", (side-17)/4)), symbol.Name)
+
+		img := symbol.Render(3)
+		assert.Equal(t, 3*(side+2*QuietZone), img.Rect.Dx(), symbol.Name)
+
+		// the top left corner of the finder is dark, the quiet zone around it light
+		assert.Equal(t, uint8(0), img.GrayAt(3*QuietZone, 3*QuietZone).Y, symbol.Name)
+		assert.Equal(t, uint8(255), img.GrayAt(3*QuietZone-1, 3*QuietZone).Y, symbol.Name)
+	}
+}
+
+func Test_Apply(t *testing.T) {
+	symbols, err := Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+	img := symbols[0].Render(4)
+
+	tests := []struct {
+		name        string
+		distortions []Distortion
+	}{
+		{"none", nil},
+		{"perspective 0", []Distortion{Perspective(0)}},
+		{"rotate 0", []Distortion{Rotate(0)}},
+		{"noise 0", []Distortion{Noise(0)}},
+		{"illumination 0", []Distortion{Illumination(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, img.Pix, Apply(img, 1, tt.distortions...).Pix)
+		})
+	}
+
+	// the same seed distorts the same way
+	noisy := Apply(img, 7, Noise(30), Occlude(0.05))
+	assert.Equal(t, noisy.Pix, Apply(img, 7, Noise(30), Occlude(0.05)).Pix)
+	assert.NotEqual(t, noisy.Pix, Apply(img, 8, Noise(30), Occlude(0.05)).Pix)
+}
+
+func Test_homography(t *testing.T) {
+	quad := [4][2]float64{{10, 5}, {90, 0}, {100, 80}, {0, 100}}
+	m := squareToQuad(quad)
+
+	for i, corner := range [4][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}} {
+		x, y := m.apply(corner[0], corner[1])
+		assert.InDelta(t, quad[i][0], x, 1e-9)
+		assert.InDelta(t, quad[i][1], y, 1e-9)
+
+		u, v := m.inverse().apply(x, y)
+		assert.InDelta(t, corner[0], u, 1e-9)
+		assert.InDelta(t, corner[1], v, 1e-9)
+	}
+}
@@ -0,0 +1,190 @@
+package corpus
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"math"
+	"math/rand"
+
+	"github.com/disintegration/imaging"
+)
+
+// Rotate rotates images counter-clockwise by degrees, growing them to hold the rotated corners
+func Rotate(degrees float64) Distortion {
+	return func(img *image.Gray, _ *rand.Rand) *image.Gray {
+		return toGray(imaging.Rotate(img, degrees, white))
+	}
+}
+
+// Perspective tilts images away on a side picked at random, as if photographed from the opposite side.
+// The far edge shrinks by tilt of its length, 0 leaving images as they are.
+func Perspective(tilt float64) Distortion {
+	return func(img *image.Gray, rng *rand.Rand) *image.Gray {
+		w, h := float64(img.Rect.Dx()), float64(img.Rect.Dy())
+
+		// where the corners of the image go, clockwise from the top left,
+		// then turned so that the far edge is on the side picked
+		corners := [4][2]float64{{0, 0}, {w, 0}, {w, h}, {0, h}}
+		side := rng.Intn(4)
+		far, next := corners[side], corners[(side+1)%4]
+		inset := [2]float64{(next[0] - far[0]) * tilt / 2, (next[1] - far[1]) * tilt / 2}
+		corners[side] = [2]float64{far[0] + inset[0], far[1] + inset[1]}
+		corners[(side+1)%4] = [2]float64{next[0] - inset[0], next[1] - inset[1]}
+
+		toImage := squareToQuad(corners).inverse()
+
+		distorted := image.NewGray(img.Rect)
+		for y := 0; y < img.Rect.Dy(); y++ {
+			for x := 0; x < img.Rect.Dx(); x++ {
+				u, v := toImage.apply(float64(x)+0.5, float64(y)+0.5)
+				distorted.Pix[y*distorted.Stride+x] = bilinear(img, u*w-0.5, v*h-0.5)
+			}
+		}
+
+		return distorted
+	}
+}
+
+// Blur blurs images with a Gaussian of sigma pixels, as an image out of focus is
+func Blur(sigma float64) Distortion {
+	return func(img *image.Gray, _ *rand.Rand) *image.Gray {
+		return toGray(imaging.Blur(img, sigma))
+	}
+}
+
+// Noise adds Gaussian noise of sigma grey levels to every pixel
+func Noise(sigma float64) Distortion {
+	return func(img *image.Gray, rng *rand.Rand) *image.Gray {
+		noisy := image.NewGray(img.Rect)
+		for i, p := range img.Pix {
+			noisy.Pix[i] = clamp(float64(p) + rng.NormFloat64()*sigma)
+		}
+		return noisy
+	}
+}
+
+// JPEG compresses images as JPEG images of quality from 1 to 100
+func JPEG(quality int) Distortion {
+	return func(img *image.Gray, _ *rand.Rand) *image.Gray {
+		data := bytes.Buffer{}
+		if err := jpeg.Encode(&data, img, &jpeg.Options{Quality: quality}); err != nil {
+			panic(err)
+		}
+
+		compressed, err := jpeg.Decode(&data)
+		if err != nil {
+			panic(err)
+		}
+		return toGray(compressed)
+	}
+}
+
+// Illumination darkens images unevenly, as a light to one side does. Images get darker from one
+// corner to the opposite one, picked at random, where they are darkened by strength of their brightness.
+func Illumination(strength float64) Distortion {
+	return func(img *image.Gray, rng *rand.Rand) *image.Gray {
+		w, h := float64(img.Rect.Dx()), float64(img.Rect.Dy())
+		corner := rng.Intn(4)
+		flipX, flipY := corner&1 == 1, corner&2 == 2
+
+		lit := image.NewGray(img.Rect)
+		for y := 0; y < img.Rect.Dy(); y++ {
+			for x := 0; x < img.Rect.Dx(); x++ {
+				fx, fy := float64(x)/w, float64(y)/h
+				if flipX {
+					fx = 1 - fx
+				}
+				if flipY {
+					fy = 1 - fy
+				}
+
+				i := y*img.Stride + x
+				lit.Pix[i] = clamp(float64(img.Pix[i]) * (1 - strength*(fx+fy)/2))
+			}
+		}
+
+		return lit
+	}
+}
+
+// Occlude covers a square of fraction of the area of images in black, somewhere in their middle half
+// where the symbol is
+func Occlude(fraction float64) Distortion {
+	return func(img *image.Gray, rng *rand.Rand) *image.Gray {
+		w, h := img.Rect.Dx(), img.Rect.Dy()
+		side := int(math.Round(math.Sqrt(fraction * float64(w*h))))
+
+		centre := image.Pt(w/4+rng.Intn(w/2+1), h/4+rng.Intn(h/2+1))
+		cover := image.Rect(0, 0, side, side).Add(centre.Sub(image.Pt(side/2, side/2))).Intersect(img.Rect)
+
+		occluded := toGray(img)
+		for y := cover.Min.Y; y < cover.Max.Y; y++ {
+			for x := cover.Min.X; x < cover.Max.X; x++ {
+				occluded.Pix[y*occluded.Stride+x] = 0
+			}
+		}
+
+		return occluded
+	}
+}
+
+// bilinear interpolates img at x, y, white outside it
+func bilinear(img *image.Gray, x, y float64) uint8 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+
+	at := func(x, y int) float64 {
+		if !(image.Point{x, y}.In(img.Rect)) {
+			return 255
+		}
+		return float64(img.Pix[y*img.Stride+x])
+	}
+
+	ix, iy := int(x0), int(y0)
+	top := at(ix, iy)*(1-fx) + at(ix+1, iy)*fx
+	bottom := at(ix, iy+1)*(1-fx) + at(ix+1, iy+1)*fx
+	return clamp(top*(1-fy) + bottom*fy)
+}
+
+// homography is a projective transform of points as column vectors, row by row
+type homography [3][3]float64
+
+// squareToQuad maps the unit square onto a quadrilateral, its corners clockwise from the top left
+func squareToQuad(quad [4][2]float64) homography {
+	x0, y0 := quad[0][0], quad[0][1]
+	x1, y1 := quad[1][0], quad[1][1]
+	x2, y2 := quad[2][0], quad[2][1]
+	x3, y3 := quad[3][0], quad[3][1]
+
+	dx1, dx2, dx3 := x1-x2, x3-x2, x0-x1+x2-x3
+	dy1, dy2, dy3 := y1-y2, y3-y2, y0-y1+y2-y3
+
+	den := dx1*dy2 - dx2*dy1
+	g := (dx3*dy2 - dx2*dy3) / den
+	h := (dx1*dy3 - dx3*dy1) / den
+
+	return homography{
+		{x1 - x0 + g*x1, x3 - x0 + h*x3, x0},
+		{y1 - y0 + g*y1, y3 - y0 + h*y3, y0},
+		{g, h, 1},
+	}
+}
+
+func (m homography) apply(x, y float64) (float64, float64) {
+	w := m[2][0]*x + m[2][1]*y + m[2][2]
+	return (m[0][0]*x + m[0][1]*y + m[0][2]) / w, (m[1][0]*x + m[1][1]*y + m[1][2]) / w
+}
+
+// inverse is the adjugate of m, which is its inverse up to a scale that doesn't change the points
+func (m homography) inverse() homography {
+	var inv homography
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			a, b := (j+1)%3, (j+2)%3
+			c, d := (i+1)%3, (i+2)%3
+			inv[i][j] = m[a][c]*m[b][d] - m[a][d]*m[b][c]
+		}
+	}
+	return inv
+}
@@ -0,0 +1,32 @@
+package corpus
+
+import (
+	"image"
+	"image/draw"
+)
+
+// EAN13Text is what EAN13 holds
+const EAN13Text = "4006381333931"
+
+// ean13Modules are the modules of EAN13Text, # being a bar
+const ean13Modules = "#.#...##.#.#..###.#.####.####.#...#..#.##..##.#.#.#....#.#....#.#....#.###.#..#....#.##..##.#.#"
+
+// EAN13 draws the EAN-13 barcode of EAN13Text black on white, 3 pixels a module and 60 pixels high,
+// in a quiet zone of 12 modules
+func EAN13() *image.Gray {
+	const moduleSize, quiet, height = 3, 12 * 3, 60
+
+	img := image.NewGray(image.Rect(0, 0, moduleSize*len(ean13Modules)+2*quiet, height+2*quiet))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+
+	for i, m := range ean13Modules {
+		if m != '#' {
+			continue
+		}
+
+		bar := image.Rect(quiet+i*moduleSize, quiet, quiet+(i+1)*moduleSize, quiet+height)
+		draw.Draw(img, bar, image.Black, image.Point{}, draw.Src)
+	}
+
+	return img
+}
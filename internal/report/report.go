@@ -0,0 +1,66 @@
+// Package report is what the ar8t commands tell about the symbols they find, in JSON
+package report
+
+import (
+	"encoding/base64"
+	"math"
+	"unicode/utf8"
+
+	"github.com/mrg0lden/ar8t"
+)
+
+// Symbol is a symbol found in an image, see the json format of the ar8t command
+type Symbol struct {
+	Symbology       string       `json:"symbology"`
+	Decoded         bool         `json:"decoded"`
+	DataBase64      *string      `json:"data_base64,omitempty"`
+	DataUTF8        *string      `json:"data_utf8,omitempty"`
+	Corners         [][2]float64 `json:"corners"`
+	Version         *uint32      `json:"version,omitempty"`
+	ECLevel         string       `json:"ec_level,omitempty"`
+	Mask            *int         `json:"mask,omitempty"`
+	CorrectedErrors []int        `json:"corrected_errors,omitempty"`
+	Error           string       `json:"error,omitempty"`
+}
+
+// NewSymbol tells about a result of DefaultDecoder.DecodeAll
+func NewSymbol(result ar8t.Result) Symbol {
+	s := Symbol{
+		Symbology: result.Symbology.String(),
+		Decoded:   result.Err == nil,
+		Corners:   [][2]float64{},
+	}
+
+	if result.Err != nil {
+		s.Error = result.Err.Error()
+	} else {
+		data := base64.StdEncoding.EncodeToString(result.Data)
+		s.DataBase64 = &data
+		if utf8.Valid(result.Data) {
+			text := string(result.Data)
+			s.DataUTF8 = &text
+		}
+	}
+
+	for _, p := range result.Corners {
+		s.Corners = append(s.Corners, [2]float64{round(p.X), round(p.Y)})
+	}
+
+	if qr := result.QR; qr != nil {
+		s.Version = &qr.Version
+		if qr.Format != nil {
+			mask := int(qr.Format.Mask)
+			s.ECLevel, s.Mask = qr.Format.ECLevel.String(), &mask
+		}
+		for _, block := range qr.Blocks {
+			s.CorrectedErrors = append(s.CorrectedErrors, block.Errors)
+		}
+	}
+
+	return s
+}
+
+// round rounds coordinates to hundredths of a pixel
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,57 @@
+package ar8t
+
+import "image"
+
+// Observer is told about every stage of decoding by DefaultDecoder, to log, measure or test them.
+// The stages past detection are those of QR Codes, the symbols of other symbologies are only
+// reported as results. Embed NopObserver to implement only some of the methods.
+type Observer interface {
+	// Prepared is the image prepared by BlockedMean, before any detection
+	Prepared(prepared *image.Gray)
+
+	// FinderCandidate is every finder pattern candidate found by LineScan
+	FinderCandidate(finder QRFinderPosition)
+
+	// Location is every QRLocation grouped from the finder patterns as it is decoded,
+	// the stages below being those of decoding the symbol there until the next one
+	Location(loc QRLocation)
+
+	// Extracted is the result of extracting the modules, after correcting the version
+	// of the location from the version information
+	Extracted(data QRData, err error)
+
+	// Format is the format information decoded from the modules
+	Format(format FormatInfo, err error)
+
+	// BlockCorrected is how every block was corrected, err being set for the block
+	// that couldn't be, after which no other block is
+	BlockCorrected(block int, stats BlockStats, err error)
+
+	// Segments are the segments decoded from the data codewords
+	Segments(segments []Segment, err error)
+
+	// Result is every symbol found, decoded or not, see DefaultDecoder.DecodeAll
+	Result(result Result)
+}
+
+// NopObserver is an Observer ignoring every stage
+type NopObserver struct{}
+
+var _ Observer = NopObserver{}
+
+func (NopObserver) Prepared(*image.Gray)                  {}
+func (NopObserver) FinderCandidate(QRFinderPosition)      {}
+func (NopObserver) Location(QRLocation)                   {}
+func (NopObserver) Extracted(QRData, error)               {}
+func (NopObserver) Format(FormatInfo, error)              {}
+func (NopObserver) BlockCorrected(int, BlockStats, error) {}
+func (NopObserver) Segments([]Segment, error)             {}
+func (NopObserver) Result(Result)                         {}
+
+// observerOr is the observer, or a NopObserver when it is nil
+func observerOr(observer Observer) Observer {
+	if observer == nil {
+		return NopObserver{}
+	}
+	return observer
+}
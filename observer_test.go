@@ -0,0 +1,98 @@
+package ar8t
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stageRecorder records the stages it is told about, along with the stats of every block
+type stageRecorder struct {
+	NopObserver
+
+	stages []string
+	blocks []BlockStats
+}
+
+func (r *stageRecorder) record(stage string) {
+	// finder candidates are found a number of times each
+	if len(r.stages) == 0 || r.stages[len(r.stages)-1] != stage {
+		r.stages = append(r.stages, stage)
+	}
+}
+
+func (r *stageRecorder) Prepared(*image.Gray)             { r.record("prepared") }
+func (r *stageRecorder) FinderCandidate(QRFinderPosition) { r.record("finder") }
+func (r *stageRecorder) Location(QRLocation)              { r.record("location") }
+func (r *stageRecorder) Extracted(_ QRData, err error)    { r.record(fmt.Sprint("extracted ", err)) }
+func (r *stageRecorder) Format(_ FormatInfo, err error)   { r.record(fmt.Sprint("format ", err)) }
+func (r *stageRecorder) Segments(_ []Segment, err error)  { r.record(fmt.Sprint("segments ", err)) }
+func (r *stageRecorder) Result(result Result)             { r.record("result " + string(result.Data)) }
+
+func (r *stageRecorder) BlockCorrected(block int, stats BlockStats, err error) {
+	r.record(fmt.Sprint("block ", block, " ", err))
+	r.blocks = append(r.blocks, stats)
+}
+
+func Test_Observer(t *testing.T) {
+	modules, err := ParseBitMatrix(`
+		#######....#..#######
+		#.....#..#....#.....#
+		#.###.#.##.#..#.###.#
+		#.###.#.#.#.#.#.###.#
+		#.###.#.##..#.#.###.#
+		#.....#.#..#..#.....#
+		#######.#.#.#.#######
+		........##...........
+		#.#####...##..#####..
+		.##..#...#..#...#.###
+		##....#.##..###....#.
+		.##.##...#...#.##.##.
+		##.#..#...#.#.##.#...
+		........#.##.#.##...#
+		#######..#.###....##.
+		#.....#.#.####..####.
+		#.###.#.##.####.##.##
+		#.###.#.#.#....####..
+		#.###.#.#.###.#...#..
+		#.....#...#..#.#..#..
+		#######.########...#.
+	`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	modules.Flip(11, 11)
+
+	const moduleSize, border = 4, 4
+	side := (modules.Width() + 2*border) * moduleSize
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			mx, my := x/moduleSize-border, y/moduleSize-border
+			if !modules.in(mx, my) || !modules.Get(mx, my) {
+				img.SetGray(x, y, color.Gray{255})
+			}
+		}
+	}
+
+	recorder := &stageRecorder{}
+	results, err := DefaultDecoder{Observer: recorder}.DecodeResults(img)
+	if !assert.NoError(t, err) || !assert.Len(t, results, 1) {
+		return
+	}
+
+	assert.Equal(t, []string{
+		"prepared",
+		"finder",
+		"location",
+		"extracted <nil>",
+		"format <nil>",
+		"block 0 <nil>",
+		"segments <nil>",
+		"result ar8t observer",
+	}, recorder.stages)
+	assert.Equal(t, []BlockStats{{Codewords: 26, DataCodewords: 16, Errors: 1}}, recorder.blocks)
+}
@@ -16,6 +16,12 @@ import (
 	"golang.org/x/exp/constraints"
 )
 
+type Preparer interface {
+	Prepare(img image.Image) *image.Gray
+}
+
+var _ Preparer = BlockedMean{}
+
 type BlockSize uint32
 
 type stats struct {
@@ -41,6 +47,21 @@ func (b BlockedMean) Prepare(img image.Image) *image.Gray {
 	return b.toThreshold(gray, blockMeanMap)
 }
 
+// Grayscale converts img to the grayscale image BlockedMean thresholds,
+// as used by soft-decision extraction
+func Grayscale(img image.Image) *image.Gray {
+	nrgba := imaging.Grayscale(img)
+	gray := image.NewGray(nrgba.Rect)
+
+	for y := 0; y < nrgba.Rect.Dy(); y++ {
+		for x := 0; x < nrgba.Rect.Dx(); x++ {
+			gray.SetGray(x, y, color.Gray{nrgba.NRGBAAt(x, y).R})
+		}
+	}
+
+	return gray
+}
+
 func (b BlockedMean) asBlockMap(gray *image.NRGBA) []stats {
 	blockWidth, blockHeight := asBlockCoords(gray.Rect.Dx(), gray.Rect.Dy(), b.blockSize)
 
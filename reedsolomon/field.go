@@ -0,0 +1,206 @@
+// Package reedsolomon implements Reed-Solomon error correction over GF(2^m),
+// the fields used by QR Codes and most other 2D symbologies, and over prime fields
+// such as GF(929), the field of PDF417.
+//
+// Codewords are stored highest degree first: the first symbol of a block is the
+// coefficient of x^(n-1) and the last error correction symbol the coefficient of x^0.
+package reedsolomon
+
+import "fmt"
+
+// Field is a Galois field GF(2^m) or GF(p) along with the generator polynomials built in it
+type Field struct {
+	size int
+	exp  []int
+	log  []int
+
+	// prime is set for GF(p), where adding is modulo p rather than a xor
+	prime bool
+
+	// generatorBase is b in the generator polynomial (x - α^b)(x - α^(b+1))...
+	generatorBase int
+}
+
+// QRCode is GF(256) with the primitive polynomial x^8 + x^4 + x^3 + x^2 + 1,
+// with generator polynomials starting at α^0
+var QRCode = NewField(0x11D, 256, 0)
+
+// DataMatrix is GF(256) with the primitive polynomial x^8 + x^5 + x^3 + x^2 + 1,
+// with generator polynomials starting at α^1
+var DataMatrix = NewField(0x12D, 256, 1)
+
+// The fields of Aztec Codes, all of them with generator polynomials starting at α^1:
+// GF(16) for the mode message, and GF(64) to GF(4096) for the data codewords
+// of 6 to 12 bits, depending on the number of layers
+var (
+	AztecParam  = NewField(0x13, 16, 1)
+	AztecData6  = NewField(0x43, 64, 1)
+	AztecData8  = DataMatrix
+	AztecData10 = NewField(0x409, 1024, 1)
+	AztecData12 = NewField(0x1069, 4096, 1)
+)
+
+// PDF417 is GF(929) with the primitive element 3, with generator polynomials starting at α^1
+var PDF417 = NewPrimeField(929, 3, 1)
+
+// NewField builds GF(size) from its primitive polynomial, size being a power of two.
+// generatorBase is the exponent of the first root of generator polynomials.
+func NewField(primitive, size, generatorBase int) *Field {
+	if size < 4 || size&(size-1) != 0 || primitive < size || primitive >= 2*size {
+		panic(fmt.Sprintf("reedsolomon: invalid field GF(%d) with primitive %#x", size, primitive))
+	}
+
+	f := &Field{
+		size:          size,
+		exp:           make([]int, size),
+		log:           make([]int, size),
+		generatorBase: generatorBase,
+	}
+
+	x := 1
+	for i := 0; i < size-1; i++ {
+		f.exp[i] = x
+		f.log[x] = i
+
+		x <<= 1
+		if x >= size {
+			x ^= primitive
+		}
+	}
+	// wraps around, so the exponent of a product never needs a modulo
+	f.exp[size-1] = 1
+
+	return f
+}
+
+// NewPrimeField builds GF(size) from a primitive element, size being a prime.
+// generatorBase is the exponent of the first root of generator polynomials.
+func NewPrimeField(size, primitive, generatorBase int) *Field {
+	f := &Field{
+		size:          size,
+		exp:           make([]int, size),
+		log:           make([]int, size),
+		prime:         true,
+		generatorBase: generatorBase,
+	}
+
+	x := 1
+	for i := 0; i < size-1; i++ {
+		if i > 0 && x == 1 {
+			panic(fmt.Sprintf("reedsolomon: %d is not a primitive element of GF(%d)", primitive, size))
+		}
+
+		f.exp[i] = x
+		f.log[x] = i
+
+		x = x * primitive % size
+	}
+	if x != 1 {
+		panic(fmt.Sprintf("reedsolomon: invalid field GF(%d)", size))
+	}
+	f.exp[size-1] = 1
+
+	return f
+}
+
+// Size is the number of elements in the field
+func (f *Field) Size() int {
+	return f.size
+}
+
+// Exp returns α^i
+func (f *Field) Exp(i int) int {
+	i %= f.size - 1
+	if i < 0 {
+		i += f.size - 1
+	}
+	return f.exp[i]
+}
+
+// Log returns i for α^i = a, a must not be zero
+func (f *Field) Log(a int) int {
+	if a == 0 {
+		panic("reedsolomon: log of zero")
+	}
+	return f.log[a]
+}
+
+// Add adds a and b, which is the same as subtracting them in GF(2^m)
+func (f *Field) Add(a, b int) int {
+	if f.prime {
+		return (a + b) % f.size
+	}
+	return a ^ b
+}
+
+// Sub subtracts b from a
+func (f *Field) Sub(a, b int) int {
+	return f.Add(a, f.Neg(b))
+}
+
+// Neg returns -a, which is a itself in GF(2^m)
+func (f *Field) Neg(a int) int {
+	if f.prime && a != 0 {
+		return f.size - a
+	}
+	return a
+}
+
+// times adds a to itself n times
+func (f *Field) times(a, n int) int {
+	if f.prime {
+		return a * (n % f.size) % f.size
+	}
+	if n%2 == 0 {
+		return 0
+	}
+	return a
+}
+
+func (f *Field) Mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[(f.log[a]+f.log[b])%(f.size-1)]
+}
+
+// Div divides a by b, b must not be zero
+func (f *Field) Div(a, b int) int {
+	if b == 0 {
+		panic("reedsolomon: division by zero")
+	}
+	if a == 0 {
+		return 0
+	}
+	return f.exp[(f.log[a]-f.log[b]+f.size-1)%(f.size-1)]
+}
+
+// Inverse returns 1/a, a must not be zero
+func (f *Field) Inverse(a int) int {
+	return f.Div(1, a)
+}
+
+// polynomials in this file are stored lowest degree first
+
+func (f *Field) polyMul(a, b []int) []int {
+	res := make([]int, len(a)+len(b)-1)
+	for i, x := range a {
+		if x == 0 {
+			continue
+		}
+		for j, y := range b {
+			res[i+j] = f.Add(res[i+j], f.Mul(x, y))
+		}
+	}
+
+	return res
+}
+
+func (f *Field) polyEval(poly []int, x int) int {
+	res := 0
+	for i := len(poly) - 1; i >= 0; i-- {
+		res = f.Add(f.Mul(res, x), poly[i])
+	}
+
+	return res
+}
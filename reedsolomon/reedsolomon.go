@@ -0,0 +1,296 @@
+package reedsolomon
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrTooManyErasures  = errors.New("reedsolomon: too many erasures to correct")
+	ErrInvalidErasure   = errors.New("reedsolomon: erasure outside of the block")
+	ErrTooManyErrors    = errors.New("reedsolomon: too many errors to correct")
+	ErrInvalidBlockSize = errors.New("reedsolomon: invalid block size")
+)
+
+// Generator returns the generator polynomial for ecLen error correction symbols,
+// (x - α^b)(x - α^(b+1))...(x - α^(b+ecLen-1)), highest degree first
+func (f *Field) Generator(ecLen int) []int {
+	generator := []int{1}
+	for i := 0; i < ecLen; i++ {
+		generator = f.polyMul(generator, []int{f.Neg(f.Exp(f.generatorBase + i)), 1})
+	}
+
+	reverse(generator)
+	return generator
+}
+
+// Encode returns the ecLen error correction symbols for data, the negated remainder
+// of dividing data by the generator polynomial so that the whole block divides by it
+func (f *Field) Encode(data []int, ecLen int) []int {
+	generator := f.Generator(ecLen)
+
+	remainder := make([]int, len(data)+ecLen)
+	copy(remainder, data)
+
+	for i := range data {
+		coeff := remainder[i]
+		if coeff == 0 {
+			continue
+		}
+		for j := 1; j <= ecLen; j++ {
+			remainder[i+j] = f.Sub(remainder[i+j], f.Mul(generator[j], coeff))
+		}
+	}
+
+	ec := remainder[len(data):]
+	for i := range ec {
+		ec[i] = f.Neg(ec[i])
+	}
+
+	return ec
+}
+
+// Syndromes calculates the ecLen syndromes of block, all of them are zero
+// when the block is a valid codeword
+func (f *Field) Syndromes(block []int, ecLen int) ([]int, bool) {
+	syndromes := make([]int, ecLen)
+	valid := true
+
+	for j := range syndromes {
+		x := f.Exp(f.generatorBase + j)
+		s := 0
+		for _, symbol := range block {
+			s = f.Add(f.Mul(s, x), symbol)
+		}
+
+		syndromes[j] = s
+		if s != 0 {
+			valid = false
+		}
+	}
+
+	return syndromes, valid
+}
+
+// Decode corrects block in place, ecLen being the number of error correction symbols
+// at its end. erasures are the indices of symbols known (or likely) to be wrong,
+// each takes half the capacity of an error at an unknown position,
+// so 2*errors + erasures must not exceed ecLen.
+// It returns the number of corrected symbols.
+//
+// The error locator is found with Berlekamp-Massey, starting from the erasure locator,
+// its roots with a Chien search and the error values with the Forney algorithm.
+func (f *Field) Decode(block []int, ecLen int, erasures []int) (int, error) {
+	if len(block) >= f.size || ecLen > len(block) {
+		return 0, ErrInvalidBlockSize
+	}
+
+	erasures = unique(erasures)
+	if len(erasures) > ecLen {
+		return 0, ErrTooManyErasures
+	}
+
+	for _, index := range erasures {
+		if index < 0 || index >= len(block) {
+			return 0, ErrInvalidErasure
+		}
+	}
+
+	syndromes, valid := f.Syndromes(block, ecLen)
+	if valid {
+		return 0, nil
+	}
+
+	// the symbol at index i is the coefficient of x^power(i)
+	power := func(index int) int {
+		return len(block) - 1 - index
+	}
+
+	erasureLocator := []int{1}
+	for _, index := range erasures {
+		erasureLocator = f.polyMul(erasureLocator, []int{1, f.Neg(f.Exp(power(index)))})
+	}
+
+	locator := f.berlekampMassey(syndromes, erasureLocator, len(erasures))
+	degree := len(locator) - 1
+
+	// the locator has a root for every erasure, the others are errors at unknown positions
+	if degree == 0 || 2*(degree-len(erasures))+len(erasures) > ecLen {
+		return 0, ErrTooManyErrors
+	}
+
+	// chien search, a root at X^-1 is an error at X
+	locations := []int{}
+	for index := range block {
+		if f.polyEval(locator, f.Exp(-power(index))) == 0 {
+			locations = append(locations, index)
+		}
+	}
+
+	if len(locations) != degree {
+		return 0, ErrTooManyErrors
+	}
+
+	// forney
+	evaluator := f.polyMul(syndromes, locator)
+	if len(evaluator) > ecLen {
+		evaluator = evaluator[:ecLen]
+	}
+
+	derivative := make([]int, len(locator)-1)
+	for i := 1; i < len(locator); i++ {
+		derivative[i-1] = f.times(locator[i], i)
+	}
+
+	values := make([]int, len(locations))
+	for i, index := range locations {
+		xInv := f.Exp(-power(index))
+
+		denominator := f.polyEval(derivative, xInv)
+		if denominator == 0 {
+			return 0, ErrTooManyErrors
+		}
+
+		// -X^(1-b) Ω(X^-1) / Λ'(X^-1)
+		values[i] = f.Neg(f.Mul(
+			f.Exp(power(index)*(1-f.generatorBase)),
+			f.Div(f.polyEval(evaluator, xInv), denominator),
+		))
+	}
+
+	for i, index := range locations {
+		block[index] = f.Sub(block[index], values[i])
+	}
+
+	if _, valid := f.Syndromes(block, ecLen); !valid {
+		// never leave a half corrected block behind
+		for i, index := range locations {
+			block[index] = f.Add(block[index], values[i])
+		}
+		return 0, ErrTooManyErrors
+	}
+
+	corrected := 0
+	for _, value := range values {
+		if value != 0 {
+			corrected++
+		}
+	}
+
+	return corrected, nil
+}
+
+// berlekampMassey finds the shortest LFSR generating syndromes. Starting from the erasure
+// locator of the given number of erasures, the result locates erasures and errors alike.
+func (f *Field) berlekampMassey(syndromes, erasureLocator []int, erasures int) []int {
+	var (
+		current  = append([]int{}, erasureLocator...)
+		previous = append([]int{}, erasureLocator...)
+		length   = erasures
+		shift    = 1
+		lastDisc = 1
+	)
+
+	for k := erasures; k < len(syndromes); k++ {
+		discrepancy := 0
+		for i := 0; i <= length && i < len(current) && i <= k; i++ {
+			discrepancy = f.Add(discrepancy, f.Mul(current[i], syndromes[k-i]))
+		}
+
+		if discrepancy == 0 {
+			shift++
+			continue
+		}
+
+		scale := f.Div(discrepancy, lastDisc)
+		next := append([]int{}, current...)
+		for i, c := range previous {
+			for len(next) <= i+shift {
+				next = append(next, 0)
+			}
+			next[i+shift] = f.Sub(next[i+shift], f.Mul(scale, c))
+		}
+
+		if 2*length <= k+erasures {
+			previous = current
+			length = k + 1 + erasures - length
+			lastDisc = discrepancy
+			shift = 1
+		} else {
+			shift++
+		}
+
+		current = next
+	}
+
+	// trailing zero coefficients do not count towards the degree
+	for len(current) > 1 && current[len(current)-1] == 0 {
+		current = current[:len(current)-1]
+	}
+
+	return current
+}
+
+// Encode appends ecLen error correction codewords to data, in the field used by QR Codes
+func Encode(data []byte, ecLen int) []byte {
+	ec := QRCode.Encode(toInts(data), ecLen)
+
+	block := make([]byte, 0, len(data)+ecLen)
+	block = append(block, data...)
+	for _, symbol := range ec {
+		block = append(block, byte(symbol))
+	}
+
+	return block
+}
+
+// Decode corrects a block of data followed by ecLen error correction codewords in place,
+// in the field used by QR Codes. It returns the block and the number of corrected codewords.
+func Decode(block []byte, ecLen int) ([]byte, int, error) {
+	return DecodeWithErasures(block, ecLen, nil)
+}
+
+// DecodeWithErasures is Decode with the indices of codewords known (or likely) to be wrong
+func DecodeWithErasures(block []byte, ecLen int, erasures []int) ([]byte, int, error) {
+	symbols := toInts(block)
+
+	corrected, err := QRCode.Decode(symbols, ecLen, erasures)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, symbol := range symbols {
+		block[i] = byte(symbol)
+	}
+
+	return block, corrected, nil
+}
+
+func toInts(b []byte) []int {
+	ints := make([]int, len(b))
+	for i, v := range b {
+		ints[i] = int(v)
+	}
+	return ints
+}
+
+func unique(indices []int) []int {
+	res := append([]int{}, indices...)
+	sort.Ints(res)
+
+	n := 0
+	for i, index := range res {
+		if i == 0 || index != res[n-1] {
+			res[n] = index
+			n++
+		}
+	}
+
+	return res[:n]
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,132 @@
+package reedsolomon
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	// "01234567" as a version 1-M QR Code, from ISO/IEC 18004 annex I
+	data := []byte{
+		0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11,
+		0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11,
+	}
+	ec := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+
+	assert.Equal(t, append(append([]byte{}, data...), ec...), Encode(data, len(ec)))
+}
+
+func TestField_Generator(t *testing.T) {
+	generator := QRCode.Generator(7)
+
+	logs := []int{}
+	for _, coeff := range generator {
+		logs = append(logs, QRCode.Log(coeff))
+	}
+
+	// x^7 + α^87x^6 + α^229x^5 + α^146x^4 + α^149x^3 + α^238x^2 + α^102x + α^21
+	assert.Equal(t, []int{0, 87, 229, 146, 149, 238, 102, 21}, logs)
+}
+
+func TestField_Decode(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *Field
+		ecLen int
+	}{
+		{name: "QR Code", field: QRCode, ecLen: 30},
+		{name: "GF(16)", field: NewField(0x13, 16, 1), ecLen: 5},
+		{name: "GF(1024)", field: NewField(0x409, 1024, 1), ecLen: 40},
+		{name: "Aztec GF(4096)", field: AztecData12, ecLen: 24},
+		{name: "PDF417 GF(929)", field: PDF417, ecLen: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			n := min(tt.field.Size()-1, 100)
+
+			for trial := 0; trial < 200; trial++ {
+				data := make([]int, n-tt.ecLen)
+				for i := range data {
+					data[i] = r.Intn(tt.field.Size())
+				}
+				want := append(append([]int{}, data...), tt.field.Encode(data, tt.ecLen)...)
+
+				erasureCount := r.Intn(tt.ecLen + 1)
+				errorCount := (tt.ecLen - erasureCount) / 2
+				positions := r.Perm(n)
+
+				block := append([]int{}, want...)
+				for _, i := range positions[:erasureCount+errorCount] {
+					block[i] = tt.field.Add(block[i], 1+r.Intn(tt.field.Size()-1))
+				}
+
+				_, err := tt.field.Decode(block, tt.ecLen, positions[:erasureCount])
+				if assert.NoError(t, err) {
+					assert.Equal(t, want, block)
+				}
+			}
+		})
+	}
+}
+
+func TestDecode_tooManyErrors(t *testing.T) {
+	block := Encode(make([]byte, 20), 6)
+	want := append([]byte{}, block...)
+	for i := 0; i < 4; i++ {
+		block[i*5] ^= 0xFF
+	}
+	damaged := append([]byte{}, block...)
+
+	_, _, err := Decode(block, 6)
+	assert.Error(t, err)
+	assert.NotEqual(t, want, block)
+	assert.Equal(t, damaged, block, "a failed decode leaves the block untouched")
+}
+
+func TestField_Decode_overCapacity(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *Field
+		ecLen int
+	}{
+		{name: "Aztec mode message", field: AztecParam, ecLen: 5},
+		{name: "QR Code", field: QRCode, ecLen: 7},
+		{name: "PDF417", field: PDF417, ecLen: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			n := min(tt.field.Size()-1, 30)
+
+			for trial := 0; trial < 2000; trial++ {
+				data := make([]int, n-tt.ecLen)
+				for i := range data {
+					data[i] = r.Intn(tt.field.Size())
+				}
+				block := append(data, tt.field.Encode(data, tt.ecLen)...)
+
+				// one error more than an odd number of error correction symbols can take
+				for _, i := range r.Perm(n)[:(tt.ecLen+1)/2] {
+					block[i] = tt.field.Add(block[i], 1+r.Intn(tt.field.Size()-1))
+				}
+
+				_, err := tt.field.Decode(block, tt.ecLen, nil)
+				if !assert.ErrorIs(t, err, ErrTooManyErrors, "trial %d", trial) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,109 @@
+package ar8t
+
+import (
+	"image"
+	"image/color"
+)
+
+// DecodeRegion decodes the symbols in a region of src, see DecodeRegions
+func (d DefaultDecoder) DecodeRegion(src image.Image, region image.Rectangle) ([]Result, error) {
+	return d.DecodeRegions(src, region)
+}
+
+// DecodeRegions decodes the symbols in regions of src, preparing and scanning only the pixels
+// inside them, which is much faster than decoding all of src when it is large. The regions are
+// in the coordinates of src, and the corners of the results are relative to its top left corner
+// as DecodeResults gives them. A symbol is only found when it is whole in a region, along with
+// some of its quiet zone. Symbols found in overlapping regions are only returned once.
+//
+// Debug and Observer are told about every region as an image of its own.
+func (d DefaultDecoder) DecodeRegions(src image.Image, regions ...image.Rectangle) ([]Result, error) {
+	bounds := src.Bounds()
+
+	results := []Result{}
+	for _, region := range regions {
+		region = region.Intersect(bounds)
+		if region.Empty() {
+			continue
+		}
+
+		found, err := d.DecodeResults(subImage(src, region))
+		if err != nil {
+			continue
+		}
+
+		offset := Point{float64(region.Min.X - bounds.Min.X), float64(region.Min.Y - bounds.Min.Y)}
+
+	found:
+		for _, result := range found {
+			for i := range result.Corners {
+				result.Corners[i] = result.Corners[i].Add(offset)
+			}
+
+			for _, other := range results {
+				if sameSymbol(result, other) {
+					continue found
+				}
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNoSymbolsFound
+	}
+
+	return results, nil
+}
+
+// sameSymbol tells whether two results are the same symbol, having the same data
+// and their centres less than half their size apart
+func sameSymbol(a, b Result) bool {
+	if a.Symbology != b.Symbology || string(a.Data) != string(b.Data) || len(a.Corners) == 0 || len(b.Corners) == 0 {
+		return false
+	}
+
+	centre := func(corners []Point) Point {
+		c := Point{}
+		for _, p := range corners {
+			c = c.Add(p)
+		}
+		return c.Div(float64(len(corners)))
+	}
+
+	centreA, size := centre(a.Corners), 0.0
+	for _, p := range a.Corners {
+		size = max(size, distance(p, centreA))
+	}
+
+	return distance(centreA, centre(b.Corners)) < size
+}
+
+// subImage is the region of src, sharing its pixels when it can
+func subImage(src image.Image, region image.Rectangle) image.Image {
+	if sub, ok := src.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(region)
+	}
+
+	return regionImage{src, region}
+}
+
+// regionImage is a region of an image without a SubImage method
+type regionImage struct {
+	image.Image
+	region image.Rectangle
+}
+
+func (r regionImage) Bounds() image.Rectangle {
+	return r.region
+}
+
+func (r regionImage) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(r.region)) {
+		return r.ColorModel().Convert(color.Transparent)
+	}
+	return r.Image.At(x, y)
+}
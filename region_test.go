@@ -0,0 +1,86 @@
+package ar8t
+
+import (
+	"image"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DecodeRegions(t *testing.T) {
+	const ean8 = "#.#...#.##.#.####.####.#.##.###.#.#.#..###.###..#.#...#..#.###..#.#"
+
+	// a page with an EAN-13 barcode on the top left and an EAN-8 one running down on the right
+	page := image.NewGray(image.Rect(0, 0, 1000, 700))
+	for i := range page.Pix {
+		page.Pix[i] = 255
+	}
+	draw.Draw(page, image.Rect(50, 40, 1000, 700), linearImage(streamEAN13, false), image.Point{}, draw.Src)
+	draw.Draw(page, image.Rect(800, 300, 1000, 700), linearImage(ean8, true), image.Point{}, draw.Src)
+
+	top := image.Rect(40, 30, 450, 200)
+	right := image.Rect(780, 280, 920, 620)
+
+	tests := []struct {
+		name    string
+		regions []image.Rectangle
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "one region",
+			regions: []image.Rectangle{top},
+			want:    []string{"4006381333931"},
+		},
+		{
+			name:    "two regions",
+			regions: []image.Rectangle{right, top},
+			want:    []string{"96385074", "4006381333931"},
+		},
+		{
+			name:    "overlapping regions",
+			regions: []image.Rectangle{top, top.Inset(-20), right},
+			want:    []string{"4006381333931", "96385074"},
+		},
+		{
+			name:    "empty region",
+			regions: []image.Rectangle{image.Rect(500, 400, 700, 600)},
+			wantErr: ErrNoSymbolsFound,
+		},
+		{
+			name:    "outside the image",
+			regions: []image.Rectangle{image.Rect(2000, 2000, 2100, 2100)},
+			wantErr: ErrNoSymbolsFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultDecoder{}.DecodeRegions(page, tt.regions...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if !assert.NoError(t, err) || !assert.Len(t, got, len(tt.want)) {
+				return
+			}
+
+			for i, result := range got {
+				assert.Equal(t, tt.want[i], string(result.Data))
+			}
+		})
+	}
+
+	// the corners are those found in the whole page
+	whole, err := DefaultDecoder{}.DecodeResults(page)
+	region, regionErr := DefaultDecoder{}.DecodeRegion(page, top)
+	if assert.NoError(t, err) && assert.NoError(t, regionErr) {
+		for _, result := range whole {
+			if result.Symbology == SymbologyEAN13 {
+				assert.Equal(t, result.Corners, region[0].Corners)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package ar8t
+
+import (
+	"errors"
+	"image"
+	"io"
+	"math"
+	"time"
+)
+
+// StreamDecoder decodes the successive frames of a video, where the same symbols are seen
+// over many frames
+//
+// The whole frame is only scanned every FullScanInterval frames. In the frames between,
+// only the areas around the symbols seen before are decoded, which keeps up with symbols
+// moving at most Margin times their size from one frame to the next.
+// Every distinct payload is emitted once, when it hasn't been seen for Expiry or the stream ends,
+// along with when it was first and last seen. A payload seen again after that is emitted again.
+type StreamDecoder struct {
+	Decoder DefaultDecoder
+
+	// FullScanInterval is the number of frames from one full scan to the next, 10 when 0
+	FullScanInterval int
+
+	// Margin is how far around a symbol seen before to decode it again, in multiples of its size, 0.5 when 0
+	Margin float64
+
+	// Expiry is how long a payload isn't seen before it is emitted, a second when 0
+	Expiry time.Duration
+
+	tracks []*StreamSymbol
+	frames int
+}
+
+// StreamSymbol is a distinct payload seen in a stream
+type StreamSymbol struct {
+	Symbology Symbology
+	Data      []byte
+
+	// FirstSeen and LastSeen are the times of the first and last frames it was decoded in
+	FirstSeen, LastSeen time.Duration
+
+	// Frames is the number of frames it was decoded in
+	Frames int
+
+	// Corners are where it was last seen, see Result
+	Corners []Point
+}
+
+// Decode decodes a frame seen at a time, which never goes back, and returns the symbols
+// not seen for Expiry since
+func (s *StreamDecoder) Decode(frame image.Image, at time.Duration) []StreamSymbol {
+	interval := s.FullScanInterval
+	if interval <= 0 {
+		interval = 10
+	}
+
+	var results []Result
+	if s.frames%interval == 0 || len(s.tracks) == 0 {
+		results, _ = s.Decoder.DecodeResults(frame)
+	} else {
+		results = s.decodeTracked(frame)
+	}
+	s.frames++
+
+	for _, result := range results {
+		s.seen(result, at)
+	}
+
+	return s.expire(at)
+}
+
+// Flush returns the symbols not emitted yet, once the stream ends
+func (s *StreamDecoder) Flush() []StreamSymbol {
+	emitted := []StreamSymbol{}
+	for _, track := range s.tracks {
+		emitted = append(emitted, *track)
+	}
+	s.tracks = nil
+
+	return emitted
+}
+
+// decodeTracked decodes the areas of a frame around the symbols seen before
+func (s *StreamDecoder) decodeTracked(frame image.Image) []Result {
+	margin := s.Margin
+	if margin <= 0 {
+		margin = 0.5
+	}
+
+	// corners are relative to the top left corner of the frame, see DecodeRegions
+	areas := []image.Rectangle{}
+	for _, track := range s.tracks {
+		areas = append(areas, trackArea(track.Corners, margin).Add(frame.Bounds().Min))
+	}
+
+	results, _ := s.Decoder.DecodeRegions(frame, areas...)
+	return results
+}
+
+// trackArea is the area around corners, margin times their size further on every side
+func trackArea(corners []Point, margin float64) image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range corners {
+		minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+		maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+	}
+
+	// linear barcodes are found along a line, their height isn't known
+	size := math.Max(maxX-minX, maxY-minY)
+	grow := margin * size
+
+	return image.Rect(
+		int(math.Floor(minX-grow)), int(math.Floor(minY-grow)),
+		int(math.Ceil(maxX+grow)), int(math.Ceil(maxY+grow)),
+	)
+}
+
+// seen updates the symbol with the payload of a result, or starts tracking it
+func (s *StreamDecoder) seen(result Result, at time.Duration) {
+	for _, track := range s.tracks {
+		if track.Symbology == result.Symbology && string(track.Data) == string(result.Data) {
+			// tracked areas can overlap, and find the same symbol more than once in a frame
+			if track.LastSeen != at {
+				track.Frames++
+			}
+			track.LastSeen, track.Corners = at, result.Corners
+			return
+		}
+	}
+
+	s.tracks = append(s.tracks, &StreamSymbol{
+		Symbology: result.Symbology,
+		Data:      result.Data,
+		FirstSeen: at,
+		LastSeen:  at,
+		Frames:    1,
+		Corners:   result.Corners,
+	})
+}
+
+// expire removes the symbols not seen for Expiry and returns them
+func (s *StreamDecoder) expire(at time.Duration) []StreamSymbol {
+	expiry := s.Expiry
+	if expiry <= 0 {
+		expiry = time.Second
+	}
+
+	emitted, kept := []StreamSymbol{}, s.tracks[:0]
+	for _, track := range s.tracks {
+		if at-track.LastSeen > expiry {
+			emitted = append(emitted, *track)
+		} else {
+			kept = append(kept, track)
+		}
+	}
+	s.tracks = kept
+
+	return emitted
+}
+
+// FrameReader reads the successive frames of a video, along with when they are seen.
+// It returns io.EOF after the last frame.
+type FrameReader interface {
+	ReadFrame() (image.Image, time.Duration, error)
+}
+
+// DecodeStream decodes every frame of a stream, emitting every distinct payload as Decode does
+// and those left once the stream ends
+func (s *StreamDecoder) DecodeStream(frames FrameReader, emit func(StreamSymbol)) error {
+	for {
+		frame, at, err := frames.ReadFrame()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, symbol := range s.Decode(frame, at) {
+			emit(symbol)
+		}
+	}
+
+	for _, symbol := range s.Flush() {
+		emit(symbol)
+	}
+
+	return nil
+}
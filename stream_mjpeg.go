@@ -0,0 +1,154 @@
+package ar8t
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"time"
+)
+
+var (
+	errMJPEGFrame     = errors.New("invalid JPEG frame in MJPEG stream")
+	errMJPEGFrameRate = errors.New("MJPEG frame rate must be positive")
+)
+
+// MJPEGReader reads the frames of an MJPEG stream, JPEG images one after the other.
+// Anything between the images is skipped, such as the part headers of a multipart/x-mixed-replace
+// stream from an IP camera.
+type MJPEGReader struct {
+	r *bufio.Reader
+
+	// FrameRate is the number of frames a second the frames are seen at, as MJPEG streams don't tell
+	FrameRate float64
+
+	frames int
+}
+
+func NewMJPEGReader(r io.Reader, frameRate float64) (*MJPEGReader, error) {
+	if !(frameRate > 0) {
+		return nil, errMJPEGFrameRate
+	}
+
+	return &MJPEGReader{r: bufio.NewReader(r), FrameRate: frameRate}, nil
+}
+
+// ReadFrame reads the next frame, seen at its number divided by the frame rate
+func (m *MJPEGReader) ReadFrame() (image.Image, time.Duration, error) {
+	if !(m.FrameRate > 0) {
+		return nil, 0, errMJPEGFrameRate
+	}
+
+	data, err := m.nextJPEG()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	frame, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	at := time.Duration(float64(m.frames) / m.FrameRate * float64(time.Second))
+	m.frames++
+
+	return frame, at, nil
+}
+
+// nextJPEG reads the bytes of the next JPEG image, from its start of image marker to its end of image
+// marker. The segments are followed by their lengths, so that the markers of the thumbnails they may
+// hold aren't mistaken for those of the image.
+func (m *MJPEGReader) nextJPEG() ([]byte, error) {
+	// skip to the start of image marker, FF D8
+	previous := byte(0)
+	for {
+		b, err := m.r.ReadByte()
+		if err != nil {
+			return nil, io.EOF
+		}
+		if previous == 0xff && b == 0xd8 {
+			break
+		}
+		previous = b
+	}
+
+	data := []byte{0xff, 0xd8}
+	for {
+		marker, err := m.readMarker(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case marker == 0xd9:
+			// end of image
+			return data, nil
+		case marker == 0x01 || marker >= 0xd0 && marker <= 0xd7:
+			// markers without a segment
+			continue
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(m.r, length[:]); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		n := int(length[0])<<8 | int(length[1])
+		if n < 2 {
+			return nil, errMJPEGFrame
+		}
+
+		segment := make([]byte, n-2)
+		if _, err := io.ReadFull(m.r, segment); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		data = append(append(data, length[:]...), segment...)
+
+		// the entropy coded data follows a start of scan segment until the next marker,
+		// FF bytes in it being followed by 00 or a restart marker
+		if marker == 0xda {
+			if err := m.readScan(&data); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// readMarker reads the next marker, skipping the FF bytes that can pad it
+func (m *MJPEGReader) readMarker(data *[]byte) (byte, error) {
+	b, err := m.r.ReadByte()
+	if err != nil || b != 0xff {
+		return 0, errMJPEGFrame
+	}
+
+	for b == 0xff {
+		if b, err = m.r.ReadByte(); err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+	}
+
+	*data = append(*data, 0xff, b)
+	return b, nil
+}
+
+// readScan reads entropy coded data, leaving the marker after it unread
+func (m *MJPEGReader) readScan(data *[]byte) error {
+	for {
+		next, err := m.r.Peek(2)
+		if err != nil {
+			return io.ErrUnexpectedEOF
+		}
+
+		switch {
+		case next[0] != 0xff:
+			*data = append(*data, next[0])
+			m.r.Discard(1)
+		case next[1] == 0x00 || next[1] >= 0xd0 && next[1] <= 0xd7:
+			*data = append(*data, next...)
+			m.r.Discard(2)
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package ar8t
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const streamEAN13 = "#.#...##.#.#..###.#.####.####.#...#..#.##..##.#.#.#....#.#....#.#....#.###.#..#....#.##..##.#.#"
+
+// streamFrames are frames of 400 x 160 pixels with an EAN-13 barcode moving to the right in the first
+// of them, then blank
+func streamFrames(seen, blank int) []*image.Gray {
+	barcode := linearImage(streamEAN13, false)
+
+	frames := []*image.Gray{}
+	for i := 0; i < seen+blank; i++ {
+		frame := image.NewGray(image.Rect(0, 0, 400, 160))
+		for j := range frame.Pix {
+			frame.Pix[j] = 255
+		}
+
+		if i < seen {
+			draw.Draw(frame, barcode.Rect.Add(image.Point{4 * i, 10}), barcode, image.Point{}, draw.Src)
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+func Test_StreamDecoder_Y4M(t *testing.T) {
+	stream := bytes.Buffer{}
+	stream.WriteString("YUV4MPEG2 W400 H160 F10:1 Ip A1:1 C420jpeg\n")
+	for _, frame := range streamFrames(15, 15) {
+		stream.WriteString("FRAME\n")
+		stream.Write(frame.Pix)
+		stream.Write(bytes.Repeat([]byte{128}, 2*200*80))
+	}
+
+	frames, err := NewY4MReader(&stream)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	emitted := []StreamSymbol{}
+	err = (&StreamDecoder{}).DecodeStream(frames, func(symbol StreamSymbol) {
+		emitted = append(emitted, symbol)
+	})
+
+	if assert.NoError(t, err) && assert.Len(t, emitted, 1) {
+		assert.Equal(t, SymbologyEAN13, emitted[0].Symbology)
+		assert.Equal(t, "4006381333931", string(emitted[0].Data))
+		assert.Equal(t, time.Duration(0), emitted[0].FirstSeen)
+		assert.Equal(t, 1400*time.Millisecond, emitted[0].LastSeen)
+		assert.Equal(t, 15, emitted[0].Frames)
+	}
+}
+
+func Test_MJPEGReader(t *testing.T) {
+	stream := bytes.Buffer{}
+	for _, frame := range streamFrames(3, 0) {
+		data := bytes.Buffer{}
+		assert.NoError(t, jpeg.Encode(&data, frame, &jpeg.Options{Quality: 90}))
+
+		// an APP1 segment holding the markers of a thumbnail, as EXIF data can
+		app1 := []byte{0xff, 0xe1, 0x00, 0x08, 0xff, 0xd8, 0xff, 0xd9, 0xff, 0xd9}
+		jpegData := append(append(append([]byte{}, data.Bytes()[:2]...), app1...), data.Bytes()[2:]...)
+
+		fmt.Fprintf(&stream, "--frame\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(jpegData))
+		stream.Write(jpegData)
+		stream.WriteString("\r\n")
+	}
+
+	frames, err := NewMJPEGReader(&stream, 5)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	emitted := []StreamSymbol{}
+	err = (&StreamDecoder{}).DecodeStream(frames, func(symbol StreamSymbol) {
+		emitted = append(emitted, symbol)
+	})
+
+	if assert.NoError(t, err) && assert.Len(t, emitted, 1) {
+		assert.Equal(t, "4006381333931", string(emitted[0].Data))
+		assert.Equal(t, 400*time.Millisecond, emitted[0].LastSeen)
+		assert.Equal(t, 3, emitted[0].Frames)
+	}
+}
+
+func Test_MJPEGReader_frameRate(t *testing.T) {
+	for _, frameRate := range []float64{0, -1, math.NaN()} {
+		_, err := NewMJPEGReader(&bytes.Buffer{}, frameRate)
+		assert.Equal(t, errMJPEGFrameRate, err, "frame rate %v", frameRate)
+	}
+
+	_, _, err := (&MJPEGReader{}).ReadFrame()
+	assert.Equal(t, errMJPEGFrameRate, err)
+}
+
+func Test_Y4MReader_timestamps(t *testing.T) {
+	stream := bytes.Buffer{}
+	stream.WriteString("YUV4MPEG2 W1 H1 F30000:1001 Cmono\n")
+	for i := 0; i < 2; i++ {
+		stream.WriteString("FRAME\n\x00")
+	}
+
+	frames, err := NewY4MReader(&stream)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// a frame number times a thousand and one seconds overflows in nanoseconds
+	frames.frames = 1 << 33
+	_, at, err := frames.ReadFrame()
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(286617484)*time.Second+219733333*time.Nanosecond, at)
+	}
+	_, at, err = frames.ReadFrame()
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(286617484)*time.Second+253100000*time.Nanosecond, at)
+	}
+
+	_, err = NewY4MReader(strings.NewReader("YUV4MPEG2 W1 H1 F4294967296:1\n"))
+	assert.Equal(t, errY4MHeader, err)
+}
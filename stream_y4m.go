@@ -0,0 +1,134 @@
+package ar8t
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errY4MHeader = errors.New("invalid YUV4MPEG2 header")
+
+// Y4MReader reads the frames of a YUV4MPEG2 stream, the luminance plane of each being the frame
+type Y4MReader struct {
+	r *bufio.Reader
+
+	width, height int
+
+	// chroma is the size of the chroma planes skipped after the luminance plane of every frame
+	chroma int
+
+	// frameRate is the number of frames a second, as a fraction
+	rateNum, rateDen int64
+
+	frames int64
+}
+
+// NewY4MReader reads the stream header of a YUV4MPEG2 stream.
+// 8 bit 4:2:0, 4:2:2, 4:4:4, 4:1:1 and monochrome streams are supported.
+func NewY4MReader(r io.Reader) (*Y4MReader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("reading YUV4MPEG2 header: %w", err)
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) == 0 || fields[0] != "YUV4MPEG2" {
+		return nil, errY4MHeader
+	}
+
+	y := &Y4MReader{r: br, rateNum: 25, rateDen: 1}
+	colorSpace := "420jpeg"
+
+	for _, field := range fields[1:] {
+		value := field[1:]
+		switch field[0] {
+		case 'W':
+			y.width, err = strconv.Atoi(value)
+		case 'H':
+			y.height, err = strconv.Atoi(value)
+		case 'F':
+			y.rateNum, y.rateDen, err = parseRatio(value)
+		case 'C':
+			colorSpace = value
+		}
+
+		if err != nil {
+			return nil, errY4MHeader
+		}
+	}
+
+	if y.width <= 0 || y.height <= 0 || y.rateNum <= 0 || y.rateDen <= 0 {
+		return nil, errY4MHeader
+	}
+
+	halfWidth, halfHeight := (y.width+1)/2, (y.height+1)/2
+	switch colorSpace {
+	case "420", "420jpeg", "420paldv", "420mpeg2":
+		y.chroma = 2 * halfWidth * halfHeight
+	case "422":
+		y.chroma = 2 * halfWidth * y.height
+	case "444":
+		y.chroma = 2 * y.width * y.height
+	case "411":
+		y.chroma = 2 * ((y.width + 3) / 4) * y.height
+	case "mono":
+		y.chroma = 0
+	default:
+		return nil, fmt.Errorf("unsupported YUV4MPEG2 colour space %s", colorSpace)
+	}
+
+	return y, nil
+}
+
+// parseRatio parses a ratio such as 30000:1001
+func parseRatio(s string) (int64, int64, error) {
+	num, den, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, errY4MHeader
+	}
+
+	// 32 bits, so that the nanoseconds of a fraction of a second don't overflow
+	n, err := strconv.ParseInt(num, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	d, err := strconv.ParseInt(den, 10, 32)
+	return n, d, err
+}
+
+// ReadFrame reads the next frame, seen at its number divided by the frame rate
+func (y *Y4MReader) ReadFrame() (image.Image, time.Duration, error) {
+	// every frame starts with FRAME and parameters of its own, which are ignored
+	header, err := y.r.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) && header == "" {
+			return nil, 0, io.EOF
+		}
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	if !strings.HasPrefix(header, "FRAME") {
+		return nil, 0, errors.New("invalid YUV4MPEG2 frame header")
+	}
+
+	frame := image.NewGray(image.Rect(0, 0, y.width, y.height))
+	if _, err := io.ReadFull(y.r, frame.Pix); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	if _, err := y.r.Discard(y.chroma); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	// in whole seconds and the rest, as the frame number times a second overflows after a few hours
+	ticks := y.frames * y.rateDen
+	at := time.Duration(ticks/y.rateNum)*time.Second + time.Duration(ticks%y.rateNum*int64(time.Second)/y.rateNum)
+	y.frames++
+
+	return frame, at, nil
+}
@@ -0,0 +1,86 @@
+package ar8t
+
+// transform is a projective transformation (homography) between two planes,
+// stored as a row major 3x3 matrix applied to (x, y, 1)
+type transform [3][3]float64
+
+// newTransform returns the transformation mapping each point in from to
+// the point at the same index in to
+func newTransform(from, to [4]Point) transform {
+	return squareToQuad(to).times(squareToQuad(from).adjoint())
+}
+
+// squareToQuad maps the unit square (0,0), (1,0), (1,1), (0,1) onto q
+func squareToQuad(q [4]Point) transform {
+	dx3 := q[0].X - q[1].X + q[2].X - q[3].X
+	dy3 := q[0].Y - q[1].Y + q[2].Y - q[3].Y
+
+	if dx3 == 0 && dy3 == 0 {
+		// affine
+		return transform{
+			{q[1].X - q[0].X, q[2].X - q[1].X, q[0].X},
+			{q[1].Y - q[0].Y, q[2].Y - q[1].Y, q[0].Y},
+			{0, 0, 1},
+		}
+	}
+
+	var (
+		dx1 = q[1].X - q[2].X
+		dx2 = q[3].X - q[2].X
+		dy1 = q[1].Y - q[2].Y
+		dy2 = q[3].Y - q[2].Y
+
+		den = dx1*dy2 - dx2*dy1
+		g   = (dx3*dy2 - dx2*dy3) / den
+		h   = (dx1*dy3 - dx3*dy1) / den
+	)
+
+	return transform{
+		{q[1].X - q[0].X + g*q[1].X, q[3].X - q[0].X + h*q[3].X, q[0].X},
+		{q[1].Y - q[0].Y + g*q[1].Y, q[3].Y - q[0].Y + h*q[3].Y, q[0].Y},
+		{g, h, 1},
+	}
+}
+
+// adjoint is the inverse of t up to a scale factor, which is all
+// a projective transformation needs
+func (t transform) adjoint() transform {
+	return transform{
+		{
+			t[1][1]*t[2][2] - t[1][2]*t[2][1],
+			t[0][2]*t[2][1] - t[0][1]*t[2][2],
+			t[0][1]*t[1][2] - t[0][2]*t[1][1],
+		},
+		{
+			t[1][2]*t[2][0] - t[1][0]*t[2][2],
+			t[0][0]*t[2][2] - t[0][2]*t[2][0],
+			t[0][2]*t[1][0] - t[0][0]*t[1][2],
+		},
+		{
+			t[1][0]*t[2][1] - t[1][1]*t[2][0],
+			t[0][1]*t[2][0] - t[0][0]*t[2][1],
+			t[0][0]*t[1][1] - t[0][1]*t[1][0],
+		},
+	}
+}
+
+func (t transform) times(other transform) transform {
+	res := transform{}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				res[i][j] += t[i][k] * other[k][j]
+			}
+		}
+	}
+
+	return res
+}
+
+func (t transform) Apply(p Point) Point {
+	w := t[2][0]*p.X + t[2][1]*p.Y + t[2][2]
+	return Point{
+		X: (t[0][0]*p.X + t[0][1]*p.Y + t[0][2]) / w,
+		Y: (t[1][0]*p.X + t[1][1]*p.Y + t[1][2]) / w,
+	}
+}
@@ -0,0 +1,122 @@
+package ar8t
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/mrg0lden/ar8t/internal/corpus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newTransform(t *testing.T) {
+	square := [4]Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+
+	tests := []struct {
+		name     string
+		from, to [4]Point
+	}{
+		{"identity", square, square},
+		{"parallelogram", square, [4]Point{{10, 20}, {110, 40}, {90, 140}, {-10, 120}}},
+		{"trapezoid", [4]Point{{3.5, 3.5}, {17.5, 3.5}, {21, 21}, {3.5, 17.5}}, [4]Point{{40, 30}, {160, 45}, {170, 190}, {35, 150}}},
+		{"quad to quad", [4]Point{{5, 5}, {95, 10}, {100, 90}, {0, 100}}, [4]Point{{200, 10}, {250, 200}, {20, 230}, {-30, 40}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forward := newTransform(tt.from, tt.to)
+			backward := newTransform(tt.to, tt.from)
+
+			for i := range tt.from {
+				got := forward.Apply(tt.from[i])
+				assert.InDelta(t, tt.to[i].X, got.X, 1e-9)
+				assert.InDelta(t, tt.to[i].Y, got.Y, 1e-9)
+
+				got = backward.Apply(got)
+				assert.InDelta(t, tt.from[i].X, got.X, 1e-9)
+				assert.InDelta(t, tt.from[i].Y, got.Y, 1e-9)
+			}
+
+			// the middle of from goes anywhere, but back to where it was
+			middle := tt.from[0].Add(tt.from[1]).Add(tt.from[2]).Add(tt.from[3]).Div(4)
+			got := backward.Apply(forward.Apply(middle))
+			assert.InDelta(t, middle.X, got.X, 1e-9)
+			assert.InDelta(t, middle.Y, got.Y, 1e-9)
+		})
+	}
+}
+
+// perspectiveImage draws modules with their corners at corners, clockwise from the top left,
+// on a white image of side pixels. Every pixel is the mean of 4 x 4 samples.
+func perspectiveImage(modules BitMatrix, corners [4]Point, side int) *image.Gray {
+	const samples = 4
+
+	width, height := float64(modules.Width()), float64(modules.Height())
+	toModules := newTransform(corners, [4]Point{{0, 0}, {width, 0}, {width, height}, {0, height}})
+
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			light := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					m := toModules.Apply(Point{float64(x) + (float64(sx)+0.5)/samples, float64(y) + (float64(sy)+0.5)/samples})
+					mx, my := int(m.X+width)-int(width), int(m.Y+height)-int(height)
+					if !modules.in(mx, my) || !modules.Get(mx, my) {
+						light++
+					}
+				}
+			}
+			img.SetGray(x, y, color.Gray{uint8(255 * light / (samples * samples))})
+		}
+	}
+
+	return img
+}
+
+func Test_estimateCorners(t *testing.T) {
+	symbols, err := corpus.Symbols()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// version 1, with its bottom right corner pulled in as if the top left were closer
+	symbol := symbols[0]
+	modules := NewBitMatrix(len(symbol.Modules), len(symbol.Modules))
+	for y, row := range symbol.Modules {
+		for x, dark := range row {
+			modules.Set(x, y, dark)
+		}
+	}
+	corners := [4]Point{{30, 30}, {150, 30}, {145, 150}, {35, 150}}
+	img := perspectiveImage(modules, corners, 180)
+
+	prepared := NewBlockedMean(3, 7).Prepare(img)
+	locations := LineScan{}.Detect(prepared)
+	if !assert.Len(t, locations, 1) {
+		return
+	}
+	loc := locations[0]
+
+	const size = 21
+	dx := loc.TopRight.Sub(loc.TopLeft).Div(size - 7)
+	dy := loc.BottomLeft.Sub(loc.TopLeft).Div(size - 7)
+
+	transform, ok := estimateCorners(prepared, size, loc, dx, dy)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	// pixel centres are half a pixel off the corners of pixels
+	want := corners[2].Sub(Point{0.5, 0.5})
+	got := transform.Apply(Point{size, size})
+	assert.InDelta(t, want.X, got.X, 1.5)
+	assert.InDelta(t, want.Y, got.Y, 1.5)
+
+	// far enough off the parallelogram of the finders for version 1 to sample with the estimate
+	assert.Greater(t, distance(got, affineCorner(loc, dx, dy)), distance(Point{}, dx)/2)
+
+	results, err := DefaultDecoder{}.DecodeResults(img)
+	if assert.NoError(t, err) && assert.Len(t, results, 1) {
+		assert.Equal(t, symbol.Text, string(results[0].Data))
+	}
+}
@@ -13,28 +13,19 @@ type QRLocation struct {
 }
 
 type QRData struct {
-	/// QR Pixel Data in side x side pixels, stored in row major order. Using the provided index will convert into 1's and 0's.
-	Data []byte
+	/// QR modules in side x side modules, or width x height for rMQR Codes, dark modules being set
+	Modules BitMatrix
 
-	/// Version of the QR Code, 1 being the smallest, 40 the largest
+	/// Version of the QR Code, 1 being the smallest, 40 the largest. M1 to M4 are 1 to 4 for Micro QR Codes,
+	/// R7x43 to R17x139 are 1 to 32 for rMQR Codes
 	Version uint32
 
-	/// Side in pixels of the QR square
+	/// Side in pixels of the QR square, 0 for rMQR Codes
 	Side uint32
-}
-
-func (d QRData) Index(x, y uint32) byte {
-	index := y*d.Side + x
-	if int(index) >= len(d.Data) {
-		return 1
-	}
 
-	switch d.Data[index] {
-	case 0:
-		return 1
-	default:
-		return 0
-	}
+	/// Confidence of every module, in row major order, from 0 (a guess) to 1 (certain).
+	/// Only filled by soft-decision extraction, nil otherwise
+	Confidence []float64
 }
 
 type ECLevel int
@@ -46,6 +37,20 @@ const (
 	ECLevelHigh
 )
 
+func (l ECLevel) String() string {
+	switch l {
+	case ECLevelLow:
+		return "L"
+	case ECLevelMedium:
+		return "M"
+	case ECLevelQuartile:
+		return "Q"
+	case ECLevelHigh:
+		return "H"
+	}
+	return "unknown"
+}
+
 type Chomp struct {
 	bytes            *bytes.Reader
 	bitsLeft         uint32
@@ -155,6 +160,7 @@ func (ch *Chomp) nibble(nBits uint8) (result byte, ok bool) {
 	ch.currentByte <<= nBits
 
 	ch.bitsLeftInByte -= nBits
+	ch.bitsLeft -= uint32(nBits)
 
 	ok = true
 	return
@@ -0,0 +1,55 @@
+package ar8t
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Chomp(t *testing.T) {
+	type chomp struct {
+		nBits uint8
+		want  byte
+		ok    bool
+	}
+	tests := []struct {
+		name   string
+		input  []byte
+		chomps []chomp
+	}{
+		{
+			name:   "within a byte",
+			input:  []byte{0b1011_0010},
+			chomps: []chomp{{3, 0b101, true}, {2, 0b10, true}, {3, 0b010, true}, {1, 0, false}},
+		},
+		{
+			name:   "across bytes",
+			input:  []byte{0b1011_0010, 0b1110_0001},
+			chomps: []chomp{{4, 0b1011, true}, {8, 0b0010_1110, true}, {4, 0b0001, true}, {1, 0, false}},
+		},
+		{
+			name:   "past the end",
+			input:  []byte{0b1011_0010, 0b1110_0001},
+			chomps: []chomp{{5, 0b10110, true}, {7, 0b010_1110, true}, {5, 0, false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := NewChomp(tt.input)
+			bitsLeft := len(tt.input) * 8
+
+			for i, c := range tt.chomps {
+				got, ok := ch.Chomp(c.nBits)
+				assert.Equal(t, c.ok, ok, "chomp %d", i)
+				if !ok {
+					continue
+				}
+
+				bitsLeft -= int(c.nBits)
+				assert.Equal(t, c.want, got, "chomp %d", i)
+				// bits chomped within a byte count towards bitsLeft too
+				assert.Equal(t, uint32(bitsLeft), ch.bitsLeft, "chomp %d", i)
+			}
+		})
+	}
+}